@@ -0,0 +1,181 @@
+package array
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// Allocator provides the storage of new arrays.
+type Allocator interface {
+	// Alloc returns a slice of n zeros.
+	Alloc(n int) []float64
+
+	// Free hands back a slice returned by Alloc, which must no longer
+	// be used, for reuse.  It is only a hint: slices that are never
+	// freed are reclaimed by the garbage collector.
+	Free(data []float64)
+}
+
+// HeapAllocator allocates storage with make, leaving its release to the
+// garbage collector.  It is the default allocator.
+type HeapAllocator struct{}
+
+// Alloc returns a new slice of n zeros.
+func (HeapAllocator) Alloc(n int) []float64 {
+	return make([]float64, n)
+}
+
+// Free does nothing.
+func (HeapAllocator) Free(data []float64) {}
+
+// allocator holds the Allocator set by SetAllocator.
+var allocator atomic.Value
+
+func init() {
+	allocator.Store(allocatorBox{HeapAllocator{}})
+}
+
+// allocatorBox gives every Allocator the same concrete type, as
+// required by atomic.Value.
+type allocatorBox struct {
+	Allocator
+}
+
+// SetAllocator sets the allocator used by NewDense and the functions
+// that return new arrays, and returns the previous allocator.  A nil
+// allocator restores the HeapAllocator.
+func SetAllocator(a Allocator) Allocator {
+	previous := CurrentAllocator()
+	if a == nil {
+		a = HeapAllocator{}
+	}
+	allocator.Store(allocatorBox{a})
+	return previous
+}
+
+// CurrentAllocator returns the allocator used by NewDense.
+func CurrentAllocator() Allocator {
+	return allocator.Load().(allocatorBox).Allocator
+}
+
+// PoolAllocator reuses freed storage, which it keeps in sync.Pools of
+// slices whose capacities are powers of two, so that a slice serves any
+// request between half its capacity and its capacity.  Pooled slices
+// are reclaimed by the garbage collector when unused for a while.  It
+// is safe for concurrent use.
+type PoolAllocator struct {
+	classes [bits.UintSize]sync.Pool
+}
+
+// NewPoolAllocator returns an empty PoolAllocator.
+func NewPoolAllocator() *PoolAllocator {
+	return &PoolAllocator{}
+}
+
+// sizeClass returns the index of the smallest power of two that is at
+// least n.
+func sizeClass(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// Alloc returns a slice of n zeros, reusing freed storage if possible.
+func (p *PoolAllocator) Alloc(n int) []float64 {
+	if n == 0 {
+		return []float64{}
+	}
+	class := sizeClass(n)
+	if v := p.classes[class].Get(); v != nil {
+		data := (*v.(*[]float64))[:n]
+		for i := range data {
+			data[i] = 0
+		}
+		return data
+	}
+	return make([]float64, n, 1<<uint(class))
+}
+
+// Free puts data back in the pool.  Slices whose capacity is not a
+// power of two, which were not returned by Alloc, are ignored.
+func (p *PoolAllocator) Free(data []float64) {
+	c := cap(data)
+	if c == 0 || c&(c-1) != 0 {
+		return
+	}
+	data = data[:c]
+	p.classes[sizeClass(c)].Put(&data)
+}
+
+// Arena allocates storage from large blocks that are released together
+// by Reset, so that the temporaries of a computation cost a few
+// allocations instead of one per array.  It is safe for concurrent use.
+type Arena struct {
+	mu        sync.Mutex
+	blockSize int
+	blocks    [][]float64
+	block     int
+	used      int
+}
+
+// DefaultArenaBlockSize is the number of items in the blocks of an
+// Arena created with a non-positive block size.
+const DefaultArenaBlockSize = 1 << 16
+
+// NewArena returns an Arena whose blocks hold blockSize items.
+// Requests for more items than that get a block of their own.
+func NewArena(blockSize int) *Arena {
+	if blockSize <= 0 {
+		blockSize = DefaultArenaBlockSize
+	}
+	return &Arena{blockSize: blockSize}
+}
+
+// Alloc returns a slice of n zeros carved from the current block.
+func (a *Arena) Alloc(n int) []float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for ; a.block < len(a.blocks); a.block++ {
+		if b := a.blocks[a.block]; len(b)-a.used >= n {
+			data := b[a.used : a.used+n : a.used+n]
+			a.used += n
+			for i := range data {
+				data[i] = 0
+			}
+			return data
+		}
+		a.used = 0
+	}
+	size := a.blockSize
+	if n > size {
+		size = n
+	}
+	a.blocks = append(a.blocks, make([]float64, size))
+	a.used = n
+	return a.blocks[a.block][:n:n]
+}
+
+// Free does nothing; storage is released by Reset.
+func (a *Arena) Free(data []float64) {}
+
+// Reset makes the storage of every array allocated from the arena
+// available again.  Those arrays must no longer be used.
+func (a *Arena) Reset() {
+	a.mu.Lock()
+	a.block = 0
+	a.used = 0
+	a.mu.Unlock()
+}
+
+// Release returns the blocks of the arena to the garbage collector.  As
+// with Reset, the arrays allocated from the arena must no longer be
+// used.
+func (a *Arena) Release() {
+	a.mu.Lock()
+	a.blocks = nil
+	a.block = 0
+	a.used = 0
+	a.mu.Unlock()
+}
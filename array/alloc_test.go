@@ -0,0 +1,84 @@
+package array_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestSetAllocator(t *testing.T) {
+	arena := array.NewArena(16)
+	previous := array.SetAllocator(arena)
+	defer array.SetAllocator(previous)
+	assert.Equal(t, array.HeapAllocator{}, previous)
+	assert.Equal(t, arena, array.CurrentAllocator())
+
+	d, err := array.NewDense(array.Shape{2, 3}, array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	out, err := array.Map(d, func(v float64) float64 { return v + 1 })
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []float64{1, 1, 1, 1, 1, 1}, out.Values())
+	// Both arrays were carved from the first block.
+	arena.Reset()
+	block := arena.Alloc(16)
+	assert.Same(t, &block[0], &d.Data[0])
+	assert.Same(t, &block[6], &out.Data[0])
+
+	assert.Equal(t, arena, array.SetAllocator(nil))
+	assert.Equal(t, array.HeapAllocator{}, array.CurrentAllocator())
+}
+
+func TestPoolAllocator(t *testing.T) {
+	pool := array.NewPoolAllocator()
+	a := pool.Alloc(5)
+	assert.Equal(t, 5, len(a))
+	assert.Equal(t, 8, cap(a))
+	for i := range a {
+		a[i] = 1
+	}
+	pool.Free(a)
+	// A pool may drop freed slices at any time, but whatever it returns
+	// is cleared.
+	b := pool.Alloc(7)
+	assert.Equal(t, []float64{0, 0, 0, 0, 0, 0, 0}, b)
+	assert.Equal(t, 8, cap(b))
+	assert.Equal(t, []float64{}, pool.Alloc(0))
+	pool.Free(make([]float64, 3))
+
+	d, err := array.NewDenseWithAllocator(array.Shape{4, 4},
+		array.DefaultAttributes, pool)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 16, len(d.Data))
+		assert.Equal(t, 16, cap(d.Data))
+	}
+}
+
+func TestArena(t *testing.T) {
+	arena := array.NewArena(8)
+	a := arena.Alloc(3)
+	assert.Equal(t, 3, cap(a))
+	b := arena.Alloc(5)
+	c := arena.Alloc(20)
+	assert.Equal(t, 20, len(c))
+	for i := range b {
+		b[i] = 2
+	}
+
+	arena.Reset()
+	d := arena.Alloc(8)
+	assert.Same(t, &a[0], &d[0])
+	assert.Same(t, &b[0], &d[3])
+	assert.Equal(t, make([]float64, 8), d)
+	e := arena.Alloc(10)
+	assert.Same(t, &c[0], &e[0])
+
+	arena.Release()
+	f := arena.Alloc(1)
+	assert.NotSame(t, &a[0], &f[0])
+}
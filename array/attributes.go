@@ -19,6 +19,10 @@ const (
 	// ColumnMajorLayout defines an array with elements disposed
 	// column-by-column in a contiguous single-segment, memory layout.
 	ColumnMajorLayout
+
+	// ComplexItems indicates whether the last axis of the array holds
+	// the real and imaginary parts of complex items.
+	ComplexItems
 )
 
 // DefaultAttributes can be used to set normally used attributes for a
@@ -48,5 +52,8 @@ func (a Attributes) String() string {
 	if a.Is(ColumnMajorLayout) {
 		attrs = append(attrs, "ColumnMajorLayout")
 	}
+	if a.Is(ComplexItems) {
+		attrs = append(attrs, "ComplexItems")
+	}
 	return strings.Join(attrs, ", ")
 }
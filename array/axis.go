@@ -0,0 +1,119 @@
+package array
+
+import "context"
+
+// normalizeAxis returns axis as a non-negative index, counting from the
+// last dimension when axis is negative.
+func normalizeAxis(axis, ndim int) (int, error) {
+	if axis < -ndim || axis >= ndim {
+		return 0, &AxisError{Axis: axis, NDim: ndim}
+	}
+	if axis < 0 {
+		return axis + ndim, nil
+	}
+	return axis, nil
+}
+
+// laneOffsets returns the position in Data of the first item of every
+// one-dimensional lane along axis, in row-major order of the remaining
+// axes.
+func (d *Dense) laneOffsets(axis int) []int {
+	shape := append(Shape{}, d.Shape...)
+	shape[axis] = 1
+	view := &Dense{
+		Data:    d.Data,
+		DType:   d.DType,
+		Shape:   shape,
+		Strides: d.Strides,
+	}
+	offsets := make([]int, 0, view.Size())
+	view.forEach(func(pos int) {
+		offsets = append(offsets, pos)
+	})
+	return offsets
+}
+
+// ApplyAlongAxis calls fn for every one-dimensional lane of d along
+// axis and returns the collected results.  The result has the shape of
+// d with the length of axis replaced by n, and fn receives a dst slice
+// of length n to be filled from the lane items in src.  Negative axes
+// count from the last dimension.  Lanes are split among goroutines when
+// there is enough work, so fn may be called concurrently and must not
+// retain its arguments.
+func ApplyAlongAxis(
+	d *Dense, axis, n int, fn func(dst, src []float64),
+) (*Dense, error) {
+	return ApplyAlongAxisContext(context.Background(), d, axis, n, fn)
+}
+
+// ApplyAlongAxisContext is like ApplyAlongAxis, but stops early and
+// returns an error wrapping the error of ctx once it is done.
+func ApplyAlongAxisContext(
+	ctx context.Context, d *Dense, axis, n int, fn func(dst, src []float64),
+) (*Dense, error) {
+	axis, err := normalizeAxis(axis, len(d.Shape))
+	if err != nil {
+		return nil, err
+	}
+	shape := append(Shape{}, d.Shape...)
+	shape[axis] = n
+	out, err := NewDense(shape, DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	itemSize := d.DType.Size()
+	inStride := d.Strides[axis] / itemSize
+	outStride := out.Strides[axis] / itemSize
+	inOffsets := d.laneOffsets(axis)
+	outOffsets := out.laneOffsets(axis)
+	err = parallelFor(ctx, len(inOffsets), 1, d.Shape[axis]+n, func(lo, hi int) {
+		src := make([]float64, d.Shape[axis])
+		dst := make([]float64, n)
+		for lane := lo; lane < hi; lane++ {
+			base := inOffsets[lane]
+			for k := range src {
+				src[k] = d.load(base + k*inStride)
+			}
+			fn(dst, src)
+			base = outOffsets[lane]
+			for k, v := range dst {
+				out.Data[base+k*outStride] = v
+			}
+		}
+	})
+	if err != nil {
+		return nil, canceled(ctx, "apply along axis")
+	}
+	return out, nil
+}
+
+// ReduceAlongAxis calls fn for every one-dimensional lane of d along
+// axis and returns the collected results in an array with the shape of
+// d without axis.  Negative axes count from the last dimension.  As in
+// ApplyAlongAxis, fn may be called concurrently.
+func ReduceAlongAxis(
+	d *Dense, axis int, fn func(src []float64) float64,
+) (*Dense, error) {
+	return ReduceAlongAxisContext(context.Background(), d, axis, fn)
+}
+
+// ReduceAlongAxisContext is like ReduceAlongAxis, but stops early and
+// returns an error wrapping the error of ctx once it is done.
+func ReduceAlongAxisContext(
+	ctx context.Context, d *Dense, axis int, fn func(src []float64) float64,
+) (*Dense, error) {
+	axis, err := normalizeAxis(axis, len(d.Shape))
+	if err != nil {
+		return nil, err
+	}
+	out, err := ApplyAlongAxisContext(ctx, d, axis, 1, func(dst, src []float64) {
+		dst[0] = fn(src)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// An axis of length one can be dropped without moving items.
+	out.Shape = append(out.Shape[:axis:axis], out.Shape[axis+1:]...)
+	out.Strides = append(out.Strides[:axis:axis], out.Strides[axis+1:]...)
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package array
+
+// BroadcastShapes returns the shape resulting from broadcasting the
+// given shapes together.  Shapes are aligned on their last dimension,
+// and dimensions of size one are stretched to match the others.
+func BroadcastShapes(shapes ...Shape) (Shape, error) {
+	nd := 0
+	for _, s := range shapes {
+		if len(s) > nd {
+			nd = len(s)
+		}
+	}
+	out := make(Shape, nd)
+	for i := range out {
+		out[i] = 1
+	}
+	for _, s := range shapes {
+		offset := nd - len(s)
+		for i, dim := range s {
+			switch {
+			case dim == out[offset+i] || dim == 1:
+			case out[offset+i] == 1:
+				out[offset+i] = dim
+			default:
+				return nil, ErrBroadcast
+			}
+		}
+	}
+	return out, nil
+}
+
+// BroadcastTo returns a read-only view of the array with the given
+// shape, sharing the data of d.  Stretched dimensions have a zero
+// stride.
+func (d *Dense) BroadcastTo(shape Shape) (*Dense, error) {
+	if err := shape.Validate(); err != nil {
+		return nil, err
+	}
+	offset := len(shape) - len(d.Shape)
+	if offset < 0 {
+		return nil, ErrBroadcast
+	}
+	strides := make(Strides, len(shape))
+	for i, dim := range d.Shape {
+		switch {
+		case dim == shape[offset+i]:
+			strides[offset+i] = d.Strides[i]
+		case dim == 1:
+			strides[offset+i] = 0
+		default:
+			return nil, ErrBroadcast
+		}
+	}
+	attrs := d.Attrs &^ (Writeable | Contiguous)
+	if shape.equal(d.Shape) {
+		attrs = d.Attrs &^ Writeable
+	}
+	return &Dense{
+		Data:    d.Data,
+		DType:   d.DType,
+		Shape:   append(Shape{}, shape...),
+		Strides: strides,
+		Attrs:   attrs,
+	}, nil
+}
+
+func (s Shape) equal(other Shape) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if s[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package array
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Bytes returns the memory holding the items of d, shared with Data,
+// in the byte order of its data-type.  Writing to the slice changes
+// the items of d.
+func (d *Dense) Bytes() []byte {
+	if len(d.Data) == 0 {
+		return []byte{}
+	}
+	var b []byte
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	header.Data = uintptr(unsafe.Pointer(&d.Data[0]))
+	header.Len = len(d.Data) * d.DType.Size()
+	header.Cap = header.Len
+	return b
+}
+
+// FromBytes returns an array with the given shape whose items are the
+// bytes of b, without copying them.  The items are laid out as defined
+// by attrs, as in NewDense, and stored in the byte order of dtype.  The
+// first byte of b must be aligned for a float64, as the slices returned
+// by make are.
+func FromBytes(b []byte, shape Shape, dtype DType, attrs Attributes) (*Dense, error) {
+	if !dtype.valid() {
+		return nil, &Error{Operation: "from bytes", Message: "invalid dtype"}
+	}
+	strides, err := NewStrides(shape, dtype, attrs)
+	if err != nil {
+		return nil, err
+	}
+	n, err := shape.Size()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != n*dtype.Size() {
+		return nil, ErrInvalidValuesLength
+	}
+	d := &Dense{
+		Data:    []float64{},
+		DType:   dtype,
+		Shape:   shape,
+		Strides: strides,
+		Attrs:   attrs,
+	}
+	if n == 0 {
+		return d, nil
+	}
+	p := unsafe.Pointer(&b[0])
+	if uintptr(p)%unsafe.Alignof(float64(0)) != 0 {
+		return nil, &Error{Operation: "from bytes", Message: "misaligned buffer"}
+	}
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&d.Data))
+	header.Data = uintptr(p)
+	header.Len = n
+	header.Cap = n
+	return d, nil
+}
+
+// Frombuffer returns a one-dimensional, writeable array whose items
+// are the bytes of b, stored in the byte order of dtype, without
+// copying them.  As in FromBytes, b must be aligned for a float64.
+func Frombuffer(b []byte, dtype DType) (*Dense, error) {
+	if !dtype.valid() {
+		return nil, &Error{Operation: "from bytes", Message: "invalid dtype"}
+	}
+	if len(b)%dtype.Size() != 0 {
+		return nil, ErrInvalidValuesLength
+	}
+	return FromBytes(b, Shape{len(b) / dtype.Size()}, dtype, DefaultAttributes)
+}
+
+// ByteSwap reverses the bytes of every item of d in place, and switches
+// its data-type to the opposite byte order, so that the values of the
+// items are unchanged.  Use it to bring an array to the byte order of
+// the machine, or to prepare its Bytes for a machine of the other
+// order.
+func (d *Dense) ByteSwap() error {
+	if !d.Attrs.Is(Writeable) {
+		return ErrNotWriteable
+	}
+	for i, v := range d.Data {
+		d.Data[i] = swapBytes(v)
+	}
+	if d.DType.explicit() == LittleEndianFloat64 {
+		d.DType = BigEndianFloat64
+	} else {
+		d.DType = LittleEndianFloat64
+	}
+	if d.DType.Native() {
+		d.DType = Float64
+	}
+	return nil
+}
+
+// View returns an array sharing the memory of d whose items are
+// interpreted as dtype.  Since every data-type has the size of a
+// float64, the view has the shape and strides of d; viewing an array
+// with a data-type of the other byte order reinterprets the bytes of
+// its items.
+func (d *Dense) View(dtype DType) (*Dense, error) {
+	if !dtype.valid() {
+		return nil, &Error{Operation: "view", Message: "invalid dtype"}
+	}
+	return &Dense{
+		Data:    d.Data,
+		DType:   dtype,
+		Shape:   append(Shape{}, d.Shape...),
+		Strides: append(Strides{}, d.Strides...),
+		Attrs:   d.Attrs,
+	}, nil
+}
+
+// load returns the value of the item at position pos in Data.
+func (d *Dense) load(pos int) float64 {
+	if d.DType.Native() {
+		return d.Data[pos]
+	}
+	return swapBytes(d.Data[pos])
+}
+
+// store sets the value of the item at position pos in Data.
+func (d *Dense) store(pos int, v float64) {
+	if d.DType.Native() {
+		d.Data[pos] = v
+	} else {
+		d.Data[pos] = swapBytes(v)
+	}
+}
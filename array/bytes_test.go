@@ -0,0 +1,101 @@
+package array_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestDTypeByteOrder(t *testing.T) {
+	assert.Equal(t, 8, array.LittleEndianFloat64.Size())
+	assert.Equal(t, binary.BigEndian, array.BigEndianFloat64.ByteOrder())
+	assert.True(t, array.Float64.Native())
+	assert.NotEqual(t,
+		array.LittleEndianFloat64.Native(), array.BigEndianFloat64.Native())
+}
+
+func TestFromBytes(t *testing.T) {
+	// An instrument dump of big-endian items, read without copying.
+	b := make([]byte, 6*8)
+	for i := 0; i < 6; i++ {
+		binary.BigEndian.PutUint64(b[8*i:], math.Float64bits(float64(i)/2))
+	}
+	d, err := array.FromBytes(b, array.Shape{2, 3}, array.BigEndianFloat64,
+		array.Contiguous|array.Writeable|array.RowMajorLayout)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []float64{0, 0.5, 1, 1.5, 2, 2.5}, d.Values())
+	v, _ := d.Get(array.Indices{1, 0})
+	assert.Equal(t, 1.5, v)
+	out, err := array.Map(d, math.Sqrt)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Float64, out.DType)
+		assert.Equal(t, math.Sqrt(2.5), out.Values()[5])
+	}
+
+	// Writes reach the buffer in its byte order.
+	assert.Nil(t, d.Set(array.Indices{0, 0}, -1))
+	assert.Equal(t, math.Float64bits(-1), binary.BigEndian.Uint64(b))
+	assert.Same(t, &b[0], &d.Bytes()[0])
+
+	_, err = array.FromBytes(b[:40], array.Shape{2, 3}, array.BigEndianFloat64,
+		array.DefaultAttributes)
+	assert.Equal(t, array.ErrInvalidValuesLength, err)
+	_, err = array.FromBytes(b[1:41], array.Shape{5}, array.BigEndianFloat64,
+		array.DefaultAttributes)
+	assert.NotNil(t, err)
+}
+
+func TestFrombuffer(t *testing.T) {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint64(b, math.Float64bits(3))
+	binary.LittleEndian.PutUint64(b[8:], math.Float64bits(4))
+	d, err := array.Frombuffer(b, array.LittleEndianFloat64)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2}, d.Shape)
+		assert.Equal(t, []float64{3, 4}, d.Values())
+	}
+	_, err = array.Frombuffer(b[:12], array.LittleEndianFloat64)
+	assert.Equal(t, array.ErrInvalidValuesLength, err)
+	_, err = array.Frombuffer(b, "f4")
+	assert.NotNil(t, err)
+}
+
+func TestByteSwapAndView(t *testing.T) {
+	d, err := array.NewDenseWithValues(array.Shape{3},
+		[]float64{1, 2, 3}, array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := append([]byte{}, d.Bytes()...)
+	assert.Equal(t, 24, len(raw))
+
+	swapped, err := d.View(array.BigEndianFloat64)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if !array.BigEndianFloat64.Native() {
+		// Reading little-endian bytes as big-endian ones.
+		bits := binary.BigEndian.Uint64(raw)
+		assert.Equal(t, math.Float64frombits(bits), swapped.Values()[0])
+	}
+
+	assert.Nil(t, d.ByteSwap())
+	assert.Equal(t, []float64{1, 2, 3}, d.Values())
+	assert.False(t, d.DType.Native())
+	for i := 0; i < 3; i++ {
+		bits := d.DType.ByteOrder().Uint64(d.Bytes()[8*i:])
+		assert.Equal(t, float64(i+1), math.Float64frombits(bits))
+	}
+	assert.Nil(t, d.ByteSwap())
+	assert.Equal(t, array.Float64, d.DType)
+	assert.Equal(t, raw, d.Bytes())
+
+	_, err = d.View("int64")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,220 @@
+package array
+
+import (
+	"math"
+	"sort"
+)
+
+// Comparisons and logical operations return boolean arrays, whose
+// items are one for true and zero for false.  Logical operations take
+// non-zero items as true, so NaN is true.
+
+// truth returns the boolean item for b.
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func logicalAnd(x, y float64) float64 {
+	return truth(x != 0 && y != 0)
+}
+
+func logicalOr(x, y float64) float64 {
+	return truth(x != 0 || y != 0)
+}
+
+// Equal returns whether the items of a and b, broadcast together, are
+// equal.  NaN is not equal to any value, including NaN.
+func Equal(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth(x == y) })
+}
+
+// NotEqual returns whether the items of a and b, broadcast together,
+// differ.
+func NotEqual(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth(x != y) })
+}
+
+// Less returns whether the items of a are less than those of b,
+// broadcast together.
+func Less(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth(x < y) })
+}
+
+// LessEqual returns whether the items of a are less than or equal to
+// those of b, broadcast together.
+func LessEqual(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth(x <= y) })
+}
+
+// Greater returns whether the items of a are greater than those of b,
+// broadcast together.
+func Greater(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth(x > y) })
+}
+
+// GreaterEqual returns whether the items of a are greater than or equal
+// to those of b, broadcast together.
+func GreaterEqual(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth(x >= y) })
+}
+
+// isClose reports whether x is within atol + rtol |y| of y.  Infinities
+// are only close to themselves, and NaN to nothing.
+func isClose(x, y, rtol, atol float64) bool {
+	if math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return x == y
+	}
+	return math.Abs(x-y) <= atol+rtol*math.Abs(y)
+}
+
+// IsClose returns whether the items of a are close to those of b,
+// broadcast together: whether |a - b| <= atol + rtol |b|.  The test is
+// not symmetric, b being the reference.  Infinities are only close to
+// themselves, and NaN to nothing.
+func IsClose(a, b *Dense, rtol, atol float64) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 {
+		return truth(isClose(x, y, rtol, atol))
+	})
+}
+
+// AllClose returns whether all the items of a are close to those of b,
+// broadcast together, as in IsClose.
+func AllClose(a, b *Dense, rtol, atol float64) (bool, error) {
+	shape, err := BroadcastShapes(a.Shape, b.Shape)
+	if err != nil {
+		return false, err
+	}
+	av, _ := a.BroadcastTo(shape)
+	bv, _ := b.BroadcastTo(shape)
+	allClose := true
+	iterate(shape, []*Dense{av, bv}, func(pos []int) {
+		if allClose && !isClose(av.load(pos[0]), bv.load(pos[1]), rtol, atol) {
+			allClose = false
+		}
+	})
+	return allClose, nil
+}
+
+// ArrayEqual returns whether a and b have the same shape and equal
+// items at every index, whatever their memory layouts and data-types.
+// As in Equal, NaN is not equal to NaN.
+func ArrayEqual(a, b *Dense) bool {
+	if !a.Shape.equal(b.Shape) {
+		return false
+	}
+	equal := true
+	iterate(a.Shape, []*Dense{a, b}, func(pos []int) {
+		if equal && a.load(pos[0]) != b.load(pos[1]) {
+			equal = false
+		}
+	})
+	return equal
+}
+
+// IsNaN returns whether the items of d are NaN.
+func IsNaN(d *Dense) (*Dense, error) {
+	return Map(d, func(x float64) float64 { return truth(math.IsNaN(x)) })
+}
+
+// IsInf returns whether the items of d are positive or negative
+// infinities.
+func IsInf(d *Dense) (*Dense, error) {
+	return Map(d, func(x float64) float64 { return truth(math.IsInf(x, 0)) })
+}
+
+// IsFinite returns whether the items of d are neither infinities nor
+// NaN.
+func IsFinite(d *Dense) (*Dense, error) {
+	return Map(d, func(x float64) float64 {
+		return truth(!math.IsInf(x, 0) && !math.IsNaN(x))
+	})
+}
+
+// LogicalAnd returns whether the items of a and b, broadcast together,
+// are both true.
+func LogicalAnd(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, logicalAnd)
+}
+
+// LogicalOr returns whether either of the items of a and b, broadcast
+// together, is true.
+func LogicalOr(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, logicalOr)
+}
+
+// LogicalXor returns whether exactly one of the items of a and b,
+// broadcast together, is true.
+func LogicalXor(a, b *Dense) (*Dense, error) {
+	return Map2(a, b, func(x, y float64) float64 { return truth((x != 0) != (y != 0)) })
+}
+
+// LogicalNot returns whether the items of d are false.
+func LogicalNot(d *Dense) (*Dense, error) {
+	return Map(d, func(x float64) float64 { return truth(x == 0) })
+}
+
+// Any returns whether any item of d is true along the given axes, in
+// an array with the shape of d without these axes.  Negative axes count
+// from the last dimension.  Without axes, all the items of d are
+// tested, giving a zero-dimensional array.
+func Any(d *Dense, axes ...int) (*Dense, error) {
+	return reduceAxes(d, axes, func(values []float64) float64 {
+		for _, v := range values {
+			if v != 0 {
+				return 1
+			}
+		}
+		return 0
+	})
+}
+
+// All returns whether all the items of d are true along the given
+// axes, in an array with the shape of d without these axes.  Negative
+// axes count from the last dimension.  Without axes, all the items of d
+// are tested, giving a zero-dimensional array.
+func All(d *Dense, axes ...int) (*Dense, error) {
+	return reduceAxes(d, axes, func(values []float64) float64 {
+		for _, v := range values {
+			if v == 0 {
+				return 0
+			}
+		}
+		return 1
+	})
+}
+
+// reduceAxes reduces d by fn along each of the given axes, or along all
+// of them when there are none.  Reducing along one axis after the other
+// is only correct for functions, such as any and all, whose result on
+// results is the result on all the items.
+func reduceAxes(d *Dense, axes []int, fn func(values []float64) float64) (*Dense, error) {
+	if len(axes) == 0 {
+		return Scalar(fn(d.Values())), nil
+	}
+	normalized := make([]int, len(axes))
+	for i, axis := range axes {
+		axis, err := normalizeAxis(axis, len(d.Shape))
+		if err != nil {
+			return nil, err
+		}
+		normalized[i] = axis
+	}
+	// Reducing the last axes first keeps the others in place.
+	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
+	for i := 1; i < len(normalized); i++ {
+		if normalized[i] == normalized[i-1] {
+			return nil, &Error{Operation: "reduce", Message: "repeated axis"}
+		}
+	}
+	out := d
+	for _, axis := range normalized {
+		var err error
+		if out, err = ReduceAlongAxis(out, axis, fn); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
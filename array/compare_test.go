@@ -0,0 +1,154 @@
+package array_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestComparisons(t *testing.T) {
+	a, _ := array.NewDenseWithValues(array.Shape{2, 2}, []float64{1, 2, 3, math.NaN()}, array.DefaultAttributes)
+	b, _ := array.NewDenseWithValues(array.Shape{2}, []float64{2, 2}, array.DefaultAttributes)
+	cases := []struct {
+		fn   func(a, b *array.Dense) (*array.Dense, error)
+		want []float64
+	}{
+		{array.Equal, []float64{0, 1, 0, 0}},
+		{array.NotEqual, []float64{1, 0, 1, 1}},
+		{array.Less, []float64{1, 0, 0, 0}},
+		{array.LessEqual, []float64{1, 1, 0, 0}},
+		{array.Greater, []float64{0, 0, 1, 0}},
+		{array.GreaterEqual, []float64{0, 1, 1, 0}},
+	}
+	for _, c := range cases {
+		d, err := c.fn(a, b)
+		if assert.NoError(t, err) {
+			assert.Equal(t, array.Shape{2, 2}, d.Shape)
+			assert.Equal(t, c.want, d.Values())
+		}
+	}
+	c, _ := array.NewDense(array.Shape{3}, array.DefaultAttributes)
+	_, err := array.Equal(a, c)
+	assert.Equal(t, array.ErrBroadcast, err)
+}
+
+func TestIsClose(t *testing.T) {
+	inf := math.Inf(1)
+	a, _ := array.NewDenseWithValues(array.Shape{5}, []float64{1, 100.5, inf, inf, math.NaN()}, array.DefaultAttributes)
+	b, _ := array.NewDenseWithValues(array.Shape{5}, []float64{1.001, 100, inf, -inf, math.NaN()}, array.DefaultAttributes)
+	d, err := array.IsClose(a, b, 1e-2, 1e-3)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{1, 1, 1, 0, 0}, d.Values())
+	}
+	d, err = array.IsClose(a, b, 0, 1e-3)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{1, 0, 1, 0, 0}, d.Values())
+	}
+
+	ok, err := array.AllClose(a, b, 1e-2, 1e-3)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	x, _ := array.NewDenseWithValues(array.Shape{2, 2}, []float64{1, 1.0001, 0.9999, 1}, array.DefaultAttributes)
+	ok, err = array.AllClose(x, array.Scalar(1), 1e-3, 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	_, err = array.AllClose(a, x, 1e-3, 0)
+	assert.Equal(t, array.ErrBroadcast, err)
+}
+
+func TestClassification(t *testing.T) {
+	d, _ := array.NewDenseWithValues(array.Shape{4}, []float64{0, math.NaN(), math.Inf(-1), 2}, array.DefaultAttributes)
+	cases := []struct {
+		fn   func(d *array.Dense) (*array.Dense, error)
+		want []float64
+	}{
+		{array.IsNaN, []float64{0, 1, 0, 0}},
+		{array.IsInf, []float64{0, 0, 1, 0}},
+		{array.IsFinite, []float64{1, 0, 0, 1}},
+		{array.LogicalNot, []float64{1, 0, 0, 0}},
+	}
+	for _, c := range cases {
+		out, err := c.fn(d)
+		if assert.NoError(t, err) {
+			assert.Equal(t, c.want, out.Values())
+		}
+	}
+}
+
+func TestLogical(t *testing.T) {
+	a, _ := array.NewDenseWithValues(array.Shape{4}, []float64{0, 0, 2, -1}, array.DefaultAttributes)
+	b, _ := array.NewDenseWithValues(array.Shape{4}, []float64{0, 1, 0, math.NaN()}, array.DefaultAttributes)
+	cases := []struct {
+		fn   func(a, b *array.Dense) (*array.Dense, error)
+		want []float64
+	}{
+		{array.LogicalAnd, []float64{0, 0, 0, 1}},
+		{array.LogicalOr, []float64{0, 1, 1, 1}},
+		{array.LogicalXor, []float64{0, 1, 1, 0}},
+	}
+	for _, c := range cases {
+		out, err := c.fn(a, b)
+		if assert.NoError(t, err) {
+			assert.Equal(t, c.want, out.Values())
+		}
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	d, _ := array.NewDenseWithValues(array.Shape{2, 3}, []float64{
+		1, 0, 1,
+		1, 0, 0,
+	}, array.DefaultAttributes)
+	out, err := array.Any(d, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{1, 0, 1}, out.Values())
+	}
+	out, err = array.All(d, -1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{2}, out.Shape)
+		assert.Equal(t, []float64{0, 0}, out.Values())
+	}
+	out, err = array.All(d, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{1, 0, 0}, out.Values())
+	}
+	out, err = array.Any(d, 1, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{}, out.Shape)
+		assert.Equal(t, []float64{1}, out.Values())
+	}
+	out, err = array.All(d)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{0}, out.Values())
+	}
+
+	_, err = array.Any(d, 2)
+	assert.Error(t, err)
+	_, err = array.All(d, 1, -1)
+	assert.Error(t, err)
+}
+
+func TestArrayEqual(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6}
+	col, _ := array.NewDenseWithValues(array.Shape{2, 3}, values, array.DefaultAttributes)
+	row, _ := array.NewDenseWithValues(array.Shape{2, 3}, values,
+		array.Contiguous|array.Writeable|array.RowMajorLayout)
+	// The layouts differ, so the data do.
+	assert.NotEqual(t, col.Data, row.Data)
+	assert.True(t, array.ArrayEqual(col, row))
+
+	// Byte order does not matter either.
+	swapped, _ := array.NewDenseWithValues(array.Shape{2, 3}, values, array.DefaultAttributes)
+	assert.NoError(t, swapped.ByteSwap())
+	assert.True(t, array.ArrayEqual(row, swapped))
+
+	assert.NoError(t, row.Set(array.Indices{1, 2}, 7))
+	assert.False(t, array.ArrayEqual(col, row))
+	flat, _ := array.NewDenseWithValues(array.Shape{6}, values, array.DefaultAttributes)
+	assert.False(t, array.ArrayEqual(col, flat))
+	nan := array.Scalar(math.NaN())
+	assert.False(t, array.ArrayEqual(nan, nan))
+}
@@ -0,0 +1,256 @@
+package array
+
+import (
+	"math"
+)
+
+// Complex arrays are stored as Dense arrays of float64 items with a
+// last axis of length two holding the real and imaginary parts of every
+// item, as a complex128 array viewed as float64 in NumPy, and with the
+// ComplexItems attribute.  Arrays made by NewComplex interleave the
+// parts, whatever their layout, so that a complex array of shape s has
+// the Dense shape s + {2} where the last axis has a stride of 8 bytes.
+// Real and Imag are views sharing this memory, obtained from the
+// strides alone.
+//
+// The attribute keeps real arrays whose last axis happens to have two
+// items, such as a list of points, from being read as complex.  Parts
+// loaded from elsewhere are read as complex once the attribute is set
+// on their array.
+
+// IsComplex returns whether d is a complex array, that is, whether it
+// has the ComplexItems attribute and its last axis has two items.
+func IsComplex(d *Dense) bool {
+	return d.Attrs.Is(ComplexItems) &&
+		len(d.Shape) > 0 && d.Shape[len(d.Shape)-1] == 2
+}
+
+// NewComplex returns a new complex array with a given shape whose items
+// are taken from values in row-major order, or zeros if values is nil.
+// The memory layout of the items is defined by the attributes, as in
+// NewDense, and their parts are interleaved.  The attributes of the
+// array are set from its strides: row-major arrays are contiguous, and
+// column-major ones generally have no layout.  The array has the
+// ComplexItems attribute.
+func NewComplex(shape Shape, values []complex128, attrs Attributes) (*Dense, error) {
+	strides, err := NewStrides(shape, Float64, attrs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range strides {
+		strides[i] *= 2
+	}
+	d, err := NewDenseWithStrides(
+		append(append(Shape{}, shape...), 2),
+		append(strides, Float64.Size()), attrs)
+	if err != nil {
+		return nil, err
+	}
+	d.Attrs = attrs&^(Contiguous|RowMajorLayout|ColumnMajorLayout) | ComplexItems
+	for _, layout := range []Attributes{RowMajorLayout, ColumnMajorLayout} {
+		if d.IsContiguous(layout) {
+			d.Attrs |= Contiguous | layout
+			break
+		}
+	}
+	if values == nil {
+		return d, nil
+	}
+	if len(values) != d.Size()/2 {
+		return nil, ErrInvalidValuesLength
+	}
+	re, im := d.parts()
+	i := 0
+	iterate(re.Shape, []*Dense{re, im}, func(pos []int) {
+		re.store(pos[0], real(values[i]))
+		im.store(pos[1], imag(values[i]))
+		i++
+	})
+	return d, nil
+}
+
+// ComplexValues returns a copy of the items of a complex array in
+// row-major order, independently of the memory layout.
+func ComplexValues(d *Dense) ([]complex128, error) {
+	re, err := Real(d)
+	if err != nil {
+		return nil, err
+	}
+	im, _ := Imag(d)
+	values := make([]complex128, 0, re.Size())
+	iterate(re.Shape, []*Dense{re, im}, func(pos []int) {
+		values = append(values, complex(re.load(pos[0]), im.load(pos[1])))
+	})
+	return values, nil
+}
+
+// parts returns the views of the real and imaginary parts of a complex
+// array.
+func (d *Dense) parts() (re, im *Dense) {
+	n := len(d.Shape) - 1
+	view := func(data []float64) *Dense {
+		return &Dense{
+			Data:    data,
+			DType:   d.DType,
+			Shape:   append(Shape{}, d.Shape[:n]...),
+			Strides: append(Strides{}, d.Strides[:n]...),
+			Attrs:   d.Attrs &^ (Contiguous | ComplexItems),
+		}
+	}
+	// Empty arrays have no data to offset.
+	offset := d.Strides[n] / d.DType.Size()
+	if offset > len(d.Data) {
+		offset = len(d.Data)
+	}
+	return view(d.Data), view(d.Data[offset:])
+}
+
+// Real returns a view of the real parts of a complex array, sharing its
+// memory, with the shape of d without its last axis.
+func Real(d *Dense) (*Dense, error) {
+	if !IsComplex(d) {
+		return nil, ErrNotComplex
+	}
+	re, _ := d.parts()
+	return re, nil
+}
+
+// Imag returns a view of the imaginary parts of a complex array,
+// sharing its memory, with the shape of d without its last axis.
+func Imag(d *Dense) (*Dense, error) {
+	if !IsComplex(d) {
+		return nil, ErrNotComplex
+	}
+	_, im := d.parts()
+	return im, nil
+}
+
+// Complex returns a new complex array whose real and imaginary parts
+// are the items of re and im broadcast together.
+func Complex(re, im *Dense) (*Dense, error) {
+	shape, err := BroadcastShapes(re.Shape, im.Shape)
+	if err != nil {
+		return nil, err
+	}
+	out, err := NewComplex(shape, nil, DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*Dense, 2)
+	if views[0], err = re.BroadcastTo(shape); err != nil {
+		return nil, err
+	}
+	if views[1], err = im.BroadcastTo(shape); err != nil {
+		return nil, err
+	}
+	outRe, outIm := out.parts()
+	iterate(shape, []*Dense{outRe, outIm, views[0], views[1]}, func(pos []int) {
+		outRe.Data[pos[0]] = views[0].load(pos[2])
+		outIm.Data[pos[1]] = views[1].load(pos[3])
+	})
+	return out, nil
+}
+
+// FromPolar returns a new complex array whose items have the magnitudes
+// r and the phases theta, in radians, broadcast together.
+func FromPolar(r, theta *Dense) (*Dense, error) {
+	re, err := Map2(r, theta, func(r, theta float64) float64 {
+		return r * math.Cos(theta)
+	})
+	if err != nil {
+		return nil, err
+	}
+	im, err := Map2(r, theta, func(r, theta float64) float64 {
+		return r * math.Sin(theta)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Complex(re, im)
+}
+
+// Conj returns a new complex array holding the complex conjugates of
+// the items of d.
+func Conj(d *Dense) (*Dense, error) {
+	if !IsComplex(d) {
+		return nil, ErrNotComplex
+	}
+	re, im := d.parts()
+	neg, err := Map(im, func(v float64) float64 { return -v })
+	if err != nil {
+		return nil, err
+	}
+	return Complex(re, neg)
+}
+
+// ComplexAbs returns the magnitudes of the items of a complex array, in
+// an array with the shape of d without its last axis.
+func ComplexAbs(d *Dense) (*Dense, error) {
+	if !IsComplex(d) {
+		return nil, ErrNotComplex
+	}
+	re, im := d.parts()
+	return Map2(re, im, math.Hypot)
+}
+
+// ComplexAngle returns the phases, in radians within [-π, π], of the
+// items of a complex array, in an array with the shape of d without its
+// last axis.
+func ComplexAngle(d *Dense) (*Dense, error) {
+	if !IsComplex(d) {
+		return nil, ErrNotComplex
+	}
+	re, im := d.parts()
+	return Map2(im, re, math.Atan2)
+}
+
+// ConjTranspose returns a new complex array holding the Hermitian
+// transpose of a complex matrix, the conjugate of its transpose.
+func ConjTranspose(d *Dense) (*Dense, error) {
+	if !IsComplex(d) || len(d.Shape) != 3 {
+		return nil, ErrNotComplex
+	}
+	// The transpose is a view swapping the strides of the matrix axes.
+	t := &Dense{
+		Data:    d.Data,
+		DType:   d.DType,
+		Shape:   Shape{d.Shape[1], d.Shape[0], 2},
+		Strides: Strides{d.Strides[1], d.Strides[0], d.Strides[2]},
+		Attrs:   d.Attrs &^ (Contiguous | Writeable),
+	}
+	return Conj(t)
+}
+
+// ComplexDot returns the sum of the products of the items of two
+// complex vectors of the same length.
+func ComplexDot(a, b *Dense) (complex128, error) {
+	return complexDot(a, b, false)
+}
+
+// ComplexVdot returns the sum of the products of the complex conjugates
+// of the items of a by the items of b, two complex vectors of the same
+// length.  This is the inner product of complex vectors, and ComplexVdot
+// of a vector with itself is its squared norm.
+func ComplexVdot(a, b *Dense) (complex128, error) {
+	return complexDot(a, b, true)
+}
+
+func complexDot(a, b *Dense, conj bool) (complex128, error) {
+	if !IsComplex(a) || !IsComplex(b) || len(a.Shape) != 2 || len(b.Shape) != 2 {
+		return 0, ErrNotComplex
+	}
+	if a.Shape[0] != b.Shape[0] {
+		return 0, ErrBroadcast
+	}
+	aRe, aIm := a.parts()
+	bRe, bIm := b.parts()
+	var sum complex128
+	iterate(aRe.Shape, []*Dense{aRe, aIm, bRe, bIm}, func(pos []int) {
+		x := complex(aRe.load(pos[0]), aIm.load(pos[1]))
+		if conj {
+			x = complex(real(x), -imag(x))
+		}
+		sum += x * complex(bRe.load(pos[2]), bIm.load(pos[3]))
+	})
+	return sum, nil
+}
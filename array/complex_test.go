@@ -0,0 +1,131 @@
+package array_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestComplexViews(t *testing.T) {
+	values := []complex128{1 + 2i, 3 - 4i, -5 + 6i, 7, 8i, -9 - 1i}
+	for _, attrs := range []array.Attributes{
+		array.DefaultAttributes,
+		array.Contiguous | array.Writeable | array.RowMajorLayout,
+	} {
+		c, err := array.NewComplex(array.Shape{2, 3}, values, attrs)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, array.Shape{2, 3, 2}, c.Shape)
+		// Parts are interleaved, and the attributes follow the strides.
+		assert.Equal(t, 8, c.Strides[2])
+		assert.Equal(t, c.IsContiguous(array.RowMajorLayout), c.Attrs.Is(array.Contiguous))
+		assert.Equal(t, c.IsContiguous(array.ColumnMajorLayout), c.Attrs.Is(array.ColumnMajorLayout))
+		got, err := array.ComplexValues(c)
+		if assert.NoError(t, err) {
+			assert.Equal(t, values, got)
+		}
+
+		re, err := array.Real(c)
+		if !assert.NoError(t, err) {
+			return
+		}
+		im, _ := array.Imag(c)
+		assert.Equal(t, array.Shape{2, 3}, re.Shape)
+		assert.Equal(t, []float64{1, 3, -5, 7, 0, -9}, re.Values())
+		assert.Equal(t, []float64{2, -4, 6, 0, 8, -1}, im.Values())
+
+		// Views share the memory of the complex array.
+		assert.NoError(t, im.Set(array.Indices{1, 0}, 10))
+		got, _ = array.ComplexValues(c)
+		assert.Equal(t, 7+10i, got[3])
+	}
+
+	_, err := array.Real(array.Scalar(1))
+	assert.Equal(t, array.ErrNotComplex, err)
+	_, err = array.NewComplex(array.Shape{2}, values, array.DefaultAttributes)
+	assert.Equal(t, array.ErrInvalidValuesLength, err)
+
+	// Real matrices with two columns are not complex unless marked so.
+	points, _ := array.NewDenseWithValues(array.Shape{2, 2},
+		[]float64{3, 4, 0, -1}, array.Contiguous|array.Writeable|array.RowMajorLayout)
+	assert.False(t, array.IsComplex(points))
+	_, err = array.ComplexAbs(points)
+	assert.Equal(t, array.ErrNotComplex, err)
+	points.Attrs |= array.ComplexItems
+	got, err := array.ComplexValues(points)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []complex128{3 + 4i, -1i}, got)
+	}
+	re, _ := array.Real(points)
+	assert.False(t, re.Attrs.Is(array.ComplexItems))
+}
+
+func TestComplexOperations(t *testing.T) {
+	c, _ := array.NewComplex(array.Shape{3}, []complex128{3 + 4i, -1, -2i}, array.DefaultAttributes)
+
+	conj, err := array.Conj(c)
+	if assert.NoError(t, err) {
+		got, _ := array.ComplexValues(conj)
+		assert.Equal(t, []complex128{3 - 4i, -1, 2i}, got)
+	}
+	abs, err := array.ComplexAbs(c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{5, 1, 2}, abs.Values())
+	}
+	angle, err := array.ComplexAngle(c)
+	if assert.NoError(t, err) {
+		want := []float64{math.Atan2(4, 3), math.Pi, -math.Pi / 2}
+		for i, v := range angle.Values() {
+			assert.InDelta(t, want[i], v, 1e-15)
+		}
+	}
+
+	// Magnitudes broadcast against phases.
+	theta, _ := array.NewDenseWithValues(array.Shape{2}, []float64{0, math.Pi / 2}, array.DefaultAttributes)
+	p, err := array.FromPolar(array.Scalar(2), theta)
+	if assert.NoError(t, err) {
+		got, _ := array.ComplexValues(p)
+		assert.InDelta(t, 2, real(got[0]), 1e-15)
+		assert.InDelta(t, 0, imag(got[0]), 1e-15)
+		assert.InDelta(t, 0, real(got[1]), 1e-15)
+		assert.InDelta(t, 2, imag(got[1]), 1e-15)
+	}
+}
+
+func TestConjTranspose(t *testing.T) {
+	m, _ := array.NewComplex(array.Shape{2, 3}, []complex128{
+		1, 2i, 3,
+		4 + 1i, 5, 6 - 2i,
+	}, array.DefaultAttributes)
+	h, err := array.ConjTranspose(m)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{3, 2, 2}, h.Shape)
+		got, _ := array.ComplexValues(h)
+		assert.Equal(t, []complex128{1, 4 - 1i, -2i, 5, 3, 6 + 2i}, got)
+	}
+	_, err = array.ConjTranspose(array.Scalar(1))
+	assert.Equal(t, array.ErrNotComplex, err)
+}
+
+func TestComplexDot(t *testing.T) {
+	a, _ := array.NewComplex(array.Shape{2}, []complex128{1 + 2i, 3 - 1i}, array.DefaultAttributes)
+	b, _ := array.NewComplex(array.Shape{2}, []complex128{2 - 1i, 1i}, array.DefaultAttributes)
+	dot, err := array.ComplexDot(a, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, (1+2i)*(2-1i)+(3-1i)*1i, dot)
+	}
+	vdot, err := array.ComplexVdot(a, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, (1-2i)*(2-1i)+(3+1i)*1i, vdot)
+	}
+	norm, _ := array.ComplexVdot(a, a)
+	assert.Equal(t, complex(15, 0), norm)
+
+	c, _ := array.NewComplex(array.Shape{3}, nil, array.DefaultAttributes)
+	_, err = array.ComplexDot(a, c)
+	assert.Equal(t, array.ErrBroadcast, err)
+}
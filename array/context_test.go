@@ -0,0 +1,104 @@
+package array_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestMapNContext(t *testing.T) {
+	d := wave(t, array.Shape{200, 1000})
+	ctx, cancel := context.WithCancel(context.Background())
+	out, err := array.MapNContext(ctx, func(args []float64) float64 {
+		return -args[0]
+	}, d)
+	if assert.Nil(t, err) {
+		assert.Equal(t, -d.Values()[12345], out.Values()[12345])
+	}
+	cancel()
+	_, err = array.MapNContext(ctx, func(args []float64) float64 {
+		return -args[0]
+	}, d)
+	assert.True(t, errors.Is(err, context.Canceled))
+	var e *array.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, "map", e.Operation)
+		assert.Equal(t, "map: operation canceled: context canceled", e.Error())
+	}
+}
+
+func TestApplyAlongAxisContext(t *testing.T) {
+	defer array.SetNumThreads(0)
+	array.SetNumThreads(2)
+	d := wave(t, array.Shape{100000, 4})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var lanes int64
+	_, err := array.ReduceAlongAxisContext(ctx, d, 1, func(src []float64) float64 {
+		if atomic.AddInt64(&lanes, 1) == 10 {
+			cancel()
+		}
+		return src[0]
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	// Work stops at the next chunk boundary.
+	assert.Less(t, atomic.LoadInt64(&lanes), int64(100000))
+}
+
+func TestMatMulContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := array.MatMulContext(ctx, wave(t, array.Shape{500, 100}), wave(t, array.Shape{100, 500}))
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestLoadTextContext(t *testing.T) {
+	text := strings.Repeat("1 2 3\n", 5000)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := array.LoadTextContext(ctx, strings.NewReader(text), nil)
+	assert.True(t, errors.Is(err, context.Canceled))
+	var e *array.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, "read text", e.Operation)
+	}
+	_, err = array.GenFromTextContext(ctx, strings.NewReader(text), nil)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// Chunks read before the context is done are kept.
+	ctx, cancel = context.WithCancel(context.Background())
+	r := array.NewTextReader(strings.NewReader(text), nil)
+	d, err := r.ReadContext(ctx, 10)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{10, 3}, d.Shape)
+	}
+	cancel()
+	_, err = r.ReadContext(ctx, 10)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestQuadContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := array.QuadOptions{AbsTol: 1e-14, RelTol: 1e-14, MaxSubintervals: 1000}
+	calls := 0
+	f := func(x float64) float64 {
+		calls++
+		return math.Sin(1 / x)
+	}
+	_, _, err := array.QuadContext(ctx, f, 1e-3, 1, &opts)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 15, calls)
+
+	v, _, err := array.QuadContext(context.Background(), math.Exp, 0, 1, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.E-1, v, 1e-14)
+	}
+}
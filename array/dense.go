@@ -27,7 +27,8 @@ type Dense struct {
 }
 
 // NewDense returns a new float64, multi-dimensional array with a given
-// shape, with elements disposed with the specified strides.
+// shape, with elements disposed with the specified strides.  New arrays
+// are always Writeable, whether attrs has the attribute or not.
 func NewDense(shape Shape, attrs Attributes) (*Dense, error) {
 	newStrides, err := NewStrides(shape, Float64, attrs)
 	if err != nil {
@@ -42,6 +43,25 @@ func NewDense(shape Shape, attrs Attributes) (*Dense, error) {
 // recommended.
 func NewDenseWithStrides(
 	shape Shape, strides Strides, attrs Attributes,
+) (*Dense, error) {
+	return newDense(shape, strides, attrs, CurrentAllocator())
+}
+
+// NewDenseWithAllocator returns a new float64, multi-dimensional array
+// as NewDense does, with storage provided by alloc instead of the
+// allocator set by SetAllocator.
+func NewDenseWithAllocator(
+	shape Shape, attrs Attributes, alloc Allocator,
+) (*Dense, error) {
+	newStrides, err := NewStrides(shape, Float64, attrs)
+	if err != nil {
+		return nil, err
+	}
+	return newDense(shape, newStrides, attrs, alloc)
+}
+
+func newDense(
+	shape Shape, strides Strides, attrs Attributes, alloc Allocator,
 ) (*Dense, error) {
 	var err error
 	err = shape.Validate()
@@ -57,28 +77,36 @@ func NewDenseWithStrides(
 		return nil, err
 	}
 	elements, err = shape.Size()
-	data := make([]float64, elements)
+	if err != nil {
+		return nil, err
+	}
+	data := alloc.Alloc(elements)
 	return &Dense{
 		Data:    data,
 		DType:   Float64,
 		Shape:   shape,
 		Strides: strides,
-		Attrs:   attrs,
-	}, err
+		Attrs:   attrs | Writeable,
+	}, nil
 }
 
-// Fill sets all items in array with start+delta*i for each item i.
+// Fill sets all items in array with start+delta*i for each item i.  It
+// panics for arrays that are not Writeable, such as read-only memory
+// maps, whose items cannot be stored.
 func (d *Dense) Fill(start, delta float64) {
+	if !d.Attrs.Is(Writeable) {
+		panic("array: Fill of an array that is not Writeable")
+	}
 	n := d.Size()
 	if delta == 0.0 {
 		// Assign a constant scalar for all values.
 		for i := 0; i < n; i++ {
-			d.Data[i] = start
+			d.store(i, start)
 		}
 	} else {
 		// Assign stepped scalar for all values.
 		for i := 0; i < n; i++ {
-			d.Data[i] = start + float64(i)*delta
+			d.store(i, start+float64(i)*delta)
 		}
 	}
 }
@@ -89,16 +117,20 @@ func (d *Dense) Get(indices Indices) (float64, error) {
 	if err != nil {
 		return math.NaN(), err
 	}
-	return d.Data[offset], nil
+	return d.load(offset), nil
 }
 
-// Set replaces an item at a given position.
+// Set replaces an item at a given position.  It fails for arrays that
+// are not Writeable.
 func (d *Dense) Set(indices Indices, item float64) error {
+	if !d.Attrs.Is(Writeable) {
+		return ErrNotWriteable
+	}
 	offset, err := d.Offset(indices)
 	if err != nil {
 		return err
 	}
-	d.Data[offset] = item
+	d.store(offset, item)
 	return nil
 }
 
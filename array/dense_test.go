@@ -164,3 +164,40 @@ func TestDense2D(t *testing.T) {
 	)
 	assert.ErrorIs(t, err, array.ErrInvalidStridesLength)
 }
+
+func TestDenseValues(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6}
+	for _, layout := range []array.Attributes{
+		array.RowMajorLayout, array.ColumnMajorLayout,
+	} {
+		arr, err := array.NewDenseWithValues(array.Shape{2, 3}, values, layout)
+		if assert.Nil(t, err) {
+			v, err := arr.Get(array.Indices{1, 0})
+			assert.Nil(t, err)
+			assert.Equal(t, 4.0, v)
+			assert.Equal(t, values, arr.Values())
+		}
+	}
+	arr, err := array.NewDenseWithValues(
+		array.Shape{2, 3}, values, array.ColumnMajorLayout)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, arr.Data)
+	}
+	_, err = array.NewDenseWithValues(
+		array.Shape{2, 2}, values, array.DefaultAttributes)
+	assert.ErrorIs(t, err, array.ErrInvalidValuesLength)
+}
+
+func TestDenseWriteable(t *testing.T) {
+	// Arrays are modifiable whatever the attributes they are made with.
+	d, err := array.NewDense(array.Shape{2, 2}, array.RowMajorLayout)
+	if assert.Nil(t, err) {
+		assert.True(t, d.Attrs.Is(array.Writeable))
+		d.Fill(1, 1)
+		assert.Nil(t, d.Set(array.Indices{1, 1}, -4))
+		assert.Equal(t, []float64{1, 2, 3, -4}, d.Values())
+	}
+	d.Attrs &^= array.Writeable
+	assert.Equal(t, array.ErrNotWriteable, d.Set(array.Indices{0, 0}, 1))
+	assert.Panics(t, func() { d.Fill(0, 0) })
+}
@@ -1,7 +1,10 @@
 package array
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
+	"math/bits"
 	"unsafe"
 )
 
@@ -9,16 +12,76 @@ import (
 type DType string
 
 const (
-	// Float64 is the Go's float64 type.
+	// Float64 is the Go's float64 type, stored in the byte order of the
+	// machine.
 	Float64 DType = "float64"
+
+	// LittleEndianFloat64 is a float64 stored in little-endian byte
+	// order, as in NumPy's "<f8".
+	LittleEndianFloat64 DType = "<f8"
+
+	// BigEndianFloat64 is a float64 stored in big-endian byte order, as
+	// in NumPy's ">f8".
+	BigEndianFloat64 DType = ">f8"
 )
 
+// nativeFloat64 is the explicit byte-order data type equivalent to
+// Float64 on this machine.
+var nativeFloat64 = func() DType {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return LittleEndianFloat64
+	}
+	return BigEndianFloat64
+}()
+
 // Size returns the number of bytes used to store an element of this
 // data-type.
 func (d DType) Size() int {
 	switch d {
-	case Float64:
+	case Float64, LittleEndianFloat64, BigEndianFloat64:
 		return int(unsafe.Sizeof(float64(0)))
 	}
 	panic(fmt.Sprintf("array: invalid dtype %#v", d))
 }
+
+// ByteOrder returns the byte order in which items of this data-type are
+// stored.
+func (d DType) ByteOrder() binary.ByteOrder {
+	switch d.explicit() {
+	case LittleEndianFloat64:
+		return binary.LittleEndian
+	case BigEndianFloat64:
+		return binary.BigEndian
+	}
+	panic(fmt.Sprintf("array: invalid dtype %#v", d))
+}
+
+// Native returns whether items of this data-type are stored in the byte
+// order of the machine, and so can be used as float64 values directly.
+// The items of arrays with other data-types are swapped as they are
+// read and written.
+func (d DType) Native() bool {
+	return d == Float64 || d == nativeFloat64
+}
+
+// explicit returns the data-type with the byte order spelled out.
+func (d DType) explicit() DType {
+	if d == Float64 {
+		return nativeFloat64
+	}
+	return d
+}
+
+func (d DType) valid() bool {
+	switch d {
+	case Float64, LittleEndianFloat64, BigEndianFloat64:
+		return true
+	}
+	return false
+}
+
+// swapBytes returns v with the order of its bytes reversed.
+func swapBytes(v float64) float64 {
+	return math.Float64frombits(bits.ReverseBytes64(math.Float64bits(v)))
+}
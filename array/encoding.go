@@ -0,0 +1,491 @@
+package array
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+)
+
+// binaryVersion is the version of the binary encoding of arrays.
+const binaryVersion = 1
+
+// errEncoding is returned when decoding malformed data.
+var errEncoding = errors.New("malformed encoding")
+
+func encodingError(operation string, err error) error {
+	return &Error{Operation: operation, Message: "cannot decode array", Err: err}
+}
+
+// compact returns d if its items fill Data in the order given by its
+// strides, and otherwise a copy of d that does, with the same layout
+// if it has one.
+func (d *Dense) compact() *Dense {
+	if (d.IsContiguous(RowMajorLayout) || d.IsContiguous(ColumnMajorLayout)) &&
+		len(d.Data) == d.Size() {
+		return d
+	}
+	attrs := d.Attrs&^(Contiguous|RowMajorLayout|ColumnMajorLayout) | Contiguous
+	if d.Attrs.Is(ColumnMajorLayout) {
+		attrs |= ColumnMajorLayout
+	} else {
+		attrs |= RowMajorLayout
+	}
+	c, _ := NewDenseWithValues(d.Shape, d.Values(), attrs)
+	return c
+}
+
+// decoded returns an array with the given description whose items are
+// values, in storage order, checking that the description is valid.
+func decoded(
+	shape Shape, strides Strides, dtype DType, attrs Attributes, values []float64,
+) (*Dense, error) {
+	if !dtype.valid() {
+		return nil, errors.New("invalid dtype")
+	}
+	if err := shape.Validate(); err != nil {
+		return nil, err
+	}
+	if strides == nil {
+		var err error
+		if strides, err = NewStrides(shape, dtype, attrs); err != nil {
+			return nil, err
+		}
+	}
+	if len(strides) != len(shape) {
+		return nil, ErrInvalidStridesLength
+	}
+	n, err := shape.Size()
+	if err != nil {
+		return nil, err
+	}
+	// Every item must lie within the values, checked one axis at a time
+	// so that large strides cannot overflow.
+	extent := dtype.Size()
+	for i, s := range strides {
+		if s < 0 || s%dtype.Size() != 0 {
+			return nil, ErrUnmatchedShapeAndStrides
+		}
+		if n > 0 && shape[i] > 1 {
+			if s > (n*dtype.Size()-extent)/(shape[i]-1) {
+				return nil, ErrUnmatchedShapeAndStrides
+			}
+			extent += s * (shape[i] - 1)
+		}
+	}
+	if len(values) != n {
+		return nil, ErrInvalidValuesLength
+	}
+	d := &Dense{
+		Data:    CurrentAllocator().Alloc(n),
+		DType:   dtype,
+		Shape:   shape,
+		Strides: strides,
+		Attrs:   attrs,
+	}
+	for i, v := range values {
+		d.store(i, v)
+	}
+	return d, nil
+}
+
+// MarshalBinary encodes the array, with its data-type, attributes and
+// memory layout, in a compact binary form that is independent of the
+// byte order of the machine.  Together with UnmarshalBinary, it is
+// used by encoding/gob.
+func (d *Dense) MarshalBinary() ([]byte, error) {
+	c := d.compact()
+	var buf bytes.Buffer
+	buf.WriteByte(binaryVersion)
+	writeUvarint(&buf, uint64(len(c.DType)))
+	buf.WriteString(string(c.DType))
+	writeUvarint(&buf, uint64(c.Attrs))
+	writeUvarint(&buf, uint64(len(c.Shape)))
+	for i := range c.Shape {
+		writeUvarint(&buf, uint64(c.Shape[i]))
+		writeUvarint(&buf, uint64(c.Strides[i]))
+	}
+	item := make([]byte, 8)
+	for i := range c.Data {
+		binary.LittleEndian.PutUint64(item, math.Float64bits(c.load(i)))
+		buf.Write(item)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes an array encoded by MarshalBinary, replacing
+// d.
+func (d *Dense) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	version, err := r.ReadByte()
+	if err != nil || version != binaryVersion {
+		return encodingError("unmarshal binary", errEncoding)
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil || length > uint64(r.Len()) {
+		return encodingError("unmarshal binary", errEncoding)
+	}
+	dtype := make([]byte, length)
+	r.Read(dtype)
+	attrs, err1 := binary.ReadUvarint(r)
+	ndim, err2 := binary.ReadUvarint(r)
+	if err1 != nil || err2 != nil {
+		return encodingError("unmarshal binary", errEncoding)
+	}
+	if ndim > MaxDimensions {
+		return encodingError("unmarshal binary", ErrInvalidShapeSize)
+	}
+	shape := make(Shape, ndim)
+	strides := make(Strides, ndim)
+	for i := range shape {
+		dim, err1 := binary.ReadUvarint(r)
+		stride, err2 := binary.ReadUvarint(r)
+		if err1 != nil || err2 != nil || dim > math.MaxInt32 || stride > math.MaxInt32 {
+			return encodingError("unmarshal binary", errEncoding)
+		}
+		shape[i] = int(dim)
+		strides[i] = int(stride)
+	}
+	if r.Len()%8 != 0 {
+		return encodingError("unmarshal binary", errEncoding)
+	}
+	values := make([]float64, r.Len()/8)
+	item := make([]byte, 8)
+	for i := range values {
+		r.Read(item)
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(item))
+	}
+	out, err := decoded(shape, strides, DType(dtype), Attributes(attrs), values)
+	if err != nil {
+		return encodingError("unmarshal binary", err)
+	}
+	*d = *out
+	return nil
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+// jsonFloat is a float64 encoded in JSON as a number, or as one of the
+// strings "NaN", "Infinity" and "-Infinity", which JSON numbers cannot
+// represent.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsNaN(v):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(v, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(v, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return json.Marshal(v)
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"NaN"`:
+		*f = jsonFloat(math.NaN())
+	case `"Infinity"`:
+		*f = jsonFloat(math.Inf(1))
+	case `"-Infinity"`:
+		*f = jsonFloat(math.Inf(-1))
+	default:
+		var v float64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*f = jsonFloat(v)
+	}
+	return nil
+}
+
+// jsonDense is the compact JSON form of an array.
+type jsonDense struct {
+	Shape   Shape       `json:"shape"`
+	Strides Strides     `json:"strides,omitempty"`
+	DType   DType       `json:"dtype,omitempty"`
+	Attrs   *Attributes `json:"attrs,omitempty"`
+	Data    []jsonFloat `json:"data"`
+}
+
+// MarshalJSON encodes the array as an object holding its shape,
+// strides, data-type, attributes and the items of Data in the order
+// of the strides.  Non-finite items are encoded as the strings "NaN",
+// "Infinity" and "-Infinity".  Use NestedJSON for nested lists.
+func (d *Dense) MarshalJSON() ([]byte, error) {
+	c := d.compact()
+	data := make([]jsonFloat, len(c.Data))
+	for i := range data {
+		data[i] = jsonFloat(c.load(i))
+	}
+	return json.Marshal(jsonDense{
+		Shape:   c.Shape,
+		Strides: c.Strides,
+		DType:   c.DType,
+		Attrs:   &c.Attrs,
+		Data:    data,
+	})
+}
+
+// UnmarshalJSON decodes an array encoded by MarshalJSON or NestedJSON,
+// replacing d.  In the object form, omitted strides follow the layout
+// of the attributes, an omitted data-type is Float64 and omitted
+// attributes are DefaultAttributes.  Nested lists must be rectangular,
+// and give an array with DefaultAttributes; a single number gives a
+// zero-dimensional array.
+func (d *Dense) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var j jsonDense
+		if err := json.Unmarshal(trimmed, &j); err != nil {
+			return encodingError("unmarshal json", err)
+		}
+		if j.Shape == nil {
+			return encodingError("unmarshal json", errors.New("missing shape"))
+		}
+		if j.DType == "" {
+			j.DType = Float64
+		}
+		attrs := DefaultAttributes
+		if j.Attrs != nil {
+			attrs = *j.Attrs
+		}
+		values := make([]float64, len(j.Data))
+		for i, v := range j.Data {
+			values[i] = float64(v)
+		}
+		out, err := decoded(j.Shape, j.Strides, j.DType, attrs, values)
+		if err != nil {
+			return encodingError("unmarshal json", err)
+		}
+		*d = *out
+		return nil
+	}
+	var nested interface{}
+	if err := json.Unmarshal(trimmed, &nested); err != nil {
+		return encodingError("unmarshal json", err)
+	}
+	var shape Shape
+	for v := nested; ; {
+		list, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		shape = append(shape, len(list))
+		if len(list) == 0 {
+			break
+		}
+		v = list[0]
+	}
+	if shape == nil {
+		shape = Shape{}
+	}
+	values, err := flatten(nested, shape, nil)
+	if err != nil {
+		return encodingError("unmarshal json", err)
+	}
+	out, err := NewDenseWithValues(shape, values, DefaultAttributes)
+	if err != nil {
+		return encodingError("unmarshal json", err)
+	}
+	*d = *out
+	return nil
+}
+
+// flatten appends the items of the nested lists v, which must have the
+// given shape, to values in row-major order.
+func flatten(v interface{}, shape Shape, values []float64) ([]float64, error) {
+	if len(shape) == 0 {
+		var f jsonFloat
+		switch x := v.(type) {
+		case float64:
+			return append(values, x), nil
+		case string:
+			b, _ := json.Marshal(x)
+			if err := f.UnmarshalJSON(b); err == nil {
+				return append(values, float64(f)), nil
+			}
+		}
+		return nil, errors.New("lists are not rectangular or hold non-numbers")
+	}
+	list, ok := v.([]interface{})
+	if !ok || len(list) != shape[0] {
+		return nil, errors.New("lists are not rectangular or hold non-numbers")
+	}
+	var err error
+	for _, item := range list {
+		if values, err = flatten(item, shape[1:], values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// NestedJSON wraps an array to encode it in JSON as nested lists, like
+// NumPy's tolist, with non-finite items encoded as in MarshalJSON.  The
+// layout and attributes of the array are not kept.
+type NestedJSON struct {
+	*Dense
+}
+
+// MarshalJSON encodes the array as nested lists.
+func (n NestedJSON) MarshalJSON() ([]byte, error) {
+	values := n.Values()
+	var buf bytes.Buffer
+	var write func(axis, offset int) error
+	write = func(axis, offset int) error {
+		if axis == len(n.Shape) {
+			b, err := jsonFloat(values[offset]).MarshalJSON()
+			buf.Write(b)
+			return err
+		}
+		block := 1
+		for _, dim := range n.Shape[axis+1:] {
+			block *= dim
+		}
+		buf.WriteByte('[')
+		for i := 0; i < n.Shape[axis]; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := write(axis+1, offset+i*block); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	if err := write(0, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// attributeNames lists the names of the attributes, in the order of
+// String.
+var attributeNames = []struct {
+	attr Attributes
+	name string
+}{
+	{Contiguous, "Contiguous"},
+	{Writeable, "Writeable"},
+	{RowMajorLayout, "RowMajorLayout"},
+	{ColumnMajorLayout, "ColumnMajorLayout"},
+	{ComplexItems, "ComplexItems"},
+}
+
+// MarshalText encodes the attributes as the names given by String.
+// Through it, attributes are encoded in JSON as a string.
+func (a Attributes) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes attributes encoded by MarshalText.
+func (a *Attributes) UnmarshalText(text []byte) error {
+	var attrs Attributes
+	for _, field := range strings.Split(string(text), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		found := false
+		for _, n := range attributeNames {
+			if n.name == field {
+				attrs |= n.attr
+				found = true
+			}
+		}
+		if !found {
+			return &Error{
+				Operation: "unmarshal text",
+				Message:   "unknown attribute " + field,
+			}
+		}
+	}
+	*a = attrs
+	return nil
+}
+
+// MarshalBinary encodes the attributes as a varint.
+func (a Attributes) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	writeUvarint(&b, uint64(a))
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes attributes encoded by MarshalBinary.
+func (a *Attributes) UnmarshalBinary(data []byte) error {
+	v, n := binary.Uvarint(data)
+	if n <= 0 || n != len(data) {
+		return encodingError("unmarshal binary", errEncoding)
+	}
+	*a = Attributes(v)
+	return nil
+}
+
+// MarshalBinary encodes the shape as a sequence of varints.
+func (s Shape) MarshalBinary() ([]byte, error) {
+	return marshalInts(s), nil
+}
+
+// UnmarshalBinary decodes a shape encoded by MarshalBinary.
+func (s *Shape) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalInts(data)
+	if err != nil {
+		return err
+	}
+	*s = Shape(values)
+	return nil
+}
+
+// MarshalBinary encodes the strides as a sequence of varints.
+func (s Strides) MarshalBinary() ([]byte, error) {
+	return marshalInts(s), nil
+}
+
+// UnmarshalBinary decodes strides encoded by MarshalBinary.
+func (s *Strides) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalInts(data)
+	if err != nil {
+		return err
+	}
+	*s = Strides(values)
+	return nil
+}
+
+func marshalInts(values []int) []byte {
+	var b bytes.Buffer
+	writeUvarint(&b, uint64(len(values)))
+	var item [binary.MaxVarintLen64]byte
+	for _, v := range values {
+		b.Write(item[:binary.PutVarint(item[:], int64(v))])
+	}
+	return b.Bytes()
+}
+
+func unmarshalInts(data []byte) ([]int, error) {
+	r := bytes.NewReader(data)
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(len(data)) {
+		return nil, encodingError("unmarshal binary", errEncoding)
+	}
+	values := make([]int, n)
+	for i := range values {
+		v, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, encodingError("unmarshal binary", errEncoding)
+		}
+		values[i] = int(v)
+	}
+	if r.Len() != 0 {
+		return nil, encodingError("unmarshal binary", errEncoding)
+	}
+	return values, nil
+}
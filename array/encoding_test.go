@@ -0,0 +1,140 @@
+package array_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestDenseJSON(t *testing.T) {
+	d, err := array.NewDenseWithValues(array.Shape{2, 2},
+		[]float64{1, math.NaN(), math.Inf(1), -0.5}, array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(d)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `{"shape":[2,2],"strides":[8,16],"dtype":"float64",`+
+		`"attrs":"Contiguous, Writeable, ColumnMajorLayout",`+
+		`"data":[1,"Infinity","NaN",-0.5]}`, string(b))
+	var back array.Dense
+	if assert.Nil(t, json.Unmarshal(b, &back)) {
+		assert.Equal(t, d.Shape, back.Shape)
+		assert.Equal(t, d.Strides, back.Strides)
+		assert.Equal(t, d.Attrs, back.Attrs)
+		assert.Equal(t, math.Float64bits(d.Data[1]), math.Float64bits(back.Data[1]))
+	}
+
+	// A broadcast view is written with its own items only.
+	row, _ := array.NewDenseWithValues(array.Shape{1, 2}, []float64{3, 4},
+		array.Contiguous|array.Writeable|array.RowMajorLayout)
+	view, _ := row.BroadcastTo(array.Shape{2, 2})
+	b, err = json.Marshal(view)
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(b), `"strides":[16,8]`)
+		assert.Contains(t, string(b), `"data":[3,4,3,4]`)
+	}
+
+	for _, bad := range []string{
+		`{"shape":[2],"data":[1]}`,
+		`{"shape":[2],"strides":[-8],"data":[1,2]}`,
+		`{"shape":[2,3],"strides":[8,4611686018427387904],"data":[1,2,3,4,5,6]}`,
+		`{"shape":[2],"dtype":"int8","data":[1,2]}`,
+		`{"shape":[1],"attrs":"Shiny","data":[1]}`,
+		`{"data":[1]}`,
+	} {
+		assert.NotNil(t, json.Unmarshal([]byte(bad), &back), bad)
+	}
+}
+
+func TestNestedJSON(t *testing.T) {
+	d, err := array.NewDenseWithValues(array.Shape{2, 1, 3},
+		[]float64{1, 2, 3, 4, 5, math.Inf(-1)}, array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(array.NestedJSON{d})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `[[[1,2,3]],[[4,5,"-Infinity"]]]`, string(b))
+	var back array.Dense
+	if assert.Nil(t, json.Unmarshal(b, &back)) {
+		assert.Equal(t, d.Shape, back.Shape)
+		assert.Equal(t, d.Values(), back.Values())
+	}
+
+	assert.Nil(t, json.Unmarshal([]byte(`2.5`), &back))
+	assert.Equal(t, array.Shape{}, back.Shape)
+	assert.Equal(t, []float64{2.5}, back.Values())
+	assert.Nil(t, json.Unmarshal([]byte(`[]`), &back))
+	assert.Equal(t, array.Shape{0}, back.Shape)
+	assert.NotNil(t, json.Unmarshal([]byte(`[[1,2],[3]]`), &back))
+	assert.NotNil(t, json.Unmarshal([]byte(`[[1,2],[3,"x"]]`), &back))
+}
+
+func TestDenseBinary(t *testing.T) {
+	d, err := array.NewDenseWithValues(array.Shape{3, 2},
+		[]float64{1, 2, 3, 4, 5, math.NaN()},
+		array.Contiguous|array.Writeable|array.RowMajorLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, d.ByteSwap())
+	b, err := d.MarshalBinary()
+	if !assert.Nil(t, err) {
+		return
+	}
+	var back array.Dense
+	if assert.Nil(t, back.UnmarshalBinary(b)) {
+		assert.Equal(t, d.Shape, back.Shape)
+		assert.Equal(t, d.Strides, back.Strides)
+		assert.Equal(t, d.DType, back.DType)
+		assert.Equal(t, d.Attrs, back.Attrs)
+		assert.Equal(t, d.Values()[:5], back.Values()[:5])
+		assert.True(t, math.IsNaN(back.Values()[5]))
+	}
+	assert.NotNil(t, back.UnmarshalBinary(b[:len(b)-1]))
+	assert.NotNil(t, back.UnmarshalBinary(nil))
+}
+
+func TestGob(t *testing.T) {
+	type message struct {
+		Name  string
+		Array *array.Dense
+		Shape array.Shape
+		Attrs array.Attributes
+	}
+	d, err := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{1, 2, 3, 4, 5, 6}, array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := message{"x", d, array.Shape{4, 0, 1}, array.Contiguous}
+	var buf bytes.Buffer
+	if !assert.Nil(t, gob.NewEncoder(&buf).Encode(in)) {
+		return
+	}
+	var out message
+	if assert.Nil(t, gob.NewDecoder(&buf).Decode(&out)) {
+		assert.Equal(t, in.Name, out.Name)
+		assert.Equal(t, d, out.Array)
+		assert.Equal(t, in.Shape, out.Shape)
+		assert.Equal(t, in.Attrs, out.Attrs)
+	}
+
+	var strides array.Strides
+	b, _ := array.Strides{8, -16}.MarshalBinary()
+	if assert.Nil(t, strides.UnmarshalBinary(b)) {
+		assert.Equal(t, array.Strides{8, -16}, strides)
+	}
+	assert.NotNil(t, strides.UnmarshalBinary(append(b, 0)))
+}
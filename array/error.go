@@ -1,6 +1,7 @@
 package array
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -36,22 +37,70 @@ var (
 	// be accessed.
 	ErrIncorrectIndices = errors.New("incorrect number of indices for array")
 
+	// ErrBroadcast is returned when array shapes cannot be broadcast
+	// together.
+	ErrBroadcast = errors.New("shapes cannot be broadcast together")
+
+	// ErrInvalidValuesLength is returned when the number of values given
+	// to build an array differs from the number of items in its shape.
+	ErrInvalidValuesLength = errors.New(
+		"number of values does not match the shape size")
+
+	// ErrUnmatchedSamplePoints is returned when the sample points of an
+	// array along an axis do not have the length of that axis.
+	ErrUnmatchedSamplePoints = errors.New(
+		"sample points must be a vector with the length of the axis")
+
 	// ErrZeroDivision is returned when a division by zero occurred in
 	// some specific situations, such as in Arange.
 	ErrZeroDivision = errors.New("division by zero")
+
+	// ErrNotWriteable is returned when modifying an array that does not
+	// have the Writeable attribute.
+	ErrNotWriteable = errors.New("array is read-only")
+
+	// ErrNotComplex is returned when an array is not a complex array,
+	// with the ComplexItems attribute and a last axis holding the real
+	// and imaginary parts of its items, or does not have the number of
+	// dimensions of the operation.
+	ErrNotComplex = errors.New(
+		"last axis of complex arrays must hold two parts")
 )
 
 // Error is used to describe errors that are not otherwise
-// specified.
+// specified.  Err optionally holds the underlying cause, such as the
+// error of a canceled context.
 type Error struct {
 	Operation string
 	Message   string
+	Err       error
 }
 
 func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Operation, e.Message, e.Err)
+	}
 	return fmt.Sprintf("%s: %s", e.Operation, e.Message)
 }
 
+// Unwrap returns the underlying cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// canceled returns an Error wrapping the error of ctx if it is done,
+// and nil otherwise.
+func canceled(ctx context.Context, operation string) error {
+	if err := ctx.Err(); err != nil {
+		return &Error{
+			Operation: operation,
+			Message:   "operation canceled",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
 // OutOfBoundsError describes an invalid acces for an array item.
 type OutOfBoundsError struct {
 	Index   int
@@ -70,3 +119,47 @@ func (e *OutOfBoundsError) Error() string {
 		e.Index, e.Axis, e.DimSize)
 	return msg
 }
+
+// AxisError describes an axis that does not exist in an array.
+type AxisError struct {
+	Axis int
+	NDim int
+}
+
+func (e *AxisError) Error() string {
+	return fmt.Sprintf(
+		"axis %d is out of bounds for array of dimension %d", e.Axis, e.NDim)
+}
+
+// TextError describes a field of delimited text that cannot be read.
+// Column is the position of the field in the line, or negative when
+// the error concerns the whole line.
+type TextError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *TextError) Error() string {
+	if e.Column < 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying cause of the error.
+func (e *TextError) Unwrap() error {
+	return e.Err
+}
+
+// SyntaxError describes an error in a formula given to Evaluate.
+// Column is the position of the error in the formula, counting bytes
+// from one.
+type SyntaxError struct {
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
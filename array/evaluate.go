@@ -0,0 +1,458 @@
+package array
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Evaluate returns a new array holding the value of a formula over
+// the arrays in vars, broadcast together.  Every item of the result is
+// computed in a single pass from the items of the arrays, without
+// intermediate arrays.
+//
+// Formulas combine numbers, variables and function calls with, from
+// the lowest to the highest precedence:
+//
+//	or |             logical or
+//	and &            logical and
+//	not ~            logical negation
+//	< <= > >= == !=  comparisons
+//	+ -              addition and subtraction
+//	* / %            multiplication, division and remainder
+//	- +              unary minus and plus
+//	**               power, right associative
+//
+// Comparisons and logical operations give one for true and zero for
+// false, and logical operations take non-zero items as true.  The
+// functions are abs, sqrt, exp, log, log10, sin, cos, tan, arcsin,
+// arccos, arctan, sinh, cosh, tanh, floor and ceil of one argument,
+// arctan2, minimum and maximum of two arguments, and where(c, x, y),
+// which is x where c is true and y elsewhere.  For example,
+//
+//	Evaluate("sqrt(x**2 + y**2) * where(m > 0, 1, -1)", vars)
+//
+// Errors in the formula, including unknown variables and functions,
+// are returned as a *SyntaxError.
+func Evaluate(formula string, vars map[string]*Dense) (*Dense, error) {
+	p := &parser{src: formula, vars: vars, leaves: make(map[string]int)}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	fn, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	operands := make([]*Dense, len(p.leaves))
+	for name, i := range p.leaves {
+		operands[i] = vars[name]
+	}
+	return MapN(fn, operands...)
+}
+
+// evalFunc computes an item of a formula from the items of its
+// variables.
+type evalFunc func(args []float64) float64
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenName
+	tokenOperator
+	tokenInvalid
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of formula"
+	}
+	return strconv.Quote(t.text)
+}
+
+// operators are the operator tokens, longest first.
+var operators = []string{
+	"**", "<=", ">=", "==", "!=",
+	"+", "-", "*", "/", "%", "<", ">", "&", "|", "~", "(", ")", ",",
+}
+
+// parser reads a formula by recursive descent, compiling it as it goes.
+type parser struct {
+	src    string
+	pos    int
+	tok    token
+	vars   map[string]*Dense
+	leaves map[string]int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Column: p.tok.pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// next reads the next token.
+func (p *parser) next() error {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	start := p.pos
+	p.tok = token{pos: start}
+	if start == len(p.src) {
+		p.tok.kind = tokenEOF
+		return nil
+	}
+	c := p.src[start]
+	r, size := utf8.DecodeRuneInString(p.src[start:])
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		end := start
+		for end < len(p.src) && (isDigit(p.src[end]) || p.src[end] == '.') {
+			end++
+		}
+		if end < len(p.src) && (p.src[end] == 'e' || p.src[end] == 'E') {
+			exp := end + 1
+			if exp < len(p.src) && (p.src[exp] == '+' || p.src[exp] == '-') {
+				exp++
+			}
+			if exp < len(p.src) && isDigit(p.src[exp]) {
+				for end = exp; end < len(p.src) && isDigit(p.src[end]); end++ {
+				}
+			}
+		}
+		p.tok.kind, p.tok.text = tokenNumber, p.src[start:end]
+		v, err := strconv.ParseFloat(p.tok.text, 64)
+		if err != nil {
+			return p.errorf("invalid number %s", p.tok)
+		}
+		p.tok.value = v
+		p.pos = end
+	case r == '_' || unicode.IsLetter(r):
+		end := start + size
+		for end < len(p.src) {
+			r, size := utf8.DecodeRuneInString(p.src[end:])
+			if r != '_' && !unicode.IsLetter(r) && !(r >= '0' && r <= '9') {
+				break
+			}
+			end += size
+		}
+		p.tok.kind, p.tok.text = tokenName, p.src[start:end]
+		p.pos = end
+	default:
+		for _, op := range operators {
+			if strings.HasPrefix(p.src[start:], op) {
+				p.tok.kind, p.tok.text = tokenOperator, op
+				p.pos += len(op)
+				return nil
+			}
+		}
+		p.tok.kind, p.tok.text = tokenInvalid, p.src[start:start+size]
+		return p.errorf("unexpected character %s", p.tok)
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// accept reads the current token if it is one of the operators or
+// keywords, and returns it.
+func (p *parser) accept(ops ...string) (string, bool, error) {
+	if p.tok.kind != tokenOperator && p.tok.kind != tokenName {
+		return "", false, nil
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return op, true, p.next()
+		}
+	}
+	return "", false, nil
+}
+
+// parseBinary parses operands given by parse joined by the operators
+// of one precedence level, from left to right.
+func (p *parser) parseBinary(
+	parse func() (evalFunc, error), ops map[string]func(x, y float64) float64,
+) (evalFunc, error) {
+	names := make([]string, 0, len(ops))
+	for op := range ops {
+		names = append(names, op)
+	}
+	x, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok, err := p.accept(names...)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return x, nil
+		}
+		y, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryFunc(ops[op], x, y)
+	}
+}
+
+func binaryFunc(fn func(x, y float64) float64, x, y evalFunc) evalFunc {
+	return func(args []float64) float64 { return fn(x(args), y(args)) }
+}
+
+func unaryFunc(fn func(x float64) float64, x evalFunc) evalFunc {
+	return func(args []float64) float64 { return fn(x(args)) }
+}
+
+var (
+	orOperators = map[string]func(x, y float64) float64{
+		"or": logicalOr, "|": logicalOr,
+	}
+	andOperators = map[string]func(x, y float64) float64{
+		"and": logicalAnd, "&": logicalAnd,
+	}
+	comparisons = map[string]func(x, y float64) float64{
+		"<":  func(x, y float64) float64 { return truth(x < y) },
+		"<=": func(x, y float64) float64 { return truth(x <= y) },
+		">":  func(x, y float64) float64 { return truth(x > y) },
+		">=": func(x, y float64) float64 { return truth(x >= y) },
+		"==": func(x, y float64) float64 { return truth(x == y) },
+		"!=": func(x, y float64) float64 { return truth(x != y) },
+	}
+	additions = map[string]func(x, y float64) float64{
+		"+": func(x, y float64) float64 { return x + y },
+		"-": func(x, y float64) float64 { return x - y },
+	}
+	multiplications = map[string]func(x, y float64) float64{
+		"*": func(x, y float64) float64 { return x * y },
+		"/": func(x, y float64) float64 { return x / y },
+		"%": math.Mod,
+	}
+)
+
+func (p *parser) parseOr() (evalFunc, error) {
+	return p.parseBinary(p.parseAnd, orOperators)
+}
+
+func (p *parser) parseAnd() (evalFunc, error) {
+	return p.parseBinary(p.parseNot, andOperators)
+}
+
+func (p *parser) parseNot() (evalFunc, error) {
+	_, ok, err := p.accept("not", "~")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return p.parseComparison()
+	}
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return unaryFunc(func(x float64) float64 { return truth(x == 0) }, x), nil
+}
+
+func (p *parser) parseComparison() (evalFunc, error) {
+	x, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for op, fn := range comparisons {
+		if p.tok.kind == tokenOperator && p.tok.text == op {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			y, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := comparisons[p.tok.text]; ok && p.tok.kind == tokenOperator {
+				return nil, p.errorf("comparisons cannot be chained")
+			}
+			return binaryFunc(fn, x, y), nil
+		}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAdditive() (evalFunc, error) {
+	return p.parseBinary(p.parseMultiplicative, additions)
+}
+
+func (p *parser) parseMultiplicative() (evalFunc, error) {
+	return p.parseBinary(p.parseUnary, multiplications)
+}
+
+func (p *parser) parseUnary() (evalFunc, error) {
+	op, ok, err := p.accept("-", "+")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return p.parsePower()
+	}
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if op == "-" {
+		return unaryFunc(func(x float64) float64 { return -x }, x), nil
+	}
+	return x, nil
+}
+
+func (p *parser) parsePower() (evalFunc, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok, err := p.accept("**"); err != nil || !ok {
+		return x, err
+	}
+	// The exponent may have a sign, and binds to the right.
+	y, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryFunc(math.Pow, x, y), nil
+}
+
+// functions are the functions of formulas, by number of arguments.
+var (
+	functions1 = map[string]func(x float64) float64{
+		"abs": math.Abs, "sqrt": math.Sqrt, "exp": math.Exp,
+		"log": math.Log, "log10": math.Log10,
+		"sin": math.Sin, "cos": math.Cos, "tan": math.Tan,
+		"arcsin": math.Asin, "arccos": math.Acos, "arctan": math.Atan,
+		"sinh": math.Sinh, "cosh": math.Cosh, "tanh": math.Tanh,
+		"floor": math.Floor, "ceil": math.Ceil,
+	}
+	functions2 = map[string]func(x, y float64) float64{
+		"arctan2": math.Atan2, "minimum": math.Min, "maximum": math.Max,
+	}
+)
+
+func (p *parser) parsePrimary() (evalFunc, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenNumber:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		v := tok.value
+		return func([]float64) float64 { return v }, nil
+	case tokenName:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokenOperator && p.tok.text == "(" {
+			return p.parseCall(tok)
+		}
+		d, ok := p.vars[tok.text]
+		if !ok || d == nil {
+			p.tok = tok
+			return nil, p.errorf("unknown variable %s", tok)
+		}
+		i, ok := p.leaves[tok.text]
+		if !ok {
+			i = len(p.leaves)
+			p.leaves[tok.text] = i
+		}
+		return func(args []float64) float64 { return args[i] }, nil
+	case tokenOperator:
+		if tok.text == "(" {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+// expect reads an operator, failing if the current token is another.
+func (p *parser) expect(op string) error {
+	if p.tok.kind != tokenOperator || p.tok.text != op {
+		return p.errorf("expected %q, found %s", op, p.tok)
+	}
+	return p.next()
+}
+
+// parseCall parses the arguments of a call to the function named by
+// tok, the current token being the opening parenthesis.
+func (p *parser) parseCall(name token) (evalFunc, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var args []evalFunc
+	if p.tok.kind != tokenOperator || p.tok.text != ")" {
+		for {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, x)
+			if p.tok.kind != tokenOperator || p.tok.text != "," {
+				break
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	arity := -1
+	if fn, ok := functions1[name.text]; ok {
+		if arity = 1; len(args) == arity {
+			return unaryFunc(fn, args[0]), nil
+		}
+	} else if fn, ok := functions2[name.text]; ok {
+		if arity = 2; len(args) == arity {
+			return binaryFunc(fn, args[0], args[1]), nil
+		}
+	} else if name.text == "where" {
+		if arity = 3; len(args) == arity {
+			c, x, y := args[0], args[1], args[2]
+			return func(a []float64) float64 {
+				if c(a) != 0 {
+					return x(a)
+				}
+				return y(a)
+			}, nil
+		}
+	}
+	p.tok = name
+	if arity < 0 {
+		return nil, p.errorf("unknown function %s", name)
+	}
+	return nil, p.errorf("%s takes %d arguments, not %d", name.text, arity, len(args))
+}
@@ -0,0 +1,84 @@
+package array_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestEvaluate(t *testing.T) {
+	x, _ := array.NewDenseWithValues(array.Shape{3}, []float64{3, 0, -6}, array.DefaultAttributes)
+	y, _ := array.NewDenseWithValues(array.Shape{3}, []float64{4, 1, 8}, array.DefaultAttributes)
+	m, _ := array.NewDenseWithValues(array.Shape{2, 1}, []float64{1, -1}, array.DefaultAttributes)
+	vars := map[string]*array.Dense{"x": x, "y": y, "m": m, "é": y}
+
+	d, err := array.Evaluate("sqrt(x**2 + y**2) * where(m > 0, 1, -1)", vars)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{2, 3}, d.Shape)
+		assert.Equal(t, []float64{5, 1, 10, -5, -1, -10}, d.Values())
+	}
+
+	cases := []struct {
+		formula string
+		want    []float64
+	}{
+		{"1 + 2 * 3", []float64{7}},
+		{"(1 + 2) * 3", []float64{9}},
+		{"2 ** 3 ** 2", []float64{512}},
+		{"-2 ** 2", []float64{-4}},
+		{"2 ** -1", []float64{0.5}},
+		{"7 % 3 - 10 / 4", []float64{-1.5}},
+		{"1.5e2 + .5", []float64{150.5}},
+		{"x + y", []float64{7, 1, 2}},
+		{"x < y", []float64{1, 1, 1}},
+		{"x >= 0 & y != 1", []float64{1, 0, 0}},
+		{"x == 0 or y > 5", []float64{0, 1, 1}},
+		{"not x and ~(y < 0)", []float64{0, 1, 0}},
+		{"1 + 1 > 1", []float64{1}},
+		{"abs(x) + floor(0.5) + ceil(0.5)", []float64{4, 1, 7}},
+		{"maximum(x, y) - minimum(x, 0)", []float64{4, 1, 14}},
+		{"arctan2(1, 1) * 4", []float64{math.Pi}},
+		{"é - y", []float64{0, 0, 0}},
+	}
+	for _, c := range cases {
+		d, err := array.Evaluate(c.formula, vars)
+		if assert.NoError(t, err, c.formula) {
+			assert.Equal(t, c.want, d.Values(), c.formula)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	x, _ := array.NewDenseWithValues(array.Shape{3}, []float64{1, 2, 3}, array.DefaultAttributes)
+	z, _ := array.NewDenseWithValues(array.Shape{2}, []float64{1, 2}, array.DefaultAttributes)
+	vars := map[string]*array.Dense{"x": x, "z": z}
+	cases := []struct {
+		formula string
+		column  int
+		message string
+	}{
+		{"x +", 4, `unexpected end of formula`},
+		{"x + w", 5, `unknown variable "w"`},
+		{"2 * foo(x)", 5, `unknown function "foo"`},
+		{"sqrt(x, x)", 1, `sqrt takes 1 arguments, not 2`},
+		{"(x + 1", 7, `expected ")", found end of formula`},
+		{"x $ 1", 3, `unexpected character "$"`},
+		{"é € 1", 4, `unexpected character "€"`},
+		{"x 1", 3, `unexpected "1"`},
+		{"1.2.3", 1, `invalid number "1.2.3"`},
+		{"x < 1 < 2", 7, `comparisons cannot be chained`},
+	}
+	for _, c := range cases {
+		_, err := array.Evaluate(c.formula, vars)
+		if assert.IsType(t, &array.SyntaxError{}, err, c.formula) {
+			e := err.(*array.SyntaxError)
+			assert.Equal(t, c.column, e.Column, c.formula)
+			assert.Equal(t, c.message, e.Message, c.formula)
+		}
+	}
+	_, err := array.Evaluate("x + z", vars)
+	assert.Equal(t, array.ErrBroadcast, err)
+}
@@ -0,0 +1,72 @@
+package array
+
+// Gradient returns the derivative of f along axis estimated with
+// second-order accurate central differences in the interior and
+// second-order accurate one-sided differences at the edges.  The
+// sample points are given by the vector x, or are evenly spaced dx
+// apart when x is nil, and need not be evenly spaced.  At least three
+// samples are required along axis.
+func Gradient(f, x *Dense, dx float64, axis int) (*Dense, error) {
+	n, err := f.axisLength(axis)
+	if err != nil {
+		return nil, err
+	}
+	if n < 3 {
+		return nil, &Error{
+			Operation: "gradient",
+			Message:   "at least three samples are required along axis",
+		}
+	}
+	xs, err := samplePoints(x, dx, n)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyAlongAxis(f, axis, n, func(dst, ys []float64) {
+		for i := 1; i < n-1; i++ {
+			hs := xs[i] - xs[i-1]
+			hd := xs[i+1] - xs[i]
+			dst[i] = -hd/(hs*(hs+hd))*ys[i-1] +
+				(hd-hs)/(hs*hd)*ys[i] +
+				hs/(hd*(hs+hd))*ys[i+1]
+		}
+		h1 := xs[1] - xs[0]
+		h2 := xs[2] - xs[1]
+		dst[0] = -(2*h1+h2)/(h1*(h1+h2))*ys[0] +
+			(h1+h2)/(h1*h2)*ys[1] -
+			h1/(h2*(h1+h2))*ys[2]
+		h1 = xs[n-2] - xs[n-3]
+		h2 = xs[n-1] - xs[n-2]
+		dst[n-1] = h2/(h1*(h1+h2))*ys[n-3] -
+			(h1+h2)/(h1*h2)*ys[n-2] +
+			(2*h2+h1)/(h2*(h1+h2))*ys[n-1]
+	})
+}
+
+// Diff returns the n-th discrete difference along axis, given by
+// out[i] = d[i+1] - d[i] applied recursively n times.  The result has
+// n items less than d along axis, or none if the axis is shorter.
+func (d *Dense) Diff(n, axis int) (*Dense, error) {
+	if n < 0 {
+		return nil, &Error{
+			Operation: "diff",
+			Message:   "order must be non-negative",
+		}
+	}
+	length, err := d.axisLength(axis)
+	if err != nil {
+		return nil, err
+	}
+	m := length - n
+	if m < 0 {
+		m = 0
+	}
+	return ApplyAlongAxis(d, axis, m, func(dst, src []float64) {
+		work := append([]float64(nil), src...)
+		for order := 1; order <= n && order <= length; order++ {
+			for i := 0; i < length-order; i++ {
+				work[i] = work[i+1] - work[i]
+			}
+		}
+		copy(dst, work)
+	})
+}
@@ -0,0 +1,66 @@
+package array_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestGradient(t *testing.T) {
+	// Second-order differences are exact for quadratics, even on
+	// uneven grids.
+	xs := []float64{0, 0.5, 1.5, 2, 3.5}
+	values := make([]float64, 0, 2*len(xs))
+	for _, x := range xs {
+		values = append(values, x*x, 3*x)
+	}
+	f, _ := array.NewDenseWithValues(
+		array.Shape{len(xs), 2}, values, array.RowMajorLayout)
+	x, _ := array.NewDenseWithValues(
+		array.Shape{len(xs)}, xs, array.DefaultAttributes)
+	g, err := array.Gradient(f, x, 0, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, f.Shape, g.Shape)
+		for i, x := range xs {
+			v, _ := g.Get(array.Indices{i, 0})
+			assert.InDelta(t, 2*x, v, 1e-12)
+			v, _ = g.Get(array.Indices{i, 1})
+			assert.InDelta(t, 3.0, v, 1e-12)
+		}
+	}
+	_, err = array.Gradient(f, nil, 1, 1)
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	d, _ := array.NewDenseWithValues(
+		array.Shape{2, 4},
+		[]float64{1, 2, 4, 7, 0, 0, 1, 0},
+		array.ColumnMajorLayout)
+	out, err := d.Diff(1, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 3}, out.Shape)
+		assert.Equal(t, []float64{1, 2, 3, 0, 1, -1}, out.Values())
+	}
+	out, err = d.Diff(2, -1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 1, 1, -2}, out.Values())
+	}
+	out, err = d.Diff(1, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{1, 4}, out.Shape)
+		assert.Equal(t, []float64{-1, -2, -3, -7}, out.Values())
+	}
+	out, err = d.Diff(0, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, d.Values(), out.Values())
+	}
+	out, err = d.Diff(5, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 0}, out.Shape)
+	}
+	_, err = d.Diff(-1, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package array
+
+// samplePoints returns the coordinates of n samples along an axis,
+// either taken from the vector x or, when x is nil, spaced dx apart
+// starting at zero.
+func samplePoints(x *Dense, dx float64, n int) ([]float64, error) {
+	if x == nil {
+		points := make([]float64, n)
+		for i := range points {
+			points[i] = float64(i) * dx
+		}
+		return points, nil
+	}
+	if len(x.Shape) != 1 || x.Shape[0] != n {
+		return nil, ErrUnmatchedSamplePoints
+	}
+	return x.Values(), nil
+}
+
+// axisLength returns the length of an axis of the array.
+func (d *Dense) axisLength(axis int) (int, error) {
+	axis, err := normalizeAxis(axis, len(d.Shape))
+	if err != nil {
+		return 0, err
+	}
+	return d.Shape[axis], nil
+}
+
+// Trapezoid integrates y along axis using the composite trapezoidal
+// rule.  The sample points are given by the vector x, or are evenly
+// spaced dx apart when x is nil.  The result has the shape of y
+// without axis.
+func Trapezoid(y, x *Dense, dx float64, axis int) (*Dense, error) {
+	n, err := y.axisLength(axis)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := samplePoints(x, dx, n)
+	if err != nil {
+		return nil, err
+	}
+	return ReduceAlongAxis(y, axis, func(ys []float64) float64 {
+		sum := 0.0
+		for i := 1; i < len(ys); i++ {
+			sum += (xs[i] - xs[i-1]) * (ys[i] + ys[i-1]) / 2
+		}
+		return sum
+	})
+}
+
+// CumulativeTrapezoid cumulatively integrates y along axis using the
+// composite trapezoidal rule.  The sample points are given by the
+// vector x, or are evenly spaced dx apart when x is nil.  The result
+// has one item less than y along axis, the item i holding the integral
+// from the first sample up to the sample i+1.
+func CumulativeTrapezoid(y, x *Dense, dx float64, axis int) (*Dense, error) {
+	n, err := y.axisLength(axis)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := samplePoints(x, dx, n)
+	if err != nil {
+		return nil, err
+	}
+	m := n - 1
+	if m < 0 {
+		m = 0
+	}
+	return ApplyAlongAxis(y, axis, m, func(dst, ys []float64) {
+		sum := 0.0
+		for i := 1; i < len(ys); i++ {
+			sum += (xs[i] - xs[i-1]) * (ys[i] + ys[i-1]) / 2
+			dst[i-1] = sum
+		}
+	})
+}
+
+// Simpson integrates y along axis using the composite Simpson's rule.
+// The sample points are given by the vector x, or are evenly spaced dx
+// apart when x is nil, and need not be evenly spaced.  When the number
+// of samples is even, the last interval is integrated with the
+// quadratic through the last three samples.  With only two samples,
+// the trapezoidal rule is used.  The result has the shape of y without
+// axis.
+func Simpson(y, x *Dense, dx float64, axis int) (*Dense, error) {
+	n, err := y.axisLength(axis)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := samplePoints(x, dx, n)
+	if err != nil {
+		return nil, err
+	}
+	return ReduceAlongAxis(y, axis, func(ys []float64) float64 {
+		return simpson(xs, ys)
+	})
+}
+
+func simpson(xs, ys []float64) float64 {
+	n := len(ys)
+	switch n {
+	case 0, 1:
+		return 0
+	case 2:
+		return (xs[1] - xs[0]) * (ys[0] + ys[1]) / 2
+	}
+	last := n - 1
+	if n%2 == 0 {
+		last = n - 2
+	}
+	sum := 0.0
+	for i := 0; i+2 <= last; i += 2 {
+		h0 := xs[i+1] - xs[i]
+		h1 := xs[i+2] - xs[i+1]
+		hsum := h0 + h1
+		sum += hsum / 6 * ((2-h1/h0)*ys[i] +
+			hsum*hsum/(h0*h1)*ys[i+1] +
+			(2-h0/h1)*ys[i+2])
+	}
+	if n%2 == 0 {
+		// Integrate the last interval with the quadratic through the
+		// last three samples.
+		h0 := xs[n-2] - xs[n-3]
+		h1 := xs[n-1] - xs[n-2]
+		alpha := (2*h1*h1 + 3*h0*h1) / (6 * (h0 + h1))
+		beta := (h1*h1 + 3*h0*h1) / (6 * h0)
+		eta := h1 * h1 * h1 / (6 * h0 * (h0 + h1))
+		sum += alpha*ys[n-1] + beta*ys[n-2] - eta*ys[n-3]
+	}
+	return sum
+}
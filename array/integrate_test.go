@@ -0,0 +1,89 @@
+package array_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestTrapezoid(t *testing.T) {
+	y, err := array.NewDenseWithValues(
+		array.Shape{2, 3}, []float64{1, 2, 3, 4, 5, 6}, array.RowMajorLayout)
+	assert.Nil(t, err)
+	out, err := array.Trapezoid(y, nil, 1, -1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2}, out.Shape)
+		assert.Equal(t, []float64{4, 10}, out.Values())
+	}
+	out, err = array.Trapezoid(y, nil, 2, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{3}, out.Shape)
+		assert.Equal(t, []float64{5, 7, 9}, out.Values())
+	}
+	x, _ := array.NewDenseWithValues(
+		array.Shape{3}, []float64{0, 1, 3}, array.DefaultAttributes)
+	out, err = array.Trapezoid(y, x, 0, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{6.5, 15.5}, out.Values())
+	}
+	_, err = array.Trapezoid(y, x, 0, 0)
+	assert.ErrorIs(t, err, array.ErrUnmatchedSamplePoints)
+	_, err = array.Trapezoid(y, nil, 1, 2)
+	if assert.Error(t, err) {
+		serr := err.(*array.AxisError)
+		assert.Equal(t, 2, serr.Axis)
+		assert.Equal(t, 2, serr.NDim)
+	}
+}
+
+func TestCumulativeTrapezoid(t *testing.T) {
+	x, _ := array.Linspace(-2, 2, 5, true)
+	y, _ := array.NewDenseWithValues(
+		array.Shape{5}, x.Values(), array.DefaultAttributes)
+	out, err := array.CumulativeTrapezoid(y, x, 0, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{4}, out.Shape)
+		assert.Equal(t, []float64{-1.5, -2, -1.5, 0}, out.Values())
+	}
+}
+
+func TestSimpson(t *testing.T) {
+	// Simpson's rule is exact for cubics, including on uneven grids.
+	cube := func(x float64) float64 { return x * x * x }
+	for _, xs := range [][]float64{
+		{0, 1, 2, 3, 4},
+		{0, 0.5, 2, 2.5, 4},
+		{0, 1, 1.5, 3, 4, 5, 8},
+	} {
+		ys := make([]float64, len(xs))
+		for i, x := range xs {
+			ys[i] = x * x
+		}
+		x, _ := array.NewDenseWithValues(
+			array.Shape{len(xs)}, xs, array.DefaultAttributes)
+		y, _ := array.NewDenseWithValues(
+			array.Shape{len(xs)}, ys, array.DefaultAttributes)
+		out, err := array.Simpson(y, x, 0, 0)
+		if assert.Nil(t, err) {
+			last := xs[len(xs)-1]
+			assert.InDelta(t, cube(last)/3, out.Data[0], 1e-12)
+		}
+	}
+	// An even number of samples is exact for quadratics.
+	y, _ := array.NewDenseWithValues(
+		array.Shape{4}, []float64{0, 1, 4, 9}, array.DefaultAttributes)
+	out, err := array.Simpson(y, nil, 1, 0)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 9.0, out.Data[0], 1e-12)
+	}
+	y, _ = array.NewDenseWithValues(
+		array.Shape{2}, []float64{1, 3}, array.DefaultAttributes)
+	out, err = array.Simpson(y, nil, 0.5, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1.0, out.Data[0])
+	}
+	assert.False(t, math.IsNaN(out.Data[0]))
+}
@@ -0,0 +1,88 @@
+package array
+
+// iterate calls fn with the position in Data of the current item of
+// every operand, visiting the items of shape in row-major order.  The
+// operands must have the given shape.
+func iterate(shape Shape, operands []*Dense, fn func(pos []int)) {
+	n, _ := shape.Size()
+	iterateRange(shape, operands, 0, n, fn)
+}
+
+// iterateRange is like iterate, but only visits the items from the
+// lo-th to the (hi-1)-th in row-major order.
+func iterateRange(shape Shape, operands []*Dense, lo, hi int, fn func(pos []int)) {
+	if lo >= hi {
+		return
+	}
+	nd := len(shape)
+	index := make([]int, nd)
+	offsets := make([]int, len(operands))
+	pos := make([]int, len(operands))
+	rest := lo
+	for axis := nd - 1; axis >= 0; axis-- {
+		index[axis] = rest % shape[axis]
+		rest /= shape[axis]
+		for i, op := range operands {
+			offsets[i] += index[axis] * op.Strides[axis]
+		}
+	}
+	for k := lo; k < hi; k++ {
+		for i, op := range operands {
+			pos[i] = offsets[i] / op.DType.Size()
+		}
+		fn(pos)
+		for axis := nd - 1; axis >= 0; axis-- {
+			index[axis]++
+			for i, op := range operands {
+				offsets[i] += op.Strides[axis]
+			}
+			if index[axis] < shape[axis] {
+				break
+			}
+			for i, op := range operands {
+				offsets[i] -= op.Strides[axis] * shape[axis]
+			}
+			index[axis] = 0
+		}
+	}
+}
+
+// forEach calls fn with the position in Data of every item of the
+// array, visiting items in row-major order of their indices regardless
+// of the memory layout.
+func (d *Dense) forEach(fn func(pos int)) {
+	iterate(d.Shape, []*Dense{d}, func(pos []int) {
+		fn(pos[0])
+	})
+}
+
+// Values returns a copy of the items in the array in row-major order,
+// independently of the memory layout.
+func (d *Dense) Values() []float64 {
+	values := make([]float64, 0, d.Size())
+	d.forEach(func(pos int) {
+		values = append(values, d.load(pos))
+	})
+	return values
+}
+
+// NewDenseWithValues returns a new array with a given shape whose
+// items are taken from values in row-major order.  The memory layout
+// is defined by the attributes, as in NewDense.
+func NewDenseWithValues(
+	shape Shape, values []float64, attrs Attributes,
+) (*Dense, error) {
+	d, err := NewDense(shape, attrs)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != d.Size() {
+		return nil, ErrInvalidValuesLength
+	}
+	i := 0
+	d.forEach(func(pos int) {
+		d.Data[pos] = values[i]
+		i++
+	})
+	return d, nil
+}
@@ -0,0 +1,89 @@
+package array
+
+// IsContiguous returns whether the items of d fill a single segment of
+// Data in the given layout, RowMajorLayout or ColumnMajorLayout.  It is
+// computed from the strides, and so holds whatever the attributes of d
+// claim.  As strides of axes with a single item are never used, arrays
+// may be contiguous in both layouts, and empty arrays always are.
+func (d *Dense) IsContiguous(layout Attributes) bool {
+	if d.Size() == 0 {
+		return true
+	}
+	axes := make([]int, len(d.Shape))
+	for i := range axes {
+		switch layout {
+		case RowMajorLayout:
+			axes[i] = len(d.Shape) - 1 - i
+		case ColumnMajorLayout:
+			axes[i] = i
+		default:
+			return false
+		}
+	}
+	expected := d.DType.Size()
+	for _, axis := range axes {
+		if d.Shape[axis] != 1 && d.Strides[axis] != expected {
+			return false
+		}
+		expected *= d.Shape[axis]
+	}
+	return true
+}
+
+// Copy returns a new writeable array with the shape, data-type and items
+// of d, laid out in the given layout, RowMajorLayout or
+// ColumnMajorLayout.  Copies of complex arrays are complex arrays.  A zero layout keeps the layout of d when it is
+// row-major, and makes column-major copies otherwise.
+func (d *Dense) Copy(layout Attributes) (*Dense, error) {
+	if layout == 0 {
+		layout = ColumnMajorLayout
+		if d.IsContiguous(RowMajorLayout) && !d.IsContiguous(ColumnMajorLayout) {
+			layout = RowMajorLayout
+		}
+	}
+	if layout != RowMajorLayout && layout != ColumnMajorLayout {
+		return nil, ErrInvalidLayout
+	}
+	out, err := NewDense(append(Shape{}, d.Shape...),
+		Contiguous|Writeable|layout|d.Attrs&ComplexItems)
+	if err != nil {
+		return nil, err
+	}
+	out.DType = d.DType
+	iterate(d.Shape, []*Dense{out, d}, func(pos []int) {
+		// Items are copied as stored, in the byte order of both arrays.
+		out.Data[pos[0]] = d.Data[pos[1]]
+	})
+	return out, nil
+}
+
+// AsRowMajor returns d laid out in row-major order: an array sharing
+// the items of d, with attributes set to match, when they already are,
+// and a copy otherwise.
+func (d *Dense) AsRowMajor() (*Dense, error) {
+	return d.asLayout(RowMajorLayout)
+}
+
+// AsColumnMajor returns d laid out in column-major order: an array
+// sharing the items of d, with attributes set to match, when they
+// already are, and a copy otherwise.
+func (d *Dense) AsColumnMajor() (*Dense, error) {
+	return d.asLayout(ColumnMajorLayout)
+}
+
+func (d *Dense) asLayout(layout Attributes) (*Dense, error) {
+	if !d.IsContiguous(layout) {
+		return d.Copy(layout)
+	}
+	attrs := d.Attrs&^(RowMajorLayout|ColumnMajorLayout) | Contiguous | layout
+	if attrs == d.Attrs {
+		return d, nil
+	}
+	return &Dense{
+		Data:    d.Data,
+		DType:   d.DType,
+		Shape:   d.Shape,
+		Strides: d.Strides,
+		Attrs:   attrs,
+	}, nil
+}
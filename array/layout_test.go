@@ -0,0 +1,102 @@
+package array_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestIsContiguous(t *testing.T) {
+	col, _ := array.NewDense(array.Shape{2, 3}, array.DefaultAttributes)
+	assert.True(t, col.IsContiguous(array.ColumnMajorLayout))
+	assert.False(t, col.IsContiguous(array.RowMajorLayout))
+	row, _ := array.NewDense(array.Shape{2, 3}, array.Contiguous|array.RowMajorLayout)
+	assert.True(t, row.IsContiguous(array.RowMajorLayout))
+	assert.False(t, row.IsContiguous(array.ColumnMajorLayout))
+
+	// Vectors and axes of one item are contiguous in both layouts.
+	for _, shape := range []array.Shape{{5}, {1, 4}, {4, 1}, {}, {0, 3}} {
+		d, _ := array.NewDense(shape, array.DefaultAttributes)
+		assert.True(t, d.IsContiguous(array.RowMajorLayout), "%v", shape)
+		assert.True(t, d.IsContiguous(array.ColumnMajorLayout), "%v", shape)
+	}
+
+	// Strides are trusted over attributes.
+	lying, err := array.NewDenseWithStrides(array.Shape{2, 2}, array.Strides{16, 8},
+		array.DefaultAttributes)
+	if assert.NoError(t, err) {
+		assert.False(t, lying.IsContiguous(array.ColumnMajorLayout))
+		assert.True(t, lying.IsContiguous(array.RowMajorLayout))
+	}
+	overlapping, err := array.NewDenseWithStrides(array.Shape{2, 2}, array.Strides{8, 8},
+		array.DefaultAttributes)
+	if assert.NoError(t, err) {
+		assert.False(t, overlapping.IsContiguous(array.ColumnMajorLayout))
+		assert.False(t, overlapping.IsContiguous(array.RowMajorLayout))
+	}
+	b, _ := array.Scalar(1).BroadcastTo(array.Shape{3})
+	assert.False(t, b.IsContiguous(array.RowMajorLayout))
+	assert.False(t, col.IsContiguous(array.Contiguous))
+}
+
+func TestCopy(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6}
+	col, _ := array.NewDenseWithValues(array.Shape{2, 3}, values, array.DefaultAttributes)
+
+	row, err := col.Copy(array.RowMajorLayout)
+	if assert.NoError(t, err) {
+		assert.Equal(t, values, row.Data)
+		assert.Equal(t, array.Strides{24, 8}, row.Strides)
+		assert.Equal(t, array.Contiguous|array.Writeable|array.RowMajorLayout, row.Attrs)
+		assert.True(t, array.ArrayEqual(col, row))
+	}
+	kept, err := row.Copy(0)
+	if assert.NoError(t, err) {
+		assert.True(t, kept.IsContiguous(array.RowMajorLayout))
+		// Copies do not share memory.
+		assert.NoError(t, kept.Set(array.Indices{0, 0}, 10))
+		assert.Equal(t, 1.0, row.Data[0])
+	}
+
+	// Copies of views are writeable and keep the data-type.
+	s := array.Scalar(2)
+	assert.NoError(t, s.ByteSwap())
+	b, _ := s.BroadcastTo(array.Shape{2, 2})
+	c, err := b.Copy(array.ColumnMajorLayout)
+	if assert.NoError(t, err) {
+		assert.Equal(t, b.DType, c.DType)
+		assert.Equal(t, []float64{2, 2, 2, 2}, c.Values())
+		assert.NoError(t, c.Set(array.Indices{0, 1}, 3))
+	}
+
+	_, err = col.Copy(array.Writeable)
+	assert.Equal(t, array.ErrInvalidLayout, err)
+}
+
+func TestAsLayout(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6}
+	col, _ := array.NewDenseWithValues(array.Shape{2, 3}, values, array.DefaultAttributes)
+
+	same, err := col.AsColumnMajor()
+	if assert.NoError(t, err) {
+		assert.Same(t, col, same)
+	}
+	row, err := col.AsRowMajor()
+	if assert.NoError(t, err) {
+		assert.True(t, row.IsContiguous(array.RowMajorLayout))
+		assert.Equal(t, values, row.Data)
+		assert.True(t, array.ArrayEqual(col, row))
+	}
+
+	// Attributes that lie are fixed without copying.
+	lying, _ := array.NewDenseWithStrides(array.Shape{2, 3}, array.Strides{24, 8},
+		array.DefaultAttributes)
+	fixed, err := lying.AsRowMajor()
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Contiguous|array.Writeable|array.RowMajorLayout, fixed.Attrs)
+		assert.NoError(t, fixed.Set(array.Indices{1, 2}, 7))
+		assert.Equal(t, 7.0, lying.Data[5])
+	}
+}
@@ -0,0 +1,30 @@
+package array
+
+// Linspace returns a Dense array with num values evenly spaced over
+// the interval `[start, stop]`, or `[start, stop)` when endpoint is
+// false.
+func Linspace(start, stop float64, num int, endpoint bool) (*Dense, error) {
+	if num < 0 {
+		return nil, &Error{
+			Operation: "linspace",
+			Message:   "number of samples must be non-negative",
+		}
+	}
+	d, err := NewDense(Shape{num}, DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	div := num
+	if endpoint {
+		div = num - 1
+	}
+	if div > 0 {
+		d.Fill(start, (stop-start)/float64(div))
+	} else {
+		d.Fill(start, 0)
+	}
+	if endpoint && num > 1 {
+		d.Data[num-1] = stop
+	}
+	return d, nil
+}
@@ -0,0 +1,40 @@
+package array_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestLinspace(t *testing.T) {
+	arr, err := array.Linspace(0, 1, 5, true)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{5}, arr.Shape)
+		assert.Equal(t, []float64{0, .25, .5, .75, 1}, arr.Data)
+	}
+	arr, err = array.Linspace(0, 1, 4, false)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, .25, .5, .75}, arr.Data)
+	}
+	// The endpoint is exact even when the step is not representable.
+	arr, err = array.Linspace(0, 0.3, 4, true)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.1, arr.Data[1], 1e-15)
+		assert.Equal(t, 0.3, arr.Data[3])
+	}
+	arr, err = array.Linspace(2, 3, 1, true)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{2}, arr.Data)
+	}
+	arr, err = array.Linspace(2, 3, 0, true)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0, arr.Size())
+	}
+	_, err = array.Linspace(0, 1, -1, true)
+	if assert.Error(t, err) {
+		serr := err.(*array.Error)
+		assert.Equal(t, "linspace", serr.Operation)
+	}
+}
@@ -0,0 +1,48 @@
+package array
+
+import "context"
+
+// MatMul returns the matrix product of a, an m×k matrix, and b, a k×n
+// matrix, in a new m×n array.  Rows of the product are split among
+// goroutines, and every item is summed in the same order whatever their
+// number.
+func MatMul(a, b *Dense) (*Dense, error) {
+	return MatMulContext(context.Background(), a, b)
+}
+
+// MatMulContext is like MatMul, but stops early and returns an error
+// wrapping the error of ctx once it is done.
+func MatMulContext(ctx context.Context, a, b *Dense) (*Dense, error) {
+	if len(a.Shape) != 2 || len(b.Shape) != 2 || a.Shape[1] != b.Shape[0] {
+		return nil, &Error{Operation: "matmul", Message: "shapes are not aligned"}
+	}
+	m, k, n := a.Shape[0], a.Shape[1], b.Shape[1]
+	out, err := NewDense(Shape{m, n}, DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	av, bv := a.Values(), b.Values()
+	rowStride := out.Strides[0] / out.DType.Size()
+	columnStride := out.Strides[1] / out.DType.Size()
+	err = parallelFor(ctx, m, 1, k*n, func(lo, hi int) {
+		row := make([]float64, n)
+		for i := lo; i < hi; i++ {
+			for j := range row {
+				row[j] = 0
+			}
+			for p := 0; p < k; p++ {
+				x := av[i*k+p]
+				for j, y := range bv[p*n : (p+1)*n] {
+					row[j] += x * y
+				}
+			}
+			for j, v := range row {
+				out.Data[i*rowStride+j*columnStride] = v
+			}
+		}
+	})
+	if err != nil {
+		return nil, canceled(ctx, "matmul")
+	}
+	return out, nil
+}
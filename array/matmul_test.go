@@ -0,0 +1,33 @@
+package array_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestMatMul(t *testing.T) {
+	a, _ := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{1, -2, 3, 0.5, 5, -1}, array.DefaultAttributes)
+	b, _ := array.NewDenseWithValues(array.Shape{3, 2},
+		[]float64{2, 1, 0, -1, 3, 4}, array.Contiguous|array.Writeable|array.RowMajorLayout)
+	c, err := array.MatMul(a, b)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, c.Shape)
+		assert.Equal(t, []float64{11, 15, -2, -8.5}, c.Values())
+	}
+
+	// Zeros times infinities give NaN, as in IEEE arithmetic.
+	zero, _ := array.NewDenseWithValues(array.Shape{1, 1}, []float64{0}, array.DefaultAttributes)
+	inf, _ := array.NewDenseWithValues(array.Shape{1, 1}, []float64{math.Inf(1)}, array.DefaultAttributes)
+	c, err = array.MatMul(zero, inf)
+	if assert.Nil(t, err) {
+		assert.True(t, math.IsNaN(c.Values()[0]))
+	}
+
+	_, err = array.MatMul(a, a)
+	assert.IsType(t, &array.Error{}, err)
+}
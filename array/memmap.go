@@ -0,0 +1,275 @@
+package array
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// MemmapMode specifies how the file behind a memory-mapped array is
+// opened.
+type MemmapMode int
+
+const (
+	// ReadOnly maps the file for reading.  The array is not Writeable.
+	ReadOnly MemmapMode = iota
+
+	// ReadWrite maps the file for reading and writing.  Changes to the
+	// array are carried to the file.
+	ReadWrite
+
+	// CopyOnWrite maps the file for reading and writing, but changes to
+	// the array are private and never reach the file.
+	CopyOnWrite
+)
+
+func (m MemmapMode) String() string {
+	switch m {
+	case ReadOnly:
+		return "ReadOnly"
+	case ReadWrite:
+		return "ReadWrite"
+	case CopyOnWrite:
+		return "CopyOnWrite"
+	}
+	return "MemmapMode(?)"
+}
+
+// mapping is the memory-mapped region of a file holding the Data of an
+// array.
+type mapping struct {
+	region []byte
+	mode   MemmapMode
+}
+
+// mappings holds the regions mapped by OpenRawMemmap until they are
+// unmapped by CloseMemmap, by the address of their first byte.
+var (
+	mappingsMu sync.Mutex
+	mappings   = make(map[uintptr]*mapping)
+)
+
+// lookupMapping returns the mapping holding the Data of d, or nil.
+// The caller must hold mappingsMu.
+func lookupMapping(d *Dense) (uintptr, *mapping) {
+	if len(d.Data) == 0 {
+		return 0, nil
+	}
+	addr := uintptr(unsafe.Pointer(&d.Data[0]))
+	for start, m := range mappings {
+		if addr >= start && addr < start+uintptr(len(m.region)) {
+			return start, m
+		}
+	}
+	return 0, nil
+}
+
+// OpenMemmap maps the items of a .npy file holding float64 items, so
+// that arrays larger than the available memory can be processed.  The
+// Data of the array lives in the mapped file instead of memory
+// allocated by the Go runtime; pages of the file are read on demand,
+// and may be dropped by the operating system while not in use.  The
+// array must be released with CloseMemmap.
+//
+// Arrays saved in Fortran order have the ColumnMajorLayout attribute,
+// and the others RowMajorLayout.  Items stored in the byte order of
+// the machine have the Float64 data-type, and the others
+// LittleEndianFloat64 or BigEndianFloat64.
+func OpenMemmap(path string, mode MemmapMode) (*Dense, error) {
+	return OpenMemmapContext(context.Background(), path, mode)
+}
+
+// OpenMemmapContext is like OpenMemmap, but stops early and returns an
+// error wrapping the error of ctx once it is done.  Only the header is
+// read while opening the file; the items are read on demand afterwards,
+// out of reach of ctx.
+func OpenMemmapContext(
+	ctx context.Context, path string, mode MemmapMode,
+) (*Dense, error) {
+	if err := canceled(ctx, "memmap"); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	h, offset, err := readNpyHeader(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	dtype := DType(h.descr)
+	if dtype != LittleEndianFloat64 && dtype != BigEndianFloat64 {
+		return nil, npyError("unsupported descr " + h.descr)
+	}
+	if err := canceled(ctx, "memmap"); err != nil {
+		return nil, err
+	}
+	d, err := OpenRawMemmap(path, mode, offset, h.shape, h.attributes())
+	if err != nil {
+		return nil, err
+	}
+	if !dtype.Native() {
+		d.DType = dtype
+	}
+	return d, nil
+}
+
+// OpenRawMemmap maps the items of a file holding float64 items in the
+// byte order of the machine, with no header.  The items start at
+// offset bytes, which must be a multiple of eight, and follow the
+// layout given in attrs; as in NewDense, only the layout and
+// contiguity attributes are taken from attrs.  The array must be
+// released with CloseMemmap.
+func OpenRawMemmap(
+	path string, mode MemmapMode, offset int64, shape Shape, attrs Attributes,
+) (*Dense, error) {
+	if mode < ReadOnly || mode > CopyOnWrite {
+		return nil, &Error{Operation: "memmap", Message: "invalid mode"}
+	}
+	if offset < 0 || offset%int64(Float64.Size()) != 0 {
+		return nil, &Error{Operation: "memmap", Message: "misaligned offset"}
+	}
+	attrs &^= Writeable
+	if mode != ReadOnly {
+		attrs |= Writeable
+	}
+	strides, err := NewStrides(shape, Float64, attrs)
+	if err != nil {
+		return nil, err
+	}
+	n, err := shape.Size()
+	if err != nil {
+		return nil, err
+	}
+	flag := os.O_RDONLY
+	if mode == ReadWrite {
+		flag = os.O_RDWR
+	}
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	end := offset + int64(n)*int64(Float64.Size())
+	if info.Size() < end {
+		return nil, &Error{Operation: "memmap", Message: "file is too short"}
+	}
+	d := &Dense{
+		Data:    []float64{},
+		DType:   Float64,
+		Shape:   shape,
+		Strides: strides,
+		Attrs:   attrs,
+	}
+	if n == 0 {
+		return d, nil
+	}
+	// The region starts at the beginning of the file, so that its
+	// offset is aligned to a page.
+	region, err := mmap(f, int(end), mode)
+	if err != nil {
+		return nil, &Error{Operation: "memmap", Message: "cannot map file", Err: err}
+	}
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&d.Data))
+	header.Data = uintptr(unsafe.Pointer(&region[offset]))
+	header.Len = n
+	header.Cap = n
+	mappingsMu.Lock()
+	mappings[uintptr(unsafe.Pointer(&region[0]))] = &mapping{region: region, mode: mode}
+	mappingsMu.Unlock()
+	return d, nil
+}
+
+// CreateMemmap creates a .npy file, or truncates an existing one, for
+// an array of zeros with the given shape, and maps it in ReadWrite
+// mode.  The file is sparse where supported, so that creating it does
+// not write its items.  The array must be released with CloseMemmap.
+func CreateMemmap(path string, shape Shape, attrs Attributes) (*Dense, error) {
+	if err := shape.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := NewStrides(shape, Float64, attrs); err != nil {
+		return nil, err
+	}
+	n, err := shape.Size()
+	if err != nil {
+		return nil, err
+	}
+	header := encodeNpyHeader(Float64, shape, attrs.Is(ColumnMajorLayout))
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.Write(header)
+	if err == nil {
+		err = f.Truncate(int64(len(header)) + int64(n)*int64(Float64.Size()))
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return OpenRawMemmap(path, ReadWrite, int64(len(header)), shape, attrs)
+}
+
+// FlushMemmap writes the changes to an array mapped in ReadWrite mode,
+// or to a view of it, to its file.  Changes are otherwise written back
+// at the discretion of the operating system, and at the latest by
+// CloseMemmap.
+func FlushMemmap(d *Dense) error {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+	_, m := lookupMapping(d)
+	if m == nil {
+		return errNotMapped(d)
+	}
+	return m.flush()
+}
+
+// CloseMemmap flushes an array mapped in ReadWrite mode and unmaps its
+// file.  The array is left empty.  The Data of every view of the array
+// must not be used afterwards.
+func CloseMemmap(d *Dense) error {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+	start, m := lookupMapping(d)
+	if m == nil {
+		return errNotMapped(d)
+	}
+	err := m.flush()
+	if uerr := munmap(m.region); err == nil && uerr != nil {
+		err = &Error{Operation: "memmap", Message: "cannot unmap file", Err: uerr}
+	}
+	delete(mappings, start)
+	d.Data = []float64{}
+	d.Shape = Shape{0}
+	d.Strides = Strides{Float64.Size()}
+	return err
+}
+
+// errNotMapped returns nil for empty arrays, for which no file is
+// mapped, and an error for the others.
+func errNotMapped(d *Dense) error {
+	if len(d.Data) == 0 {
+		return nil
+	}
+	return &Error{Operation: "memmap", Message: "array is not memory-mapped"}
+}
+
+func (m *mapping) flush() error {
+	if m.mode != ReadWrite {
+		return nil
+	}
+	if err := msync(m.region); err != nil {
+		return &Error{Operation: "memmap", Message: "cannot flush", Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package array_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "array")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCreateMemmap(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.npy")
+
+	m, err := array.CreateMemmap(path, array.Shape{2, 3},
+		array.Contiguous|array.RowMajorLayout)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, m.Attrs.Is(array.Writeable))
+	m.Fill(1, 1)
+	assert.Nil(t, m.Set(array.Indices{1, 2}, -6))
+	assert.Nil(t, array.CloseMemmap(m))
+	assert.Equal(t, 0, m.Size())
+	assert.Nil(t, array.CloseMemmap(m))
+
+	// The file is a valid .npy file whose items start at a multiple of
+	// 64 bytes.
+	raw, err := ioutil.ReadFile(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "\x93NUMPY\x01\x00", string(raw[:8]))
+	length := int(binary.LittleEndian.Uint16(raw[8:10]))
+	assert.Equal(t, 0, (10+length)%64)
+	assert.Contains(t, string(raw[10:10+length]),
+		"'fortran_order': False, 'shape': (2, 3), }")
+	assert.Equal(t, 10+length+6*8, len(raw))
+
+	for _, mode := range []array.MemmapMode{array.ReadOnly, array.CopyOnWrite} {
+		m, err = array.OpenMemmap(path, mode)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, array.Shape{2, 3}, m.Shape)
+		assert.True(t, m.Attrs.Is(array.RowMajorLayout))
+		assert.Equal(t, []float64{1, 2, 3, 4, 5, -6}, m.Values())
+		assert.Nil(t, array.CloseMemmap(m))
+	}
+}
+
+func TestMemmapModes(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.npy")
+	m, err := array.CreateMemmap(path, array.Shape{3, 2}, array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	m.Fill(0, 0.5)
+	assert.Nil(t, array.CloseMemmap(m))
+
+	ro, err := array.OpenMemmap(path, array.ReadOnly)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer array.CloseMemmap(ro)
+	assert.False(t, ro.Attrs.Is(array.Writeable))
+	assert.True(t, ro.Attrs.Is(array.ColumnMajorLayout))
+	assert.Equal(t, array.ErrNotWriteable, ro.Set(array.Indices{0, 0}, 1))
+	assert.Panics(t, func() { ro.Fill(1, 0) })
+
+	cow, err := array.OpenMemmap(path, array.CopyOnWrite)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, cow.Set(array.Indices{2, 1}, 42))
+	v, _ := cow.Get(array.Indices{2, 1})
+	assert.Equal(t, 42.0, v)
+	assert.Nil(t, array.CloseMemmap(cow))
+
+	rw, err := array.OpenMemmap(path, array.ReadWrite)
+	if !assert.Nil(t, err) {
+		return
+	}
+	v, _ = rw.Get(array.Indices{2, 1})
+	assert.Equal(t, 2.5, v)
+	assert.Nil(t, rw.Set(array.Indices{2, 1}, math.Pi))
+	// Views of the array are flushed with it.
+	view := &array.Dense{
+		Data:    rw.Data[5:],
+		DType:   rw.DType,
+		Shape:   array.Shape{1},
+		Strides: array.Strides{8},
+		Attrs:   rw.Attrs,
+	}
+	assert.Nil(t, array.FlushMemmap(view))
+	// The shared map sees the change before the writer is closed.
+	v, _ = ro.Get(array.Indices{2, 1})
+	assert.Equal(t, math.Pi, v)
+	assert.Nil(t, array.CloseMemmap(rw))
+
+	// Arrays allocated by the runtime are not memory-mapped.
+	assert.NotNil(t, array.CloseMemmap(array.Scalar(1)))
+}
+
+func TestOpenRawMemmap(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.bin")
+	raw := make([]byte, 16+4*8)
+	for i := 0; i < 4; i++ {
+		bits := math.Float64bits(float64(i + 1))
+		binary.LittleEndian.PutUint64(raw[16+8*i:], bits)
+	}
+	if err := ioutil.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	attrs := array.Contiguous | array.RowMajorLayout
+	m, err := array.OpenRawMemmap(path, array.ReadOnly, 16, array.Shape{2, 2}, attrs)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 2, 3, 4}, m.Values())
+		assert.Nil(t, array.CloseMemmap(m))
+	}
+
+	_, err = array.OpenRawMemmap(path, array.ReadOnly, 4, array.Shape{2}, attrs)
+	assert.NotNil(t, err)
+	_, err = array.OpenRawMemmap(path, array.ReadOnly, 16, array.Shape{5}, attrs)
+	assert.NotNil(t, err)
+	_, err = array.OpenMemmap(path, array.ReadOnly)
+	assert.NotNil(t, err)
+}
+
+func TestOpenMemmapContext(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.npy")
+	m, err := array.CreateMemmap(path, array.Shape{2}, array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	m.Fill(1, 1)
+	assert.Nil(t, array.CloseMemmap(m))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m, err = array.OpenMemmapContext(ctx, path, array.ReadOnly)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 2}, m.Values())
+		assert.Nil(t, array.CloseMemmap(m))
+	}
+	cancel()
+	_, err = array.OpenMemmapContext(ctx, path, array.ReadOnly)
+	assert.ErrorIs(t, err, context.Canceled)
+}
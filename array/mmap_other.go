@@ -0,0 +1,23 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux
+// +build !darwin,!dragonfly,!freebsd,!linux
+
+package array
+
+import (
+	"errors"
+	"os"
+)
+
+var errMmapUnsupported = errors.New("memory maps are not supported on this platform")
+
+func mmap(f *os.File, length int, mode MemmapMode) ([]byte, error) {
+	return nil, errMmapUnsupported
+}
+
+func msync(region []byte) error {
+	return errMmapUnsupported
+}
+
+func munmap(region []byte) error {
+	return errMmapUnsupported
+}
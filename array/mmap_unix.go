@@ -0,0 +1,37 @@
+//go:build darwin || dragonfly || freebsd || linux
+// +build darwin dragonfly freebsd linux
+
+package array
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func mmap(f *os.File, length int, mode MemmapMode) ([]byte, error) {
+	prot := syscall.PROT_READ
+	flags := syscall.MAP_SHARED
+	switch mode {
+	case ReadWrite:
+		prot |= syscall.PROT_WRITE
+	case CopyOnWrite:
+		prot |= syscall.PROT_WRITE
+		flags = syscall.MAP_PRIVATE
+	}
+	return syscall.Mmap(int(f.Fd()), 0, length, prot, flags)
+}
+
+func msync(region []byte) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC,
+		uintptr(unsafe.Pointer(&region[0])), uintptr(len(region)),
+		syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func munmap(region []byte) error {
+	return syscall.Munmap(region)
+}
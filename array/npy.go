@@ -0,0 +1,151 @@
+package array
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// npyMagic starts every file in the NumPy .npy format.
+const npyMagic = "\x93NUMPY"
+
+var (
+	npyDescr   = regexp.MustCompile(`['"]descr['"]\s*:\s*['"]([^'"]*)['"]`)
+	npyFortran = regexp.MustCompile(`['"]fortran_order['"]\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`['"]shape['"]\s*:\s*\(([^)]*)\)`)
+)
+
+// npyHeader describes the items stored in a .npy file.
+type npyHeader struct {
+	descr   string
+	fortran bool
+	shape   Shape
+}
+
+// attributes returns the attributes of an array laid out as in the
+// header.
+func (h *npyHeader) attributes() Attributes {
+	if h.fortran {
+		return Contiguous | ColumnMajorLayout
+	}
+	return Contiguous | RowMajorLayout
+}
+
+func npyError(message string) error {
+	return &Error{Operation: "npy", Message: message}
+}
+
+// readNpyHeader reads the header of a .npy file from r, returning it
+// along with the number of bytes read, which is the offset of the
+// items in the file.
+func readNpyHeader(r io.Reader) (*npyHeader, int64, error) {
+	prefix := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, 0, npyError("missing magic string")
+	}
+	if string(prefix[:len(npyMagic)]) != npyMagic {
+		return nil, 0, npyError("missing magic string")
+	}
+	var length int64
+	offset := int64(len(prefix))
+	switch major := prefix[len(npyMagic)]; major {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, 0, err
+		}
+		length = int64(n)
+		offset += 2
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, 0, err
+		}
+		length = int64(n)
+		offset += 4
+	default:
+		return nil, 0, npyError(fmt.Sprintf("unsupported version %d", major))
+	}
+	text := make([]byte, length)
+	if _, err := io.ReadFull(r, text); err != nil {
+		return nil, 0, npyError("truncated header")
+	}
+	h, err := parseNpyHeader(string(text))
+	if err != nil {
+		return nil, 0, err
+	}
+	return h, offset + length, nil
+}
+
+// parseNpyHeader parses the Python dictionary literal of a .npy header.
+func parseNpyHeader(text string) (*npyHeader, error) {
+	descr := npyDescr.FindStringSubmatch(text)
+	fortran := npyFortran.FindStringSubmatch(text)
+	shape := npyShape.FindStringSubmatch(text)
+	if descr == nil || fortran == nil || shape == nil {
+		return nil, npyError("malformed header")
+	}
+	h := &npyHeader{descr: descr[1], fortran: fortran[1] == "True"}
+	for _, field := range strings.Split(shape[1], ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(field, "L"))
+		if err != nil {
+			return nil, npyError("malformed shape")
+		}
+		h.shape = append(h.shape, n)
+	}
+	if h.shape == nil {
+		h.shape = Shape{}
+	}
+	if err := h.shape.Validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// encodeNpyHeader returns the header of a .npy file holding items of
+// dtype with the given shape and layout, padded so that the items start
+// at a multiple of 64 bytes.
+func encodeNpyHeader(dtype DType, shape Shape, fortran bool) []byte {
+	dims := make([]string, len(shape))
+	for i, n := range shape {
+		dims[i] = strconv.Itoa(n)
+	}
+	tuple := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		tuple += ","
+	}
+	order := "False"
+	if fortran {
+		order = "True"
+	}
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': (%s), }",
+		dtype.explicit(), order, tuple)
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	prefix := len(npyMagic) + 2 + 2
+	major := byte(1)
+	if prefix+len(dict)+1 > 1<<16 {
+		major = 2
+		prefix += 2
+	}
+	total := (prefix + len(dict) + 1 + 63) / 64 * 64
+	length := total - prefix
+	buf.Write([]byte{major, 0})
+	if major == 1 {
+		binary.Write(&buf, binary.LittleEndian, uint16(length))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(length))
+	}
+	buf.WriteString(dict)
+	buf.WriteString(strings.Repeat(" ", length-len(dict)-1))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
@@ -0,0 +1,156 @@
+package array
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minParallelItems is the amount of work, in items, handed to a
+// goroutine at a time.  Smaller amounts do not amortize the cost of
+// scheduling, and larger ones delay the detection of cancellation.
+const minParallelItems = 1 << 15
+
+// numThreads holds the value set by SetNumThreads, zero meaning the
+// default.
+var numThreads int32
+
+// SetNumThreads sets the maximum number of goroutines among which a
+// single operation is split, and returns the previous maximum.  Values
+// less than one restore the default, runtime.GOMAXPROCS(0).  Results do
+// not depend on the number of goroutines.
+func SetNumThreads(n int) int {
+	previous := NumThreads()
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt32(&numThreads, int32(n))
+	return previous
+}
+
+// NumThreads returns the maximum number of goroutines among which a
+// single operation is split.
+func NumThreads() int {
+	if n := atomic.LoadInt32(&numThreads); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// parallelFor splits the range [0, n) into contiguous chunks whose
+// boundaries are multiples of grain and calls fn for every chunk,
+// concurrently when the work is large enough.  Every index costs the
+// processing of cost items.  Chunks are small enough for ctx to be
+// checked regularly; once it is done, no further chunk is started and
+// its error is returned.
+func parallelFor(ctx context.Context, n, grain, cost int, fn func(lo, hi int)) error {
+	if grain < 1 {
+		grain = 1
+	}
+	if cost < 1 {
+		cost = 1
+	}
+	grains := minParallelItems / (grain * cost)
+	if grains < 1 {
+		grains = 1
+	}
+	chunk := grains * grain
+	chunks := (n + chunk - 1) / chunk
+	var next int64
+	var stopped int32
+	work := func() {
+		for {
+			c := int(atomic.AddInt64(&next, 1) - 1)
+			if c >= chunks {
+				return
+			}
+			if ctx.Err() != nil {
+				atomic.StoreInt32(&stopped, 1)
+				return
+			}
+			lo := c * chunk
+			hi := lo + chunk
+			if hi > n {
+				hi = n
+			}
+			fn(lo, hi)
+		}
+	}
+	workers := NumThreads()
+	if chunks < workers {
+		workers = chunks
+	}
+	var wg sync.WaitGroup
+	for i := 1; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+	if atomic.LoadInt32(&stopped) != 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// outerBlock returns the number of items spanned by the trailing axes
+// of shape after the shortest run of leading axes that can keep every
+// goroutine busy, so that splitting a row-major traversal at multiples
+// of it partitions the outer axes.
+func outerBlock(shape Shape) int {
+	workers := NumThreads()
+	outer := 1
+	for axis, n := range shape {
+		if outer >= workers {
+			block := 1
+			for _, m := range shape[axis:] {
+				block *= m
+			}
+			return block
+		}
+		outer *= n
+	}
+	return 1
+}
+
+// pairwiseBlock is the length below which pairwiseSum adds items in
+// sequence.
+const pairwiseBlock = 128
+
+// pairwiseSum returns the sum of values by recursive halving, whose
+// rounding error grows with the logarithm of the length instead of the
+// length itself.
+func pairwiseSum(values []float64) float64 {
+	if len(values) <= pairwiseBlock {
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	}
+	half := len(values) / 2
+	return pairwiseSum(values[:half]) + pairwiseSum(values[half:])
+}
+
+// parallelPairwiseSum returns the same value as pairwiseSum, evaluating
+// the upper levels of the summation tree on up to workers goroutines.
+func parallelPairwiseSum(values []float64, workers int) float64 {
+	if workers <= 1 || len(values) < minParallelItems {
+		return pairwiseSum(values)
+	}
+	half := len(values) / 2
+	var left float64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		left = parallelPairwiseSum(values[:half], workers/2)
+	}()
+	right := parallelPairwiseSum(values[half:], workers-workers/2)
+	wg.Wait()
+	return left + right
+}
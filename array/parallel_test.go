@@ -0,0 +1,108 @@
+package array_test
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestSetNumThreads(t *testing.T) {
+	defer array.SetNumThreads(0)
+	array.SetNumThreads(3)
+	assert.Equal(t, 3, array.NumThreads())
+	assert.Equal(t, 3, array.SetNumThreads(-1))
+	assert.Equal(t, runtime.GOMAXPROCS(0), array.NumThreads())
+}
+
+// wave returns a large row-major array with the given shape.
+func wave(t *testing.T, shape array.Shape) *array.Dense {
+	t.Helper()
+	d, err := array.NewDense(shape,
+		array.Contiguous|array.Writeable|array.RowMajorLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range d.Data {
+		d.Data[i] = math.Sin(float64(i)) * math.Exp(float64(i%97)/10)
+	}
+	return d
+}
+
+func TestParallelMatchesSerial(t *testing.T) {
+	defer array.SetNumThreads(0)
+	a := wave(t, array.Shape{300, 7, 50})
+	b := wave(t, array.Shape{7, 1})
+	left, right := wave(t, array.Shape{2100, 50}), wave(t, array.Shape{50, 40})
+	flat, err := array.NewDenseWithValues(
+		array.Shape{a.Size()}, a.Values(), array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(threads int) []*array.Dense {
+		array.SetNumThreads(threads)
+		mapped, err := array.Map2(a, b, math.Atan2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := a.Diff(2, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum, err := array.Sum(a, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total, err := array.Sum(flat, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		product, err := array.MatMul(left, right)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []*array.Dense{mapped, diff, sum, total, product}
+	}
+	serial := run(1)
+	for _, threads := range []int{2, 5, 32} {
+		for i, out := range run(threads) {
+			assert.Equal(t, serial[i].Shape, out.Shape)
+			assert.Equal(t, serial[i].Data, out.Data, "output %d with %d threads", i, threads)
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	defer array.SetNumThreads(0)
+	d, err := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{1, 2, 3, 4, 5, 6}, array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	sum, err := array.Sum(d, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{5, 7, 9}, sum.Values())
+	}
+	sum, err = array.Sum(d, -1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{6, 15}, sum.Values())
+	}
+	assert.Equal(t, 21.0, array.SumAll(d))
+
+	// Pairwise summation keeps the error far below that of a running
+	// sum, and does not depend on the number of goroutines.
+	n := 1 << 20
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 0.1
+	}
+	v, _ := array.NewDenseWithValues(array.Shape{n}, values, array.DefaultAttributes)
+	array.SetNumThreads(1)
+	serial := array.SumAll(v)
+	array.SetNumThreads(7)
+	assert.Equal(t, serial, array.SumAll(v))
+	assert.InDelta(t, 0.1*float64(n), serial, 1e-8)
+}
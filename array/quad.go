@@ -0,0 +1,214 @@
+package array
+
+import (
+	"container/heap"
+	"context"
+	"math"
+)
+
+// QuadOptions controls the accuracy of Quad.
+type QuadOptions struct {
+	// AbsTol is the absolute error tolerance.
+	AbsTol float64
+	// RelTol is the error tolerance relative to the integral.
+	RelTol float64
+	// MaxSubintervals limits the number of subintervals of the
+	// adaptive subdivision.
+	MaxSubintervals int
+}
+
+// DefaultQuadOptions holds the tolerances used by Quad when no options
+// are given.
+var DefaultQuadOptions = QuadOptions{
+	AbsTol:          1.49e-8,
+	RelTol:          1.49e-8,
+	MaxSubintervals: 50,
+}
+
+// Gauss-Kronrod 7-15 abscissae and weights.  The Gauss nodes are the
+// odd-indexed Kronrod nodes.
+var (
+	kronrodNodes = [8]float64{
+		0.991455371120812639206854697526329,
+		0.949107912342758524526189684047851,
+		0.864864423359769072789712788640926,
+		0.741531185599394439863864773280788,
+		0.586087235467691130294144845693013,
+		0.405845151377397166906606412076961,
+		0.207784955007898467600689403773245,
+		0,
+	}
+	kronrodWeights = [8]float64{
+		0.022935322010529224963732008058970,
+		0.063092092629978553290700663189204,
+		0.104790010322250183839876322541518,
+		0.140653259715525918745189590510238,
+		0.169004726639267902826583426598550,
+		0.190350578064785409913256402421014,
+		0.204432940075298892414161999234649,
+		0.209482141084727828012999174891714,
+	}
+	gaussWeights = [4]float64{
+		0.129484966168869693270611432679082,
+		0.279705391489276667901467771423780,
+		0.381830050505118944950369775488975,
+		0.417959183673469387755102040816327,
+	}
+)
+
+// Quad integrates f from a to b using globally adaptive Gauss-Kronrod
+// quadrature, returning the integral and an estimate of its absolute
+// error.  Either bound may be infinite.  When opts is nil,
+// DefaultQuadOptions is used.  If the tolerances cannot be met within
+// the subinterval limit, the best estimate is returned along with an
+// error.
+func Quad(
+	f func(float64) float64, a, b float64, opts *QuadOptions,
+) (float64, float64, error) {
+	return QuadContext(context.Background(), f, a, b, opts)
+}
+
+// QuadContext is like Quad, but checks ctx before every subdivision and
+// returns the current estimates along with an error wrapping the error
+// of ctx once it is done.
+func QuadContext(
+	ctx context.Context, f func(float64) float64, a, b float64, opts *QuadOptions,
+) (float64, float64, error) {
+	if opts == nil {
+		opts = &DefaultQuadOptions
+	}
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		return math.NaN(), math.NaN(), &Error{
+			Operation: "quad",
+			Message:   "integration bounds must not be NaN",
+		}
+	case a == b:
+		return 0, 0, nil
+	case a > b:
+		v, e, err := QuadContext(ctx, f, b, a, opts)
+		return -v, e, err
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		g := func(t float64) float64 {
+			x := (1 - t) / t
+			return (f(x) + f(-x)) / (t * t)
+		}
+		return quadFinite(ctx, g, 0, 1, opts)
+	case math.IsInf(b, 1):
+		g := func(t float64) float64 {
+			return f(a+(1-t)/t) / (t * t)
+		}
+		return quadFinite(ctx, g, 0, 1, opts)
+	case math.IsInf(a, -1):
+		g := func(t float64) float64 {
+			return f(b-(1-t)/t) / (t * t)
+		}
+		return quadFinite(ctx, g, 0, 1, opts)
+	}
+	return quadFinite(ctx, f, a, b, opts)
+}
+
+// quadInterval is a subinterval with its integral and error estimates.
+type quadInterval struct {
+	a, b       float64
+	value, err float64
+}
+
+// quadHeap orders subintervals by decreasing error estimate.
+type quadHeap []quadInterval
+
+func (h quadHeap) Len() int            { return len(h) }
+func (h quadHeap) Less(i, j int) bool  { return h[i].err > h[j].err }
+func (h quadHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *quadHeap) Push(x interface{}) { *h = append(*h, x.(quadInterval)) }
+func (h *quadHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+func quadFinite(
+	ctx context.Context, f func(float64) float64, a, b float64, opts *QuadOptions,
+) (float64, float64, error) {
+	value, errEst := kronrod15(f, a, b)
+	intervals := &quadHeap{{a: a, b: b, value: value, err: errEst}}
+	for n := 1; ; n++ {
+		tol := math.Max(opts.AbsTol, opts.RelTol*math.Abs(value))
+		if errEst <= tol || math.IsNaN(errEst) {
+			return value, errEst, nil
+		}
+		if n >= opts.MaxSubintervals {
+			break
+		}
+		if err := canceled(ctx, "quad"); err != nil {
+			return value, errEst, err
+		}
+		worst := heap.Pop(intervals).(quadInterval)
+		mid := (worst.a + worst.b) / 2
+		if mid <= worst.a || mid >= worst.b {
+			// The interval cannot be split further.
+			heap.Push(intervals, worst)
+			break
+		}
+		left := quadInterval{a: worst.a, b: mid}
+		left.value, left.err = kronrod15(f, left.a, left.b)
+		right := quadInterval{a: mid, b: worst.b}
+		right.value, right.err = kronrod15(f, right.a, right.b)
+		heap.Push(intervals, left)
+		heap.Push(intervals, right)
+		value, errEst = 0, 0
+		for _, in := range *intervals {
+			value += in.value
+			errEst += in.err
+		}
+	}
+	return value, errEst, &Error{
+		Operation: "quad",
+		Message:   "maximum number of subintervals reached",
+	}
+}
+
+// kronrod15 applies the 15-point Kronrod rule to f over [a, b],
+// returning the integral and an error estimate based on the embedded
+// 7-point Gauss rule.
+func kronrod15(f func(float64) float64, a, b float64) (float64, float64) {
+	center := (a + b) / 2
+	halfLength := (b - a) / 2
+	absHalfLength := math.Abs(halfLength)
+	fc := f(center)
+	resultGauss := gaussWeights[3] * fc
+	resultKronrod := kronrodWeights[7] * fc
+	resultAbs := math.Abs(resultKronrod)
+	var fv1, fv2 [7]float64
+	for j := 0; j < 7; j++ {
+		x := halfLength * kronrodNodes[j]
+		f1 := f(center - x)
+		f2 := f(center + x)
+		fv1[j], fv2[j] = f1, f2
+		resultKronrod += kronrodWeights[j] * (f1 + f2)
+		resultAbs += kronrodWeights[j] * (math.Abs(f1) + math.Abs(f2))
+		if j%2 == 1 {
+			resultGauss += gaussWeights[j/2] * (f1 + f2)
+		}
+	}
+	mean := resultKronrod / 2
+	resultAsc := kronrodWeights[7] * math.Abs(fc-mean)
+	for j := 0; j < 7; j++ {
+		resultAsc += kronrodWeights[j] *
+			(math.Abs(fv1[j]-mean) + math.Abs(fv2[j]-mean))
+	}
+	result := resultKronrod * halfLength
+	resultAbs *= absHalfLength
+	resultAsc *= absHalfLength
+	errEst := math.Abs((resultKronrod - resultGauss) * halfLength)
+	if resultAsc != 0 && errEst != 0 {
+		errEst = resultAsc * math.Min(1, math.Pow(200*errEst/resultAsc, 1.5))
+	}
+	const epsilon = 2.220446049250313e-16
+	if resultAbs > math.SmallestNonzeroFloat64/(50*epsilon) {
+		errEst = math.Max(50*epsilon*resultAbs, errEst)
+	}
+	return result, errEst
+}
@@ -0,0 +1,52 @@
+package array_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestQuad(t *testing.T) {
+	v, e, err := array.Quad(math.Sin, 0, math.Pi, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 2.0, v, 1e-12)
+		assert.Less(t, e, 1e-8)
+	}
+	v, _, err = array.Quad(math.Sin, math.Pi, 0, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, -2.0, v, 1e-12)
+	}
+	// A singular but integrable function needs many subdivisions.
+	opts := array.DefaultQuadOptions
+	opts.MaxSubintervals = 200
+	v, _, err = array.Quad(func(x float64) float64 {
+		return 1 / math.Sqrt(x)
+	}, 0, 1, &opts)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 2.0, v, 1e-7)
+	}
+	gauss := func(x float64) float64 { return math.Exp(-x * x) }
+	v, _, err = array.Quad(gauss, math.Inf(-1), math.Inf(1), nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.Sqrt(math.Pi), v, 1e-10)
+	}
+	v, _, err = array.Quad(gauss, 0, math.Inf(1), nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.Sqrt(math.Pi)/2, v, 1e-10)
+	}
+	v, _, err = array.Quad(gauss, math.Inf(-1), 0, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.Sqrt(math.Pi)/2, v, 1e-10)
+	}
+	opts.MaxSubintervals = 2
+	_, _, err = array.Quad(func(x float64) float64 {
+		return math.Sin(1 / x)
+	}, 1e-6, 1, &opts)
+	if assert.Error(t, err) {
+		serr := err.(*array.Error)
+		assert.Equal(t, "quad", serr.Operation)
+	}
+}
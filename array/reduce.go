@@ -0,0 +1,30 @@
+package array
+
+// Sum returns the sums of the items of d along axis, in an array with
+// the shape of d without axis.  Negative axes count from the last
+// dimension.  Items are added by pairwise summation in a fixed order,
+// so the result does not depend on the number of goroutines.
+func Sum(d *Dense, axis int) (*Dense, error) {
+	length, err := d.axisLength(axis)
+	if err != nil {
+		return nil, err
+	}
+	// Lanes are summed in parallel; the remaining goroutines split the
+	// summation tree of every lane.
+	workers := NumThreads()
+	if length > 0 {
+		if lanes := d.Size() / length; lanes > 1 {
+			workers /= lanes
+		}
+	}
+	return ReduceAlongAxis(d, axis, func(values []float64) float64 {
+		return parallelPairwiseSum(values, workers)
+	})
+}
+
+// SumAll returns the sum of all the items of d, added by pairwise
+// summation of the items in row-major order.  As in Sum, the result
+// does not depend on the number of goroutines.
+func SumAll(d *Dense) float64 {
+	return parallelPairwiseSum(d.Values(), NumThreads())
+}
@@ -0,0 +1,326 @@
+package array
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TextOptions controls how LoadText, GenFromText and TextReader parse
+// delimited text.
+type TextOptions struct {
+	// Delimiter separates the fields of a line.  When empty, fields are
+	// separated by runs of white space.
+	Delimiter string
+	// Comments starts a comment running to the end of the line.  When
+	// empty, lines have no comments.
+	Comments string
+	// SkipRows is the number of lines skipped at the beginning of the
+	// text, such as a header.
+	SkipRows int
+	// Columns selects the fields read from every line, in the order of
+	// the columns of the result.  When nil, all fields are read.
+	Columns []int
+	// Converters parse the fields of a column, indexed by position in
+	// the line, in place of strconv.ParseFloat.
+	Converters map[int]func(string) (float64, error)
+	// MissingValues lists the fields taken as missing, in addition to
+	// empty ones.
+	MissingValues []string
+	// FillValue replaces missing fields in GenFromText and TextReader.
+	FillValue float64
+}
+
+// DefaultTextOptions reads fields separated by white space, with
+// comments starting with "#" and missing fields replaced by NaN.
+var DefaultTextOptions = TextOptions{
+	Comments:  "#",
+	FillValue: math.NaN(),
+}
+
+// LoadText reads a two-dimensional array from delimited text, one row
+// per line, skipping blank lines.  Every row must have the same number
+// of fields, and missing fields are an error.  If opts is nil,
+// DefaultTextOptions is used.
+func LoadText(r io.Reader, opts *TextOptions) (*Dense, error) {
+	return LoadTextContext(context.Background(), r, opts)
+}
+
+// LoadTextContext is like LoadText, but stops early and returns an
+// error wrapping the error of ctx once it is done.
+func LoadTextContext(ctx context.Context, r io.Reader, opts *TextOptions) (*Dense, error) {
+	t := NewTextReader(r, opts)
+	t.strict = true
+	return t.readAll(ctx)
+}
+
+// GenFromText reads a two-dimensional array from delimited text like
+// LoadText, but replaces missing fields by the fill value.  Missing
+// fields include empty fields, those listed in the options and those
+// absent from the end of short lines.
+func GenFromText(r io.Reader, opts *TextOptions) (*Dense, error) {
+	return GenFromTextContext(context.Background(), r, opts)
+}
+
+// GenFromTextContext is like GenFromText, but stops early and returns
+// an error wrapping the error of ctx once it is done.
+func GenFromTextContext(ctx context.Context, r io.Reader, opts *TextOptions) (*Dense, error) {
+	return NewTextReader(r, opts).readAll(ctx)
+}
+
+// TextReader reads a two-dimensional array from delimited text in
+// chunks of rows, so that files larger than memory can be processed.
+// Missing fields are handled as in GenFromText.
+type TextReader struct {
+	opts    *TextOptions
+	reader  *bufio.Reader
+	strict  bool
+	line    int
+	columns int
+	done    bool
+}
+
+// NewTextReader returns a TextReader parsing the text of r as defined
+// by opts.  If opts is nil, DefaultTextOptions is used.
+func NewTextReader(r io.Reader, opts *TextOptions) *TextReader {
+	if opts == nil {
+		opts = &DefaultTextOptions
+	}
+	return &TextReader{
+		opts:    opts,
+		reader:  bufio.NewReader(r),
+		columns: -1,
+	}
+}
+
+// Read returns an array with the next rows of the text, at most n
+// unless n is not positive, in which case all the remaining rows are
+// read.  At the end of the text, Read returns nil and io.EOF.
+func (t *TextReader) Read(n int) (*Dense, error) {
+	return t.ReadContext(context.Background(), n)
+}
+
+// ReadContext is like Read, but stops early and returns an error
+// wrapping the error of ctx once it is done.  The rows read until then
+// are lost.
+func (t *TextReader) ReadContext(ctx context.Context, n int) (*Dense, error) {
+	var values []float64
+	rows := 0
+	for n <= 0 || rows < n {
+		if rows%textCheckRows == 0 && ctx.Err() != nil {
+			return nil, canceled(ctx, "read text")
+		}
+		row, err := t.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, row...)
+		rows++
+	}
+	if rows == 0 {
+		return nil, io.EOF
+	}
+	return NewDenseWithValues(Shape{rows, t.columns}, values, DefaultAttributes)
+}
+
+// textCheckRows is the number of rows read between checks of the
+// context.
+const textCheckRows = 1024
+
+// readAll reads all the rows of the text, returning an array with no
+// rows for a text without any.
+func (t *TextReader) readAll(ctx context.Context) (*Dense, error) {
+	d, err := t.ReadContext(ctx, 0)
+	if err == io.EOF {
+		columns := t.columns
+		if columns < 0 {
+			columns = len(t.opts.Columns)
+		}
+		return NewDense(Shape{0, columns}, DefaultAttributes)
+	}
+	return d, err
+}
+
+// next returns the values of the next row.
+func (t *TextReader) next() ([]float64, error) {
+	for !t.done {
+		text, err := t.reader.ReadString('\n')
+		if err == io.EOF {
+			t.done = true
+			if text == "" {
+				break
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		t.line++
+		if t.line <= t.opts.SkipRows {
+			continue
+		}
+		if t.opts.Comments != "" {
+			if i := strings.Index(text, t.opts.Comments); i >= 0 {
+				text = text[:i]
+			}
+		}
+		text = strings.TrimRight(text, "\r\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		return t.parse(text)
+	}
+	return nil, io.EOF
+}
+
+// parse returns the values of the selected fields of a line.
+func (t *TextReader) parse(text string) ([]float64, error) {
+	var fields []string
+	if t.opts.Delimiter == "" {
+		fields = strings.Fields(text)
+	} else {
+		fields = strings.Split(text, t.opts.Delimiter)
+	}
+	columns := t.opts.Columns
+	if columns == nil {
+		if t.columns < 0 {
+			t.columns = len(fields)
+		}
+		if len(fields) > t.columns || (t.strict && len(fields) < t.columns) {
+			return nil, &TextError{
+				Line:   t.line,
+				Column: -1,
+				Err: fmt.Errorf("found %d fields, expected %d",
+					len(fields), t.columns),
+			}
+		}
+	} else {
+		t.columns = len(columns)
+	}
+	row := make([]float64, t.columns)
+	for i := range row {
+		column := i
+		if columns != nil {
+			column = columns[i]
+		}
+		v, err := t.value(fields, column)
+		if err != nil {
+			return nil, &TextError{Line: t.line, Column: column, Err: err}
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+// value returns the value of a field.
+func (t *TextReader) value(fields []string, column int) (float64, error) {
+	if column < 0 {
+		return 0, fmt.Errorf("invalid column %d", column)
+	}
+	field := ""
+	if column < len(fields) {
+		field = strings.TrimSpace(fields[column])
+	} else if t.strict {
+		return 0, fmt.Errorf("line has %d fields", len(fields))
+	}
+	if field == "" || t.missing(field) {
+		if t.strict {
+			return 0, errors.New("missing value")
+		}
+		return t.opts.FillValue, nil
+	}
+	if convert, ok := t.opts.Converters[column]; ok {
+		return convert(field)
+	}
+	return strconv.ParseFloat(field, 64)
+}
+
+func (t *TextReader) missing(field string) bool {
+	for _, m := range t.opts.MissingValues {
+		if field == m {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveTextOptions controls how SaveText writes an array.
+type SaveTextOptions struct {
+	// Delimiter separates the fields of a line.  The delimiter of
+	// DefaultSaveTextOptions is used when it is empty.
+	Delimiter string
+	// Format is the fmt verb of every item.  The format of
+	// DefaultSaveTextOptions is used when it is empty.
+	Format string
+	// Header and Footer are written before and after the rows, every
+	// line prefixed by Comments, unless they are empty.
+	Header, Footer string
+	// Comments prefixes the lines of Header and Footer.
+	Comments string
+}
+
+// DefaultSaveTextOptions writes items separated by a space, in
+// exponential notation with enough digits to be read back exactly.
+var DefaultSaveTextOptions = SaveTextOptions{
+	Delimiter: " ",
+	Format:    "%.18e",
+	Comments:  "# ",
+}
+
+// SaveText writes a one- or two-dimensional array as delimited text,
+// one row per line.  The items of a one-dimensional array are written
+// one per line.  If opts is nil, DefaultSaveTextOptions is used.
+func SaveText(w io.Writer, d *Dense, opts *SaveTextOptions) error {
+	if opts == nil {
+		opts = &DefaultSaveTextOptions
+	}
+	delimiter, format := opts.Delimiter, opts.Format
+	if delimiter == "" {
+		delimiter = DefaultSaveTextOptions.Delimiter
+	}
+	if format == "" {
+		format = DefaultSaveTextOptions.Format
+	}
+	var rows, columns int
+	switch len(d.Shape) {
+	case 1:
+		rows, columns = d.Shape[0], 1
+	case 2:
+		rows, columns = d.Shape[0], d.Shape[1]
+	default:
+		return &Error{
+			Operation: "save text",
+			Message:   "array must be one- or two-dimensional",
+		}
+	}
+	b := bufio.NewWriter(w)
+	comment := func(text string) {
+		if text == "" {
+			return
+		}
+		for _, line := range strings.Split(text, "\n") {
+			b.WriteString(opts.Comments)
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	comment(opts.Header)
+	values := d.Values()
+	for i := 0; i < rows; i++ {
+		for j := 0; j < columns; j++ {
+			if j > 0 {
+				b.WriteString(delimiter)
+			}
+			fmt.Fprintf(b, format, values[i*columns+j])
+		}
+		b.WriteByte('\n')
+	}
+	comment(opts.Footer)
+	return b.Flush()
+}
@@ -0,0 +1,153 @@
+package array_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestLoadText(t *testing.T) {
+	text := "# x y\n1 2\n\n3.5 -4e2  # trailing\n  NaN inf\n"
+	d, err := array.LoadText(strings.NewReader(text), nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, array.Shape{3, 2}, d.Shape)
+	values := d.Values()
+	assert.Equal(t, []float64{1, 2, 3.5, -400}, values[:4])
+	assert.True(t, math.IsNaN(values[4]))
+	assert.True(t, math.IsInf(values[5], 1))
+
+	_, err = array.LoadText(strings.NewReader("1 2\n3\n"), nil)
+	var e *array.TextError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 2, e.Line)
+		assert.Equal(t, "line 2: found 1 fields, expected 2", e.Error())
+	}
+
+	d, err = array.LoadText(strings.NewReader(""), nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{0, 0}, d.Shape)
+	}
+}
+
+func TestLoadTextOptions(t *testing.T) {
+	text := "date,temp,flag\n2021-01-01,10.5,yes\n2021-01-02,,no\n"
+	opts := array.TextOptions{
+		Delimiter: ",",
+		SkipRows:  1,
+		Columns:   []int{2, 1},
+		Converters: map[int]func(string) (float64, error){
+			2: func(s string) (float64, error) {
+				if s == "yes" {
+					return 1, nil
+				}
+				return 0, nil
+			},
+		},
+		FillValue: -1,
+	}
+	_, err := array.LoadText(strings.NewReader(text), &opts)
+	var e *array.TextError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, "line 3, column 1: missing value", e.Error())
+	}
+	d, err := array.GenFromText(strings.NewReader(text), &opts)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, d.Shape)
+		assert.Equal(t, []float64{1, 10.5, 0, -1}, d.Values())
+	}
+
+	_, err = array.LoadText(strings.NewReader("1,x\n"),
+		&array.TextOptions{Delimiter: ","})
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 1, e.Column)
+		assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	}
+}
+
+func TestGenFromText(t *testing.T) {
+	opts := array.DefaultTextOptions
+	opts.Delimiter = ";"
+	opts.MissingValues = []string{"NA"}
+	d, err := array.GenFromText(strings.NewReader("1;NA;3\n4;5\n"), &opts)
+	if !assert.Nil(t, err) {
+		return
+	}
+	values := d.Values()
+	assert.Equal(t, []float64{1, 4, 5}, []float64{values[0], values[3], values[4]})
+	assert.True(t, math.IsNaN(values[1]))
+	assert.True(t, math.IsNaN(values[5]))
+
+	_, err = array.GenFromText(strings.NewReader("1;2\n3;4;5\n"), &opts)
+	assert.NotNil(t, err)
+}
+
+func TestTextReader(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 10; i++ {
+		b.WriteString(strconv.Itoa(i) + " " + strconv.Itoa(i*i) + "\n")
+	}
+	r := array.NewTextReader(strings.NewReader(b.String()), nil)
+	var rows []int
+	total := 0.0
+	for {
+		chunk, err := r.Read(4)
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err) {
+			return
+		}
+		rows = append(rows, chunk.Shape[0])
+		total += array.SumAll(chunk)
+	}
+	assert.Equal(t, []int{4, 4, 2}, rows)
+	assert.Equal(t, 45.0+285.0, total)
+}
+
+func TestSaveText(t *testing.T) {
+	d, err := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{1, 2, 3, 0.1, math.Inf(-1), math.NaN()},
+		array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if !assert.Nil(t, array.SaveText(&buf, d, nil)) {
+		return
+	}
+	back, err := array.LoadText(&buf, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, d.Shape, back.Shape)
+		assert.Equal(t, d.Values()[:5], back.Values()[:5])
+		assert.True(t, math.IsNaN(back.Values()[5]))
+	}
+
+	buf.Reset()
+	opts := array.SaveTextOptions{
+		Delimiter: ",", Format: "%g", Header: "a,b,c", Comments: "#",
+	}
+	assert.Nil(t, array.SaveText(&buf, d, &opts))
+	assert.Equal(t, "#a,b,c\n1,2,3\n0.1,-Inf,NaN\n", buf.String())
+
+	// Options left empty take their default values.
+	buf.Reset()
+	assert.Nil(t, array.SaveText(&buf, d, &array.SaveTextOptions{Delimiter: ","}))
+	assert.Equal(t, "1.000000000000000000e+00,2.000000000000000000e+00,"+
+		"3.000000000000000000e+00\n", strings.SplitAfter(buf.String(), "\n")[0])
+	buf.Reset()
+	assert.Nil(t, array.SaveText(&buf, d, &array.SaveTextOptions{Format: "%g"}))
+	assert.Equal(t, "1 2 3\n0.1 -Inf NaN\n", buf.String())
+
+	scalar := array.Scalar(1)
+	assert.NotNil(t, array.SaveText(&buf, scalar, nil))
+}
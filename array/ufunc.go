@@ -0,0 +1,79 @@
+package array
+
+import "context"
+
+// Scalar returns a zero-dimensional array holding v.
+func Scalar(v float64) *Dense {
+	return &Dense{
+		Data:    []float64{v},
+		DType:   Float64,
+		Shape:   Shape{},
+		Strides: Strides{},
+		Attrs:   DefaultAttributes,
+	}
+}
+
+// Map returns a new array with the shape of d whose items are fn
+// applied to the items of d.  As in MapN, fn may be called
+// concurrently.
+func Map(d *Dense, fn func(float64) float64) (*Dense, error) {
+	return MapN(func(args []float64) float64 {
+		return fn(args[0])
+	}, d)
+}
+
+// Map2 returns a new array whose items are fn applied to the items of
+// a and b broadcast together.  As in MapN, fn may be called
+// concurrently.
+func Map2(a, b *Dense, fn func(x, y float64) float64) (*Dense, error) {
+	return MapN(func(args []float64) float64 {
+		return fn(args[0], args[1])
+	}, a, b)
+}
+
+// MapN returns a new array whose items are fn applied to the items of
+// the operands broadcast together.  The slice given to fn holds one
+// item of each operand and must not be retained.  Large arrays are
+// split among goroutines, so fn may be called concurrently.
+func MapN(fn func(args []float64) float64, operands ...*Dense) (*Dense, error) {
+	return MapNContext(context.Background(), fn, operands...)
+}
+
+// MapNContext is like MapN, but stops early and returns an error
+// wrapping the error of ctx once it is done.
+func MapNContext(
+	ctx context.Context, fn func(args []float64) float64, operands ...*Dense,
+) (*Dense, error) {
+	shapes := make([]Shape, len(operands))
+	for i, op := range operands {
+		shapes[i] = op.Shape
+	}
+	shape, err := BroadcastShapes(shapes...)
+	if err != nil {
+		return nil, err
+	}
+	out, err := NewDense(shape, DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*Dense, len(operands)+1)
+	views[0] = out
+	for i, op := range operands {
+		if views[i+1], err = op.BroadcastTo(shape); err != nil {
+			return nil, err
+		}
+	}
+	err = parallelFor(ctx, out.Size(), outerBlock(shape), 1, func(lo, hi int) {
+		args := make([]float64, len(operands))
+		iterateRange(shape, views, lo, hi, func(pos []int) {
+			for i := range args {
+				args[i] = views[i+1].load(pos[i+1])
+			}
+			out.Data[pos[0]] = fn(args)
+		})
+	})
+	if err != nil {
+		return nil, canceled(ctx, "map")
+	}
+	return out, nil
+}
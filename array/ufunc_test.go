@@ -0,0 +1,70 @@
+package array_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+)
+
+func TestBroadcastShapes(t *testing.T) {
+	s, err := array.BroadcastShapes(array.Shape{3, 1}, array.Shape{4}, array.Shape{})
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{3, 4}, s)
+	}
+	s, err = array.BroadcastShapes(array.Shape{2, 1, 5}, array.Shape{3, 1})
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 3, 5}, s)
+	}
+	_, err = array.BroadcastShapes(array.Shape{3}, array.Shape{4})
+	assert.ErrorIs(t, err, array.ErrBroadcast)
+}
+
+func TestBroadcastTo(t *testing.T) {
+	d, _ := array.NewDenseWithValues(
+		array.Shape{3, 1}, []float64{1, 2, 3}, array.RowMajorLayout)
+	v, err := d.BroadcastTo(array.Shape{2, 3, 2})
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Strides{0, 8, 0}, v.Strides)
+		assert.False(t, v.Attrs.Is(array.Writeable))
+		assert.Equal(t,
+			[]float64{1, 1, 2, 2, 3, 3, 1, 1, 2, 2, 3, 3}, v.Values())
+	}
+	_, err = d.BroadcastTo(array.Shape{2, 2})
+	assert.ErrorIs(t, err, array.ErrBroadcast)
+	_, err = d.BroadcastTo(array.Shape{3})
+	assert.ErrorIs(t, err, array.ErrBroadcast)
+}
+
+func TestMap(t *testing.T) {
+	col, _ := array.NewDenseWithValues(
+		array.Shape{2, 1}, []float64{10, 20}, array.ColumnMajorLayout)
+	row, _ := array.NewDenseWithValues(
+		array.Shape{3}, []float64{1, 2, 3}, array.DefaultAttributes)
+	out, err := array.Map2(col, row, func(x, y float64) float64 {
+		return x + y
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 3}, out.Shape)
+		assert.Equal(t, []float64{11, 12, 13, 21, 22, 23}, out.Values())
+	}
+	out, err = array.Map(row, func(x float64) float64 { return x * x })
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 4, 9}, out.Values())
+	}
+	out, err = array.MapN(func(args []float64) float64 {
+		return args[0]*args[1] - args[2]
+	}, row, array.Scalar(2), col)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{-8, -6, -4, -18, -16, -14}, out.Values())
+	}
+	out, err = array.Map(array.Scalar(3), func(x float64) float64 { return -x })
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{}, out.Shape)
+		assert.Equal(t, []float64{-3}, out.Values())
+	}
+	wide, _ := array.NewDense(array.Shape{4}, array.DefaultAttributes)
+	_, err = array.Map2(row, wide, func(x, y float64) float64 { return x })
+	assert.ErrorIs(t, err, array.ErrBroadcast)
+}
@@ -0,0 +1,282 @@
+package arrow_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/arrow"
+	"github.com/jimmyskull/math/internal/flatbuf"
+)
+
+func matrix(t *testing.T, rows, columns int, attrs array.Attributes) *array.Dense {
+	t.Helper()
+	values := make([]float64, rows*columns)
+	for i := range values {
+		values[i] = float64(i) / 4
+	}
+	d, err := array.NewDenseWithValues(array.Shape{rows, columns}, values, attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestTensor(t *testing.T) {
+	row := array.Contiguous | array.Writeable | array.RowMajorLayout
+	for _, attrs := range []array.Attributes{row, array.DefaultAttributes} {
+		d := matrix(t, 3, 4, attrs)
+		var buf bytes.Buffer
+		if !assert.Nil(t, arrow.WriteTensor(&buf, d)) {
+			return
+		}
+		// The buffer of bytes.Buffer is allocated by make and so aligned.
+		raw := append([]byte{}, buf.Bytes()...)
+		back, err := arrow.ReadTensor(&buf)
+		if assert.Nil(t, err) {
+			assert.Equal(t, d.Shape, back.Shape)
+			assert.Equal(t, d.Strides, back.Strides)
+			assert.Equal(t, d.Attrs, back.Attrs)
+			assert.Equal(t, d.Values(), back.Values())
+		}
+		shared, err := arrow.TensorFromBytes(raw)
+		if assert.Nil(t, err) {
+			assert.Equal(t, d.Values(), shared.Values())
+			assert.Same(t, &raw[len(raw)-96], &shared.Bytes()[0])
+		}
+	}
+
+	// Views are written with their own items.
+	v, _ := array.Scalar(2).BroadcastTo(array.Shape{2, 2})
+	var buf bytes.Buffer
+	assert.Nil(t, arrow.WriteTensor(&buf, v))
+	back, err := arrow.ReadTensor(&buf)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{2, 2, 2, 2}, back.Values())
+	}
+
+	_, err = arrow.TensorFromBytes([]byte("not a tensor"))
+	assert.Equal(t, arrow.ErrInvalidMessage, err)
+}
+
+func TestStream(t *testing.T) {
+	var buf bytes.Buffer
+	w := arrow.NewStreamWriter(&buf, []string{"x", "y"})
+	a := matrix(t, 5, 2, array.DefaultAttributes)
+	b := matrix(t, 1, 2, array.Contiguous|array.Writeable|array.RowMajorLayout)
+	assert.Nil(t, w.WriteBatch(a))
+	assert.Nil(t, w.WriteBatch(b))
+	assert.Equal(t, arrow.ErrColumns, w.WriteBatch(matrix(t, 2, 3, array.DefaultAttributes)))
+	assert.Nil(t, w.Close())
+
+	r, err := arrow.NewStreamReader(&buf)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"x", "y"}, r.Names())
+	for _, want := range []*array.Dense{a, b} {
+		got, err := r.ReadBatch()
+		if assert.Nil(t, err) {
+			assert.Equal(t, want.Shape, got.Shape)
+			assert.Equal(t, want.Values(), got.Values())
+		}
+	}
+	_, err = r.ReadBatch()
+	assert.Equal(t, io.EOF, err)
+
+	// Columns are taken from the strides, whatever the attributes claim.
+	buf.Reset()
+	w = arrow.NewStreamWriter(&buf, []string{"x", "y"})
+	lying, _ := array.NewDenseWithStrides(array.Shape{3, 2}, array.Strides{16, 8},
+		array.DefaultAttributes)
+	copy(lying.Data, []float64{1, 2, 3, 4, 5, 6})
+	assert.Nil(t, w.WriteBatch(lying))
+	assert.Nil(t, w.Close())
+	if r, err = arrow.NewStreamReader(&buf); assert.Nil(t, err) {
+		got, err := r.ReadBatch()
+		if assert.Nil(t, err) {
+			assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, got.Values())
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	var buf bytes.Buffer
+	w := arrow.NewFileWriter(&buf, nil)
+	a := matrix(t, 4, 3, array.DefaultAttributes)
+	assert.Nil(t, w.WriteBatch(a))
+	assert.Nil(t, w.WriteBatch(matrix(t, 2, 3, array.DefaultAttributes)))
+	assert.Nil(t, w.Close())
+
+	b := buf.Bytes()
+	names, batches, err := arrow.ReadFile(b)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"f0", "f1", "f2"}, names)
+	if assert.Equal(t, 2, len(batches)) {
+		assert.Equal(t, a.Values(), batches[0].Values())
+		assert.True(t, batches[0].Attrs.Is(array.ColumnMajorLayout))
+		// The columns of the batch are the bytes of the file.
+		data := batches[0].Bytes()
+		assert.True(t, bytes.Contains(b, data))
+		data[0] = 1
+		assert.True(t, bytes.Contains(b, data))
+	}
+
+	_, _, err = arrow.ReadFile(b[:len(b)-1])
+	assert.Equal(t, arrow.ErrInvalidMessage, err)
+}
+
+// message returns an encapsulated message with the given header and
+// body.
+func message(kind uint8, header flatbuf.Table, body []byte) []byte {
+	meta := flatbuf.Encode(flatbuf.Table{
+		flatbuf.Int16(4), flatbuf.Uint8(kind), header,
+		flatbuf.Int64(int64(len(body))),
+	})
+	b := []byte{0xff, 0xff, 0xff, 0xff, byte(len(meta)), 0, 0, 0}
+	b = append(b, meta...)
+	return append(b, body...)
+}
+
+func schema(precision int16) []byte {
+	field := flatbuf.Table{
+		flatbuf.String("x"), flatbuf.Bool(true), flatbuf.Uint8(3),
+		flatbuf.Table{flatbuf.Int16(precision)},
+	}
+	return message(1, flatbuf.Table{flatbuf.Int16(0), flatbuf.Tables{field}}, nil)
+}
+
+func TestReadContext(t *testing.T) {
+	var stream, file, tensor bytes.Buffer
+	d := matrix(t, 4, 3, array.DefaultAttributes)
+	w := arrow.NewStreamWriter(&stream, nil)
+	assert.Nil(t, w.WriteBatch(d))
+	assert.Nil(t, w.Close())
+	w = arrow.NewFileWriter(&file, nil)
+	assert.Nil(t, w.WriteBatch(d))
+	assert.Nil(t, w.Close())
+	assert.Nil(t, arrow.WriteTensor(&tensor, d))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := arrow.NewStreamReaderContext(ctx, &stream)
+	if !assert.Nil(t, err) {
+		return
+	}
+	cancel()
+	_, err = r.ReadBatch()
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, _, err = arrow.ReadFileContext(ctx, file.Bytes())
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = arrow.ReadTensorContext(ctx, &tensor)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestNulls(t *testing.T) {
+	body := make([]byte, 8+3*8)
+	body[0] = 0x5 // rows 0 and 2 are valid.
+	for i, v := range []float64{1, 2, 3} {
+		bits := math.Float64bits(v)
+		for k := 0; k < 8; k++ {
+			body[8+8*i+k] = byte(bits >> (8 * uint(k)))
+		}
+	}
+	batch := flatbuf.Table{
+		flatbuf.Int64(3),
+		flatbuf.Structs{Count: 1, Data: flatbuf.Int64s(3, 1)},
+		flatbuf.Structs{Count: 2, Data: flatbuf.Int64s(0, 1, 8, 24)},
+	}
+	stream := append(schema(2), message(3, batch, body)...)
+	r, err := arrow.NewStreamReader(bytes.NewReader(stream))
+	if !assert.Nil(t, err) {
+		return
+	}
+	d, err := r.ReadBatch()
+	if assert.Nil(t, err) {
+		values := d.Values()
+		assert.Equal(t, 1.0, values[0])
+		assert.True(t, math.IsNaN(values[1]))
+		assert.Equal(t, 3.0, values[2])
+	}
+
+	// Single-precision columns are not supported.
+	_, err = arrow.NewStreamReader(bytes.NewReader(schema(1)))
+	assert.Equal(t, arrow.ErrUnsupported, err)
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// The fixtures are assembled by testdata/fixtures.py from the Arrow
+// format specification, independently of the encoder of the package.
+func TestFixtures(t *testing.T) {
+	nan := math.NaN()
+	batches := [][]float64{{1, 2, 3, 0.5, nan, -1.5}, {4, 2.5}}
+	assertBatch := func(expected []float64, d *array.Dense) {
+		t.Helper()
+		assert.Equal(t, array.Shape{len(expected) / 2, 2}, d.Shape)
+		// Values are compared column by column, with NaN for nulls.
+		for i, v := range d.Values() {
+			row, column := i/2, i%2
+			want := expected[column*d.Shape[0]+row]
+			if math.IsNaN(want) {
+				assert.True(t, math.IsNaN(v))
+			} else {
+				assert.Equal(t, want, v)
+			}
+		}
+	}
+
+	r, err := arrow.NewStreamReader(bytes.NewReader(readFixture(t, "stream.arrows")))
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"x", "y"}, r.Names())
+		for _, expected := range batches {
+			d, err := r.ReadBatch()
+			if assert.Nil(t, err) {
+				assertBatch(expected, d)
+			}
+		}
+		_, err = r.ReadBatch()
+		assert.Equal(t, io.EOF, err)
+	}
+
+	names, got, err := arrow.ReadFile(readFixture(t, "file.arrow"))
+	if assert.Nil(t, err) && assert.Len(t, got, 2) {
+		assert.Equal(t, []string{"x", "y"}, names)
+		for i, expected := range batches {
+			assertBatch(expected, got[i])
+		}
+	}
+
+	for name, strides := range map[string]array.Strides{
+		"tensor.arrow":         {24, 8},
+		"tensor_fortran.arrow": {8, 16},
+	} {
+		d, err := arrow.TensorFromBytes(readFixture(t, name))
+		if assert.Nil(t, err, name) {
+			assert.Equal(t, array.Shape{2, 3}, d.Shape)
+			assert.Equal(t, strides, d.Strides)
+			assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, d.Values())
+		}
+		d, err = arrow.ReadTensor(bytes.NewReader(readFixture(t, name)))
+		if assert.Nil(t, err, name) {
+			assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, d.Values())
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package arrow
+
+import "errors"
+
+var (
+	// ErrInvalidMessage is returned when reading data that is not a
+	// valid Arrow message, stream or file.
+	ErrInvalidMessage = errors.New("invalid Arrow message")
+
+	// ErrUnsupported is returned when reading Arrow data that cannot be
+	// represented by an array, such as columns that are not float64 or
+	// compressed record batches.
+	ErrUnsupported = errors.New("unsupported Arrow data")
+
+	// ErrColumns is returned when a record batch does not match the
+	// columns of the schema, or is not a two-dimensional array.
+	ErrColumns = errors.New("array does not match the schema columns")
+)
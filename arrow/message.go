@@ -0,0 +1,206 @@
+// Package arrow converts arrays to and from the Apache Arrow IPC
+// formats: record batches of float64 columns in the stream and file
+// formats, and tensors.
+//
+// Arrow buffers are little-endian and aligned to eight bytes, so that
+// arrays read from a byte slice, such as a memory-mapped file, share
+// its memory instead of copying it whenever the layout allows.
+package arrow
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/flatbuf"
+)
+
+// continuation starts every message of the encapsulated format.
+const continuation = 0xFFFFFFFF
+
+// metadataVersion is the version of the Arrow metadata written, V5.
+const metadataVersion = 4
+
+// Message header types.
+const (
+	headerSchema      = 1
+	headerRecordBatch = 3
+	headerTensor      = 4
+)
+
+// Type of float64 columns and tensors.
+const (
+	typeFloatingPoint = 3
+	precisionDouble   = 2
+)
+
+// message is a decoded encapsulated message.
+type message struct {
+	kind   uint8
+	header flatbuf.Ref
+	body   []byte
+	// size is the length of the message before the body.
+	size int
+}
+
+// writeMessage writes an encapsulated message, returning the lengths
+// of its metadata, including the prefix, and of its body.  The body is
+// the concatenation of buffers, each padded to eight bytes.
+func writeMessage(
+	w io.Writer, kind uint8, header flatbuf.Table, buffers [][]byte,
+) (int, int64, error) {
+	var bodyLength int64
+	for _, b := range buffers {
+		bodyLength += padded(int64(len(b)))
+	}
+	meta := flatbuf.Encode(flatbuf.Table{
+		flatbuf.Int16(metadataVersion),
+		flatbuf.Uint8(kind),
+		header,
+		flatbuf.Int64(bodyLength),
+	})
+	prefix := make([]byte, 8)
+	binary.LittleEndian.PutUint32(prefix, continuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(meta)))
+	if _, err := w.Write(prefix); err != nil {
+		return 0, 0, err
+	}
+	if _, err := w.Write(meta); err != nil {
+		return 0, 0, err
+	}
+	var zeros [8]byte
+	for _, b := range buffers {
+		if _, err := w.Write(b); err != nil {
+			return 0, 0, err
+		}
+		pad := padded(int64(len(b))) - int64(len(b))
+		if _, err := w.Write(zeros[:pad]); err != nil {
+			return 0, 0, err
+		}
+	}
+	return len(prefix) + len(meta), bodyLength, nil
+}
+
+// writeEOS writes the end-of-stream marker.
+func writeEOS(w io.Writer) error {
+	eos := make([]byte, 8)
+	binary.LittleEndian.PutUint32(eos, continuation)
+	_, err := w.Write(eos)
+	return err
+}
+
+func padded(n int64) int64 {
+	return (n + 7) &^ 7
+}
+
+// decodeMessage decodes the metadata of a message, which gives the
+// length of its body.
+func decodeMessage(meta []byte) (msg *message, bodyLength int64, err error) {
+	defer flatbuf.Catch(&err)
+	root := flatbuf.Root(meta)
+	header, ok := root.Table(2)
+	if !ok {
+		return nil, 0, ErrInvalidMessage
+	}
+	bodyLength = root.Int64(3, 0)
+	if bodyLength < 0 {
+		return nil, 0, ErrInvalidMessage
+	}
+	return &message{kind: root.Uint8(1, 0), header: header}, bodyLength, nil
+}
+
+// readMessage reads the next message of r, returning io.EOF at the end
+// of the stream.  The body is copied into memory aligned for float64.
+func readMessage(r io.Reader) (*message, error) {
+	var word [4]byte
+	if _, err := io.ReadFull(r, word[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = ErrInvalidMessage
+		}
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(word[:])
+	if length == continuation {
+		if _, err := io.ReadFull(r, word[:]); err != nil {
+			return nil, ErrInvalidMessage
+		}
+		length = binary.LittleEndian.Uint32(word[:])
+	}
+	if length == 0 {
+		return nil, io.EOF
+	}
+	meta, err := readBody(r, int64(length))
+	if err != nil {
+		return nil, err
+	}
+	msg, bodyLength, err := decodeMessage(meta)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	if msg.body, err = readBody(r, bodyLength); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// readBody reads n bytes, of metadata or body, into memory aligned for
+// float64, growing the buffer as the bytes arrive so that a corrupt
+// length cannot cause a huge allocation.
+func readBody(r io.Reader, n int64) ([]byte, error) {
+	size := int64(1 << 20)
+	if n < size {
+		size = n
+	}
+	body := aligned(size)
+	read := 0
+	for {
+		m, err := io.ReadFull(r, body[read:])
+		read += m
+		if err != nil {
+			return nil, ErrInvalidMessage
+		}
+		if int64(read) == n {
+			return body, nil
+		}
+		size *= 2
+		if n < size {
+			size = n
+		}
+		grown := aligned(size)
+		copy(grown, body)
+		body = grown
+	}
+}
+
+// parseMessage decodes the message at the start of b, whose body is a
+// slice of b.
+func parseMessage(b []byte) (*message, error) {
+	if len(b) < 8 {
+		return nil, ErrInvalidMessage
+	}
+	start := 4
+	length := int(binary.LittleEndian.Uint32(b))
+	if uint32(length) == continuation {
+		start = 8
+		length = int(binary.LittleEndian.Uint32(b[4:]))
+	}
+	if length <= 0 || length > len(b)-start {
+		return nil, ErrInvalidMessage
+	}
+	msg, bodyLength, err := decodeMessage(b[start : start+length])
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	msg.size = start + length
+	if bodyLength > int64(len(b)-msg.size) {
+		return nil, ErrInvalidMessage
+	}
+	msg.body = b[msg.size : msg.size+int(bodyLength)]
+	return msg, nil
+}
+
+// aligned returns a slice of n bytes aligned for float64.
+func aligned(n int64) []byte {
+	words := &array.Dense{Data: make([]float64, (n+7)/8), DType: array.Float64}
+	return words.Bytes()[:n]
+}
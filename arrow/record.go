@@ -0,0 +1,403 @@
+package arrow
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/ctxio"
+	"github.com/jimmyskull/math/internal/flatbuf"
+)
+
+// fileMagic starts and ends every Arrow file.
+const fileMagic = "ARROW1"
+
+// block locates a record batch in an Arrow file.
+type block struct {
+	offset     int64
+	metaLength int
+	bodyLength int64
+}
+
+// Writer writes two-dimensional arrays as record batches of float64
+// columns, one column per array column, in the Arrow stream or file
+// format.  Column-major arrays are written without an intermediate
+// copy.
+type Writer struct {
+	w       *counter
+	names   []string
+	file    bool
+	blocks  []block
+	started bool
+}
+
+// counter counts the bytes written to a writer.
+type counter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewStreamWriter returns a Writer of the Arrow stream format with the
+// given column names.  If names is nil, the columns are named f0, f1,
+// and so on after the columns of the first array written.
+func NewStreamWriter(w io.Writer, names []string) *Writer {
+	return &Writer{w: &counter{w: w}, names: names}
+}
+
+// NewFileWriter returns a Writer of the Arrow file format, which
+// supports random access to its record batches.  Names are as in
+// NewStreamWriter.
+func NewFileWriter(w io.Writer, names []string) *Writer {
+	return &Writer{w: &counter{w: w}, names: names, file: true}
+}
+
+func (w *Writer) schema() flatbuf.Table {
+	fields := make(flatbuf.Tables, len(w.names))
+	for i, name := range w.names {
+		fields[i] = flatbuf.Table{
+			flatbuf.String(name),
+			flatbuf.Bool(true),
+			flatbuf.Uint8(typeFloatingPoint),
+			flatbuf.Table{flatbuf.Int16(precisionDouble)},
+			nil,
+			flatbuf.Tables{},
+		}
+	}
+	return flatbuf.Table{flatbuf.Int16(0), fields}
+}
+
+// start writes the file magic and the schema, once.
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if w.file {
+		if _, err := w.w.Write([]byte(fileMagic + "\x00\x00")); err != nil {
+			return err
+		}
+	}
+	_, _, err := writeMessage(w.w, headerSchema, w.schema(), nil)
+	return err
+}
+
+// WriteBatch writes d, which must have one column per name, as a
+// record batch.  One-dimensional arrays are written as a single
+// column.
+func (w *Writer) WriteBatch(d *array.Dense) error {
+	rows, columns := 0, 0
+	switch len(d.Shape) {
+	case 1:
+		rows, columns = d.Shape[0], 1
+	case 2:
+		rows, columns = d.Shape[0], d.Shape[1]
+	default:
+		return ErrColumns
+	}
+	if w.names == nil {
+		w.names = make([]string, columns)
+		for i := range w.names {
+			w.names[i] = fmt.Sprintf("f%d", i)
+		}
+	}
+	if columns != len(w.names) {
+		return ErrColumns
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+	c, err := littleEndian(d, array.ColumnMajorLayout)
+	if err != nil {
+		return err
+	}
+	data := c.Bytes()
+	size := int64(8 * rows)
+	nodes := make([]int64, 0, 2*columns)
+	buffers := make([]int64, 0, 4*columns)
+	body := make([][]byte, columns)
+	for j := range body {
+		body[j] = data[int64(j)*size : int64(j+1)*size]
+		nodes = append(nodes, int64(rows), 0)
+		// An empty validity buffer stands for no nulls.
+		buffers = append(buffers, int64(j)*size, 0, int64(j)*size, size)
+	}
+	header := flatbuf.Table{
+		flatbuf.Int64(int64(rows)),
+		flatbuf.Structs{Count: columns, Data: flatbuf.Int64s(nodes...)},
+		flatbuf.Structs{Count: 2 * columns, Data: flatbuf.Int64s(buffers...)},
+	}
+	offset := w.w.n
+	metaLength, bodyLength, err := writeMessage(w.w, headerRecordBatch, header, body)
+	if err != nil {
+		return err
+	}
+	w.blocks = append(w.blocks, block{offset, metaLength, bodyLength})
+	return nil
+}
+
+// Close ends the stream, writing the schema if no batch was written,
+// and the footer of files.  It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := writeEOS(w.w); err != nil {
+		return err
+	}
+	if !w.file {
+		return nil
+	}
+	blocks := make([]int64, 0, 3*len(w.blocks))
+	for _, b := range w.blocks {
+		blocks = append(blocks, b.offset, int64(b.metaLength), b.bodyLength)
+	}
+	footer := flatbuf.Encode(flatbuf.Table{
+		flatbuf.Int16(metadataVersion),
+		w.schema(),
+		flatbuf.Structs{},
+		flatbuf.Structs{Count: len(w.blocks), Data: flatbuf.Int64s(blocks...)},
+	})
+	trailer := make([]byte, 4, 4+len(fileMagic))
+	binary.LittleEndian.PutUint32(trailer, uint32(len(footer)))
+	trailer = append(trailer, fileMagic...)
+	if _, err := w.w.Write(footer); err != nil {
+		return err
+	}
+	_, err := w.w.Write(trailer)
+	return err
+}
+
+// decodeSchema returns the column names of a schema, which must only
+// have float64 columns.
+func decodeSchema(schema flatbuf.Ref) (names []string, err error) {
+	defer flatbuf.Catch(&err)
+	if schema.Int16(0, 0) != 0 {
+		return nil, ErrUnsupported
+	}
+	for _, field := range schema.Tables(1) {
+		typ, ok := field.Table(3)
+		if field.Uint8(2, 0) != typeFloatingPoint || !ok ||
+			typ.Int16(0, 0) != precisionDouble {
+			return nil, ErrUnsupported
+		}
+		names = append(names, field.String(0))
+	}
+	return names, nil
+}
+
+// decodeBatch returns the array of a record batch with the given
+// number of columns.  The array shares the memory of the body when
+// the columns have no nulls and follow each other, and copies them
+// otherwise, with nulls read as NaN.
+func decodeBatch(msg *message, columns int) (*array.Dense, error) {
+	if msg.kind != headerRecordBatch {
+		return nil, ErrInvalidMessage
+	}
+	var rows int64
+	var nodes, buffers []int64
+	err := func() (err error) {
+		defer flatbuf.Catch(&err)
+		if msg.header.Has(3) {
+			return ErrUnsupported
+		}
+		rows = msg.header.Int64(0, 0)
+		nodes = msg.header.Int64s(1, 2)
+		buffers = msg.header.Int64s(2, 2)
+		return nil
+	}()
+	if err != nil {
+		if err != ErrUnsupported {
+			err = ErrInvalidMessage
+		}
+		return nil, err
+	}
+	if rows < 0 || rows > math.MaxInt32 || len(nodes) != 2*columns ||
+		len(buffers) != 4*columns {
+		return nil, ErrInvalidMessage
+	}
+	size := 8 * rows
+	body := int64(len(msg.body))
+	slice := func(i int, min int64) ([]byte, bool) {
+		offset, length := buffers[2*i], buffers[2*i+1]
+		if offset < 0 || length < min || offset > body || length > body-offset {
+			return nil, false
+		}
+		return msg.body[offset : offset+length], true
+	}
+	shared := true
+	for j := 0; j < columns; j++ {
+		if nodes[2*j] != rows {
+			return nil, ErrInvalidMessage
+		}
+		if _, ok := slice(2*j+1, size); !ok {
+			return nil, ErrInvalidMessage
+		}
+		if nodes[2*j+1] != 0 || buffers[4*j+2] != buffers[2]+int64(j)*size {
+			shared = false
+		}
+	}
+	shape := array.Shape{int(rows), columns}
+	if shared && columns > 0 {
+		start := buffers[2]
+		strides, _ := array.NewStrides(shape, array.Float64, array.ColumnMajorLayout)
+		if d, err := wrap(msg.body[start:start+int64(columns)*size],
+			shape, strides); err == nil {
+			return d, nil
+		}
+	}
+	d, err := array.NewDense(shape, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	for j := 0; j < columns; j++ {
+		values, _ := slice(2*j+1, size)
+		validity, ok := slice(2*j, 0)
+		if !ok || (nodes[2*j+1] != 0 && int64(len(validity)) < (rows+7)/8) {
+			return nil, ErrInvalidMessage
+		}
+		for i := 0; i < int(rows); i++ {
+			v := math.Float64frombits(binary.LittleEndian.Uint64(values[8*i:]))
+			if nodes[2*j+1] != 0 && validity[i/8]&(1<<uint(i%8)) == 0 {
+				v = math.NaN()
+			}
+			if err := d.Set(array.Indices{i, j}, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return d, nil
+}
+
+// Reader reads the record batches of an Arrow stream.
+type Reader struct {
+	ctx   context.Context
+	r     io.Reader
+	names []string
+}
+
+// NewStreamReader returns a Reader of the Arrow stream r, reading its
+// schema, whose columns must all be float64.
+func NewStreamReader(r io.Reader) (*Reader, error) {
+	return NewStreamReaderContext(context.Background(), r)
+}
+
+// NewStreamReaderContext is like NewStreamReader, but the Reader stops
+// reading and returns an error wrapping the error of ctx once it is
+// done, in this call or in those of ReadBatch.
+func NewStreamReaderContext(ctx context.Context, r io.Reader) (*Reader, error) {
+	reader, err := newStreamReader(ctxio.NewReader(ctx, r))
+	if err != nil {
+		return nil, ctxio.Canceled(ctx, "read Arrow stream", err)
+	}
+	reader.ctx = ctx
+	return reader, nil
+}
+
+func newStreamReader(r io.Reader) (*Reader, error) {
+	msg, err := readMessage(r)
+	if err == io.EOF || (err == nil && msg.kind != headerSchema) {
+		return nil, ErrInvalidMessage
+	}
+	if err != nil {
+		return nil, err
+	}
+	names, err := decodeSchema(msg.header)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{r: r, names: names}, nil
+}
+
+// Names returns the names of the columns.
+func (r *Reader) Names() []string {
+	return r.names
+}
+
+// ReadBatch returns the next record batch as an array with one column
+// per column of the schema, or nil and io.EOF at the end of the
+// stream.  Nulls are read as NaN.
+func (r *Reader) ReadBatch() (*array.Dense, error) {
+	msg, err := readMessage(r.r)
+	if err == nil {
+		var d *array.Dense
+		if d, err = decodeBatch(msg, len(r.names)); err == nil {
+			return d, nil
+		}
+	}
+	if err != io.EOF {
+		err = ctxio.Canceled(r.ctx, "read Arrow stream", err)
+	}
+	return nil, err
+}
+
+// ReadFile decodes the Arrow file b, returning its column names and
+// its record batches.  Batches share the memory of b, such as a
+// memory-mapped file, when their columns have no nulls and follow
+// each other, as they do in files written by a Writer.
+func ReadFile(b []byte) ([]string, []*array.Dense, error) {
+	return ReadFileContext(context.Background(), b)
+}
+
+// ReadFileContext is like ReadFile, but stops between record batches
+// and returns an error wrapping the error of ctx once it is done.
+func ReadFileContext(ctx context.Context, b []byte) ([]string, []*array.Dense, error) {
+	n := len(b)
+	trailer := 4 + len(fileMagic)
+	if n < 8+trailer || string(b[:len(fileMagic)]) != fileMagic ||
+		string(b[n-len(fileMagic):]) != fileMagic {
+		return nil, nil, ErrInvalidMessage
+	}
+	length := int(binary.LittleEndian.Uint32(b[n-trailer:]))
+	if length <= 0 || length > n-trailer-8 {
+		return nil, nil, ErrInvalidMessage
+	}
+	footer := b[n-trailer-length : n-trailer]
+	var schema flatbuf.Ref
+	var blocks []int64
+	err := func() (err error) {
+		defer flatbuf.Catch(&err)
+		root := flatbuf.Root(footer)
+		var ok bool
+		if schema, ok = root.Table(1); !ok {
+			return ErrInvalidMessage
+		}
+		blocks = root.Int64s(3, 3)
+		return nil
+	}()
+	if err != nil {
+		return nil, nil, ErrInvalidMessage
+	}
+	names, err := decodeSchema(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	batches := make([]*array.Dense, 0, len(blocks)/3)
+	for i := 0; i < len(blocks); i += 3 {
+		if ctx.Err() != nil {
+			return nil, nil, ctxio.Canceled(ctx, "read Arrow file", nil)
+		}
+		offset := blocks[i]
+		if offset < 0 || offset >= int64(n) {
+			return nil, nil, ErrInvalidMessage
+		}
+		msg, err := parseMessage(b[offset:])
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := decodeBatch(msg, len(names))
+		if err != nil {
+			return nil, nil, err
+		}
+		batches = append(batches, d)
+	}
+	return names, batches, nil
+}
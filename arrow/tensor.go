@@ -0,0 +1,215 @@
+package arrow
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/ctxio"
+	"github.com/jimmyskull/math/internal/flatbuf"
+)
+
+// littleEndian returns d if its items fill Data in little-endian byte
+// order, and otherwise such a copy of d, in the given layout.
+func littleEndian(d *array.Dense, layout array.Attributes) (*array.Dense, error) {
+	if d.DType.ByteOrder() == binary.LittleEndian &&
+		d.IsContiguous(layout) && len(d.Data) == d.Size() {
+		return d, nil
+	}
+	c, err := array.NewDenseWithValues(d.Shape, d.Values(),
+		array.Contiguous|array.Writeable|layout)
+	if err != nil {
+		return nil, err
+	}
+	if c.DType.ByteOrder() != binary.LittleEndian {
+		if err := c.ByteSwap(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// WriteTensor writes d as an Arrow tensor message, keeping its shape
+// and strides, so that row-major and column-major arrays are read back
+// with the same layout.  Arrays that are not contiguous are written in
+// row-major layout.
+func WriteTensor(w io.Writer, d *array.Dense) error {
+	layout := array.RowMajorLayout
+	if d.Attrs.Is(array.ColumnMajorLayout) {
+		layout = array.ColumnMajorLayout
+	}
+	c, err := littleEndian(d, layout)
+	if err != nil {
+		return err
+	}
+	dims := make(flatbuf.Tables, len(c.Shape))
+	strides := make([]int64, len(c.Strides))
+	for i, n := range c.Shape {
+		dims[i] = flatbuf.Table{flatbuf.Int64(int64(n))}
+		strides[i] = int64(c.Strides[i])
+	}
+	data := c.Bytes()
+	header := flatbuf.Table{
+		flatbuf.Uint8(typeFloatingPoint),
+		flatbuf.Table{flatbuf.Int16(precisionDouble)},
+		dims,
+		flatbuf.Structs{Count: len(strides), Data: flatbuf.Int64s(strides...)},
+		flatbuf.Int64s(0, int64(len(data))),
+	}
+	_, _, err = writeMessage(w, headerTensor, header, [][]byte{data})
+	return err
+}
+
+// ReadTensor reads an Arrow tensor message of float64 items.
+func ReadTensor(r io.Reader) (*array.Dense, error) {
+	return ReadTensorContext(context.Background(), r)
+}
+
+// ReadTensorContext is like ReadTensor, but stops reading and returns
+// an error wrapping the error of ctx once it is done.
+func ReadTensorContext(ctx context.Context, r io.Reader) (*array.Dense, error) {
+	d, err := readTensor(ctxio.NewReader(ctx, r))
+	if err != nil {
+		return nil, ctxio.Canceled(ctx, "read tensor", err)
+	}
+	return d, nil
+}
+
+func readTensor(r io.Reader) (*array.Dense, error) {
+	msg, err := readMessage(r)
+	if err == io.EOF {
+		return nil, ErrInvalidMessage
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeTensor(msg)
+}
+
+// TensorFromBytes decodes an Arrow tensor message at the start of b,
+// returning an array that shares the memory of b.  The message must be
+// aligned for float64 within b, as it is in the buffers of Arrow
+// files.
+func TensorFromBytes(b []byte) (*array.Dense, error) {
+	msg, err := parseMessage(b)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTensor(msg)
+}
+
+func decodeTensor(msg *message) (d *array.Dense, err error) {
+	if msg.kind != headerTensor {
+		return nil, ErrInvalidMessage
+	}
+	var shape array.Shape
+	var strides array.Strides
+	var data []int64
+	err = func() (err error) {
+		defer flatbuf.Catch(&err)
+		h := msg.header
+		typ, ok := h.Table(1)
+		if h.Uint8(0, 0) != typeFloatingPoint || !ok ||
+			typ.Int16(0, 0) != precisionDouble {
+			return ErrUnsupported
+		}
+		shape = array.Shape{}
+		for _, dim := range h.Tables(2) {
+			shape = append(shape, int(dim.Int64(0, 0)))
+		}
+		for _, s := range h.Int64s(3, 1) {
+			strides = append(strides, int(s))
+		}
+		data = h.Struct(4, 2)
+		return nil
+	}()
+	if err != nil {
+		if err != ErrUnsupported {
+			err = ErrInvalidMessage
+		}
+		return nil, err
+	}
+	if err := shape.Validate(); err != nil || data == nil {
+		return nil, ErrInvalidMessage
+	}
+	if strides == nil {
+		if strides, err = array.NewStrides(shape, array.Float64,
+			array.RowMajorLayout); err != nil {
+			return nil, err
+		}
+	}
+	offset, length := data[0], data[1]
+	if offset < 0 || length < 0 || offset > int64(len(msg.body)) ||
+		length > int64(len(msg.body))-offset {
+		return nil, ErrInvalidMessage
+	}
+	return wrap(msg.body[offset:offset+length], shape, strides)
+}
+
+// wrap returns an array with the given shape and strides over the
+// little-endian items of b.
+func wrap(b []byte, shape array.Shape, strides array.Strides) (*array.Dense, error) {
+	if len(strides) != len(shape) {
+		return nil, ErrInvalidMessage
+	}
+	for _, s := range strides {
+		if s < 0 || s%8 != 0 {
+			return nil, ErrUnsupported
+		}
+	}
+	n, err := shape.Size()
+	if err != nil || len(b)%8 != 0 {
+		return nil, ErrInvalidMessage
+	}
+	if n > 0 {
+		// The items must lie within b, which rules out overflows.
+		extent := 8
+		if len(b) < extent {
+			return nil, ErrInvalidMessage
+		}
+		for i, s := range strides {
+			if shape[i] > 1 {
+				if s > (len(b)-extent)/(shape[i]-1) {
+					return nil, ErrInvalidMessage
+				}
+				extent += s * (shape[i] - 1)
+			}
+		}
+	}
+	dtype := array.LittleEndianFloat64
+	if dtype.Native() {
+		dtype = array.Float64
+	}
+	d, err := array.Frombuffer(b, dtype)
+	if err != nil {
+		return nil, err
+	}
+	d.Shape = shape
+	d.Strides = strides
+	d.Attrs = array.Writeable
+	if len(d.Data) == n {
+		for _, layout := range []array.Attributes{
+			array.ColumnMajorLayout, array.RowMajorLayout,
+		} {
+			s, _ := array.NewStrides(shape, array.Float64, layout)
+			if equal(s, strides) {
+				d.Attrs |= array.Contiguous | layout
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+func equal(a, b array.Strides) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
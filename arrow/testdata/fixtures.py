@@ -0,0 +1,301 @@
+#!/usr/bin/env python3
+"""Writes the Arrow IPC fixtures of the tests of the arrow package.
+
+The fixtures are assembled from the Arrow columnar format specification
+and its Schema.fbs, Message.fbs, File.fbs and Tensor.fbs schemas, with a
+FlatBuffers builder that lays out buffers back to front as the reference
+builders do, and messages framed as Arrow C++ frames them.  They share
+no code with the encoder of the package, so that the tests decode
+buffers it does not write: omitted default fields, vtables before their
+tables, nullable columns with validity bitmaps, dimension names and
+explicit tensor strides.
+
+The equivalent pyarrow calls are given with every fixture.  Run this
+script from its directory to write them again.
+"""
+
+import struct
+
+
+class Builder:
+    """A FlatBuffers builder writing from the end of the buffer."""
+
+    def __init__(self):
+        self.buf = bytearray()  # Bytes written so far, in reverse order.
+        self.minalign = 1
+        self.fields = None
+        self.object_end = 0
+        self.vtables = []
+
+    def offset(self):
+        return len(self.buf)
+
+    def pad(self, n):
+        self.buf += b"\0" * n
+
+    def prep(self, size, additional):
+        self.minalign = max(self.minalign, size)
+        self.pad((-(len(self.buf) + additional)) % size)
+
+    def put(self, fmt, value):
+        self.buf += struct.pack("<" + fmt, value)[::-1]
+
+    def prepend(self, fmt, value):
+        self.prep(struct.calcsize(fmt), 0)
+        self.put(fmt, value)
+
+    def prepend_offset(self, off):
+        self.prep(4, 0)
+        self.put("I", self.offset() - off + 4)
+
+    def string(self, s):
+        data = s.encode()
+        self.prep(4, len(data) + 1)
+        self.buf += b"\0"
+        self.buf += data[::-1]
+        self.put("I", len(data))
+        return self.offset()
+
+    def vector(self, fmt, values, count=None):
+        size = struct.calcsize(fmt)
+        self.prep(4, size * len(values))
+        self.prep(size, size * len(values))
+        for v in reversed(values):
+            self.put(fmt, v)
+        self.prep(4, 0)
+        self.put("I", len(values) if count is None else count)
+        return self.offset()
+
+    def structs(self, values, fields):
+        """A vector of structs made of the given number of longs."""
+        return self.vector("q", values, len(values) // fields)
+
+    def offsets(self, offs):
+        self.prep(4, 4 * len(offs))
+        for off in reversed(offs):
+            self.prepend_offset(off)
+        self.put("I", len(offs))
+        return self.offset()
+
+    def start(self, slots):
+        self.fields = [0] * slots
+        self.object_end = self.offset()
+
+    def add(self, slot, fmt, value, default=0):
+        # Default scalars are omitted, as the reference builders do.
+        if value != default:
+            self.prepend(fmt, value)
+            self.fields[slot] = self.offset()
+
+    def add_offset(self, slot, off):
+        self.prepend_offset(off)
+        self.fields[slot] = self.offset()
+
+    def add_struct(self, slot, values):
+        self.prep(8, 8 * len(values))
+        for v in reversed(values):
+            self.put("q", v)
+        self.fields[slot] = self.offset()
+
+    def end(self):
+        self.prepend("i", 0)
+        obj = self.offset()
+        slots = list(self.fields)
+        while slots and slots[-1] == 0:
+            slots.pop()
+        vtable = [4 + 2 * len(slots), obj - self.object_end]
+        vtable += [obj - f if f else 0 for f in slots]
+        packed = struct.pack("<%dH" % len(vtable), *vtable)
+        for at, existing in self.vtables:
+            if existing == packed:
+                break
+        else:
+            # New vtables are written before their table.
+            for v in reversed(vtable):
+                self.put("H", v)
+            at = self.offset()
+            self.vtables.append((at, packed))
+        self.patch(obj, at - obj)
+        self.fields = None
+        return obj
+
+    def patch(self, obj, soffset):
+        i = len(self.buf) - obj
+        data = bytes(self.buf[::-1])
+        data = data[:i] + struct.pack("<i", soffset) + data[i + 4:]
+        self.buf = bytearray(data[::-1])
+
+    def finish(self, root):
+        self.prep(self.minalign, 4)
+        self.prepend_offset(root)
+        return bytes(self.buf[::-1])
+
+
+# Metadata version V5, header types and the double type.
+V5 = 4
+SCHEMA, RECORD_BATCH, TENSOR = 1, 3, 4
+FLOATING_POINT, DOUBLE = 3, 2
+CONTINUATION = 0xFFFFFFFF
+
+
+def double_type(b):
+    b.start(1)
+    b.add(0, "h", DOUBLE)
+    return b.end()
+
+
+def schema(b, names):
+    fields = []
+    for name in names:
+        n = b.string(name)
+        t = double_type(b)
+        children = b.offsets([])
+        b.start(7)
+        b.add_offset(0, n)
+        b.add(1, "B", 1)
+        b.add(2, "B", FLOATING_POINT)
+        b.add_offset(3, t)
+        b.add_offset(5, children)
+        fields.append(b.end())
+    vector = b.offsets(fields)
+    b.start(4)
+    # Little endianness is the default, and is omitted.
+    b.add(0, "h", 0)
+    b.add_offset(1, vector)
+    return b.end()
+
+
+def message(kind, header, body_length):
+    b = Builder()
+    h = header(b)
+    b.start(5)
+    b.add(0, "h", V5)
+    b.add(1, "B", kind)
+    b.add_offset(2, h)
+    b.add(3, "q", body_length)
+    meta = b.finish(b.end())
+    # The metadata is padded so that the body starts at a multiple of
+    # eight bytes.
+    meta += b"\0" * ((-len(meta)) % 8)
+    return struct.pack("<II", CONTINUATION, len(meta)) + meta
+
+
+def pad8(data):
+    return data + b"\0" * ((-len(data)) % 8)
+
+
+def record_batch(columns):
+    """Columns are lists of floats, with None for nulls."""
+    rows = len(columns[0])
+    body = b""
+    nodes, buffers = [], []
+    for column in columns:
+        nulls = column.count(None)
+        validity = b""
+        if nulls:
+            bits = bytearray((rows + 7) // 8)
+            for i, v in enumerate(column):
+                if v is not None:
+                    bits[i // 8] |= 1 << (i % 8)
+            validity = bytes(bits)
+        buffers += [len(body), len(validity)]
+        body += pad8(validity)
+        values = b"".join(
+            struct.pack("<d", 0.0 if v is None else v) for v in column)
+        buffers += [len(body), len(values)]
+        body += pad8(values)
+        nodes += [rows, nulls]
+
+    def header(b):
+        buf = b.structs(buffers, 2)
+        node = b.structs(nodes, 2)
+        b.start(5)
+        b.add(0, "q", rows)
+        b.add_offset(1, node)
+        b.add_offset(2, buf)
+        return b.end()
+
+    return message(RECORD_BATCH, header, len(body)), body
+
+
+NAMES = ["x", "y"]
+BATCHES = [
+    [[1.0, 2.0, 3.0], [0.5, None, -1.5]],
+    [[4.0], [2.5]],
+]
+EOS = struct.pack("<II", CONTINUATION, 0)
+
+
+def stream():
+    """pa.ipc.new_stream(sink, schema) writing both batches."""
+    out = message(SCHEMA, lambda b: schema(b, NAMES), 0)
+    for columns in BATCHES:
+        meta, body = record_batch(columns)
+        out += meta + body
+    return out + EOS
+
+
+def file():
+    """pa.ipc.new_file(sink, schema) writing both batches."""
+    out = b"ARROW1\0\0" + message(SCHEMA, lambda b: schema(b, NAMES), 0)
+    blocks = []
+    for columns in BATCHES:
+        meta, body = record_batch(columns)
+        # Blocks are structs of a long, an int padded to eight bytes and
+        # a long.
+        blocks += [len(out), len(meta), len(body)]
+        out += meta + body
+    out += EOS
+    b = Builder()
+    s = schema(b, NAMES)
+    batches = b.structs(blocks, 3)
+    dictionaries = b.structs([], 3)
+    b.start(5)
+    b.add(0, "h", V5)
+    b.add_offset(1, s)
+    b.add_offset(2, dictionaries)
+    b.add_offset(3, batches)
+    footer = b.finish(b.end())
+    return out + footer + struct.pack("<I", len(footer)) + b"ARROW1"
+
+
+def tensor(shape, strides, names, values):
+    """pa.ipc.write_tensor(pa.Tensor.from_numpy(a, dim_names), sink)."""
+    data = b"".join(struct.pack("<d", v) for v in values)
+
+    def header(b):
+        dims = []
+        for size, name in zip(shape, names):
+            n = b.string(name)
+            b.start(2)
+            b.add(0, "q", size)
+            b.add_offset(1, n)
+            dims.append(b.end())
+        t = double_type(b)
+        dim_vector = b.offsets(dims)
+        stride_vector = b.vector("q", strides)
+        b.start(5)
+        b.add(0, "B", FLOATING_POINT)
+        b.add_offset(1, t)
+        b.add_offset(2, dim_vector)
+        b.add_offset(3, stride_vector)
+        b.add_struct(4, [0, len(data)])
+        return b.end()
+
+    return message(TENSOR, header, len(pad8(data))) + pad8(data)
+
+
+FIXTURES = {
+    "stream.arrows": stream,
+    "file.arrow": file,
+    # np.arange(1., 7.).reshape(2, 3), in C and Fortran order.
+    "tensor.arrow": lambda: tensor(
+        [2, 3], [24, 8], ["rows", "columns"], [1, 2, 3, 4, 5, 6]),
+    "tensor_fortran.arrow": lambda: tensor(
+        [2, 3], [8, 16], ["rows", "columns"], [1, 4, 2, 5, 3, 6]),
+}
+
+if __name__ == "__main__":
+    for name, build in FIXTURES.items():
+        with open(name, "wb") as f:
+            f.write(build())
@@ -0,0 +1,182 @@
+package autodiff
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// unbroadcast returns the gradient of an operand of the given shape
+// from the gradient g of its broadcast to gshape, summing g over the
+// stretched dimensions.  Both gradients are in row-major order.
+func unbroadcast(g []float64, gshape, shape array.Shape) []float64 {
+	if sameShape(gshape, shape) {
+		return g
+	}
+	size, _ := shape.Size()
+	out := make([]float64, size)
+	offset := len(gshape) - len(shape)
+	index := make([]int, len(gshape))
+	for _, v := range g {
+		pos := 0
+		for axis, dim := range shape {
+			i := index[offset+axis]
+			if dim == 1 {
+				i = 0
+			}
+			pos = pos*dim + i
+		}
+		out[pos] += v
+		for axis := len(gshape) - 1; axis >= 0; axis-- {
+			index[axis]++
+			if index[axis] < gshape[axis] {
+				break
+			}
+			index[axis] = 0
+		}
+	}
+	return out
+}
+
+// unary records an elementwise function f of x, whose derivative df is
+// given the item of x and the item of the result.
+func unary(x *Var, f func(float64) float64, df func(x, y float64) float64) (*Var, error) {
+	y, err := array.Map(x.Value, f)
+	if err != nil {
+		return nil, err
+	}
+	return x.tape.record(y, func(grad []float64, grads [][]float64) {
+		xv, yv := x.Value.Values(), y.Values()
+		delta := make([]float64, len(grad))
+		for i, g := range grad {
+			delta[i] = g * df(xv[i], yv[i])
+		}
+		accumulate(grads, x, delta)
+	}), nil
+}
+
+// binary records an elementwise function f of a and b broadcast
+// together, whose partial derivatives da and db are given the items of
+// a and b.
+func binary(
+	a, b *Var, f func(x, y float64) float64, da, db func(x, y float64) float64,
+) (*Var, error) {
+	t, err := tapeOf(a, b)
+	if err != nil {
+		return nil, err
+	}
+	z, err := array.Map2(a.Value, b.Value, f)
+	if err != nil {
+		return nil, err
+	}
+	return t.record(z, func(grad []float64, grads [][]float64) {
+		av, _ := a.Value.BroadcastTo(z.Shape)
+		bv, _ := b.Value.BroadcastTo(z.Shape)
+		x, y := av.Values(), bv.Values()
+		ga, gb := make([]float64, len(grad)), make([]float64, len(grad))
+		for i, g := range grad {
+			ga[i] = g * da(x[i], y[i])
+			gb[i] = g * db(x[i], y[i])
+		}
+		accumulate(grads, a, unbroadcast(ga, z.Shape, a.Value.Shape))
+		accumulate(grads, b, unbroadcast(gb, z.Shape, b.Value.Shape))
+	}), nil
+}
+
+// Add returns a + b, broadcast together.
+func Add(a, b *Var) (*Var, error) {
+	return binary(a, b,
+		func(x, y float64) float64 { return x + y },
+		func(x, y float64) float64 { return 1 },
+		func(x, y float64) float64 { return 1 })
+}
+
+// Sub returns a - b, broadcast together.
+func Sub(a, b *Var) (*Var, error) {
+	return binary(a, b,
+		func(x, y float64) float64 { return x - y },
+		func(x, y float64) float64 { return 1 },
+		func(x, y float64) float64 { return -1 })
+}
+
+// Mul returns the products of the items of a and b, broadcast together.
+func Mul(a, b *Var) (*Var, error) {
+	return binary(a, b,
+		func(x, y float64) float64 { return x * y },
+		func(x, y float64) float64 { return y },
+		func(x, y float64) float64 { return x })
+}
+
+// Div returns the quotients of the items of a and b, broadcast
+// together.
+func Div(a, b *Var) (*Var, error) {
+	return binary(a, b,
+		func(x, y float64) float64 { return x / y },
+		func(x, y float64) float64 { return 1 / y },
+		func(x, y float64) float64 { return -x / (y * y) })
+}
+
+// Neg returns -x.
+func Neg(x *Var) (*Var, error) {
+	return unary(x,
+		func(x float64) float64 { return -x },
+		func(x, y float64) float64 { return -1 })
+}
+
+// Pow returns the items of x raised to the power p.
+func Pow(x *Var, p float64) (*Var, error) {
+	return unary(x,
+		func(x float64) float64 { return math.Pow(x, p) },
+		func(x, y float64) float64 { return p * math.Pow(x, p-1) })
+}
+
+// Exp returns the exponentials of the items of x.
+func Exp(x *Var) (*Var, error) {
+	return unary(x, math.Exp, func(x, y float64) float64 { return y })
+}
+
+// Log returns the natural logarithms of the items of x.
+func Log(x *Var) (*Var, error) {
+	return unary(x, math.Log, func(x, y float64) float64 { return 1 / x })
+}
+
+// Sqrt returns the square roots of the items of x.
+func Sqrt(x *Var) (*Var, error) {
+	return unary(x, math.Sqrt, func(x, y float64) float64 { return 0.5 / y })
+}
+
+// Sin returns the sines of the items of x, in radians.
+func Sin(x *Var) (*Var, error) {
+	return unary(x, math.Sin, func(x, y float64) float64 { return math.Cos(x) })
+}
+
+// Cos returns the cosines of the items of x, in radians.
+func Cos(x *Var) (*Var, error) {
+	return unary(x, math.Cos, func(x, y float64) float64 { return -math.Sin(x) })
+}
+
+// Tanh returns the hyperbolic tangents of the items of x.
+func Tanh(x *Var) (*Var, error) {
+	return unary(x, math.Tanh, func(x, y float64) float64 { return 1 - y*y })
+}
+
+// Sigmoid returns the logistic function 1 / (1 + exp(-x)) of the items
+// of x.
+func Sigmoid(x *Var) (*Var, error) {
+	return unary(x,
+		func(x float64) float64 { return 1 / (1 + math.Exp(-x)) },
+		func(x, y float64) float64 { return y * (1 - y) })
+}
+
+// Relu returns the items of x where they are positive, and zero
+// elsewhere.  Its derivative at zero is taken as zero.
+func Relu(x *Var) (*Var, error) {
+	return unary(x,
+		func(x float64) float64 { return math.Max(x, 0) },
+		func(x, y float64) float64 {
+			if x > 0 {
+				return 1
+			}
+			return 0
+		})
+}
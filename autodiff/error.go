@@ -0,0 +1,22 @@
+package autodiff
+
+import "errors"
+
+var (
+	// ErrTapeMismatch is returned when the operands of an operation, or
+	// the variables of a gradient, were recorded on different tapes.
+	ErrTapeMismatch = errors.New("variables belong to different tapes")
+
+	// ErrNotScalar is returned when computing the gradients of an output
+	// that has more than one item.
+	ErrNotScalar = errors.New("output of gradients must have one item")
+
+	// ErrDimensionMismatch is returned when the operands of a matrix
+	// product are not matrices with a common inner dimension.
+	ErrDimensionMismatch = errors.New(
+		"operands must be matrices with matching inner dimensions")
+
+	// ErrInvalidSlice is returned when the bounds of a slice are not
+	// increasing within the length of its axis.
+	ErrInvalidSlice = errors.New("slice bounds out of range")
+)
@@ -0,0 +1,49 @@
+package autodiff
+
+import (
+	"context"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// matmul returns the product of an m×k and a k×n matrix in row-major
+// order, computed by array.MatMul.
+func matmul(a, b []float64, m, k, n int) []float64 {
+	rowMajor := array.Contiguous | array.Writeable | array.RowMajorLayout
+	x, _ := array.NewDenseWithValues(array.Shape{m, k}, a, rowMajor)
+	y, _ := array.NewDenseWithValues(array.Shape{k, n}, b, rowMajor)
+	c, _ := array.MatMul(x, y)
+	return c.Values()
+}
+
+// MatMul returns the matrix product of a, an m×k matrix, and b, a k×n
+// matrix.  The product and its gradients are computed by array.MatMul,
+// split among goroutines as set by array.SetNumThreads.
+func MatMul(a, b *Var) (*Var, error) {
+	return MatMulContext(context.Background(), a, b)
+}
+
+// MatMulContext is like MatMul, but stops early and returns an error
+// wrapping the error of ctx once it is done.  Only the product is
+// computed under ctx; gradients are computed later by the tape.
+func MatMulContext(ctx context.Context, a, b *Var) (*Var, error) {
+	t, err := tapeOf(a, b)
+	if err != nil {
+		return nil, err
+	}
+	as, bs := a.Value.Shape, b.Value.Shape
+	if len(as) != 2 || len(bs) != 2 || as[1] != bs[0] {
+		return nil, ErrDimensionMismatch
+	}
+	m, k, n := as[0], as[1], bs[1]
+	av, bv := a.Value.Values(), b.Value.Values()
+	c, err := array.MatMulContext(ctx, a.Value, b.Value)
+	if err != nil {
+		return nil, err
+	}
+	return t.record(c, func(grad []float64, grads [][]float64) {
+		// dA = G Bᵀ and dB = Aᵀ G.
+		accumulate(grads, a, matmul(grad, transpose(bv, k, n), m, n, k))
+		accumulate(grads, b, matmul(transpose(av, m, k), grad, k, m, n))
+	}), nil
+}
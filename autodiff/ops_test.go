@@ -0,0 +1,168 @@
+package autodiff_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/autodiff"
+	"github.com/jimmyskull/math/internal/arraytest"
+)
+
+func TestElementwiseGradients(t *testing.T) {
+	x := arraytest.Dense(t, array.Shape{2, 3}, 0.5, 1, 1.5, 2, 2.5, 3)
+	// Weights make every item contribute differently.
+	w := arraytest.Dense(t, array.Shape{2, 3}, 1, -2, 3, 0.5, 2, -1)
+	unaries := map[string]func(*autodiff.Var) (*autodiff.Var, error){
+		"neg":     autodiff.Neg,
+		"exp":     autodiff.Exp,
+		"log":     autodiff.Log,
+		"sqrt":    autodiff.Sqrt,
+		"sin":     autodiff.Sin,
+		"cos":     autodiff.Cos,
+		"tanh":    autodiff.Tanh,
+		"sigmoid": autodiff.Sigmoid,
+		"relu":    autodiff.Relu,
+		"pow": func(x *autodiff.Var) (*autodiff.Var, error) {
+			return autodiff.Pow(x, 2.5)
+		},
+	}
+	for name, fn := range unaries {
+		t.Run(name, func(t *testing.T) {
+			checkGradients(t, func(v []*autodiff.Var) (*autodiff.Var, error) {
+				y, err := fn(v[0])
+				if err != nil {
+					return nil, err
+				}
+				y, _ = autodiff.Mul(y, v[0].Tape().Constant(w))
+				return autodiff.SumAll(y)
+			}, x)
+		})
+	}
+}
+
+func TestBroadcastGradients(t *testing.T) {
+	a := arraytest.Dense(t, array.Shape{2, 3}, 1, 2, 3, 4, 5, 6)
+	b := arraytest.Dense(t, array.Shape{3}, 0.5, -1, 2)
+	c := arraytest.Dense(t, array.Shape{2, 1}, 3, -2)
+	binaries := map[string]func(a, b *autodiff.Var) (*autodiff.Var, error){
+		"add": autodiff.Add,
+		"sub": autodiff.Sub,
+		"mul": autodiff.Mul,
+		"div": autodiff.Div,
+	}
+	for name, fn := range binaries {
+		t.Run(name, func(t *testing.T) {
+			checkGradients(t, func(v []*autodiff.Var) (*autodiff.Var, error) {
+				ab, err := fn(v[0], v[1])
+				if err != nil {
+					return nil, err
+				}
+				abc, err := fn(ab, v[2])
+				if err != nil {
+					return nil, err
+				}
+				sq, _ := autodiff.Pow(abc, 2)
+				return autodiff.SumAll(sq)
+			}, a, b, c)
+		})
+	}
+}
+
+func TestReductionGradients(t *testing.T) {
+	x := arraytest.Dense(t, array.Shape{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	for _, axis := range []int{0, 1, -1} {
+		for _, fn := range []func(*autodiff.Var, int) (*autodiff.Var, error){autodiff.Sum, autodiff.Mean} {
+			checkGradients(t, func(v []*autodiff.Var) (*autodiff.Var, error) {
+				s, err := fn(v[0], axis)
+				if err != nil {
+					return nil, err
+				}
+				sq, _ := autodiff.Pow(s, 2)
+				return autodiff.MeanAll(sq)
+			}, x)
+		}
+	}
+	tape := autodiff.NewTape()
+	s, err := autodiff.Sum(tape.Variable(x), 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, s.Value.Shape)
+		assert.Equal(t, []float64{9, 12, 27, 30}, s.Value.Values())
+	}
+	_, err = autodiff.Sum(tape.Variable(x), 3)
+	assert.Error(t, err)
+}
+
+func TestMatMulGradients(t *testing.T) {
+	a := arraytest.Dense(t, array.Shape{2, 3}, 1, -2, 3, 0.5, 5, -1)
+	b := arraytest.Dense(t, array.Shape{3, 2}, 2, 1, 0, -1, 3, 4)
+	tape := autodiff.NewTape()
+	c, err := autodiff.MatMul(tape.Variable(a), tape.Variable(b))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{11, 15, -2, -8.5}, c.Value.Values())
+	}
+	checkGradients(t, func(v []*autodiff.Var) (*autodiff.Var, error) {
+		c, err := autodiff.MatMul(v[0], v[1])
+		if err != nil {
+			return nil, err
+		}
+		tanh, _ := autodiff.Tanh(c)
+		return autodiff.SumAll(tanh)
+	}, arraytest.Dense(t, a.Shape, 0.1, -0.2, 0.3, 0.05, 0.5, -0.1), b)
+
+	// Zeros times infinities give NaN, as in IEEE arithmetic.
+	c, err = autodiff.MatMul(
+		tape.Variable(arraytest.Dense(t, array.Shape{1, 1}, 0)),
+		tape.Variable(arraytest.Dense(t, array.Shape{1, 1}, math.Inf(1))))
+	if assert.NoError(t, err) {
+		assert.True(t, math.IsNaN(c.Value.Values()[0]))
+	}
+
+	_, err = autodiff.MatMul(tape.Variable(a), tape.Variable(a))
+	assert.Equal(t, autodiff.ErrDimensionMismatch, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = autodiff.MatMulContext(ctx, tape.Variable(a), tape.Variable(b))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestShapeGradients(t *testing.T) {
+	x := arraytest.Dense(t, array.Shape{2, 4}, 1, 2, 3, 4, 5, 6, 7, 8)
+	tape := autodiff.NewTape()
+	s, err := autodiff.Slice(tape.Variable(x), 1, 1, 3)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, s.Value.Shape)
+		assert.Equal(t, []float64{2, 3, 6, 7}, s.Value.Values())
+	}
+	r, err := autodiff.Reshape(tape.Variable(x), array.Shape{4, 2})
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{4, 2}, r.Value.Shape)
+		assert.Equal(t, x.Values(), r.Value.Values())
+	}
+	tr, err := autodiff.Transpose(tape.Variable(x))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{1, 5, 2, 6, 3, 7, 4, 8}, tr.Value.Values())
+	}
+
+	checkGradients(t, func(v []*autodiff.Var) (*autodiff.Var, error) {
+		s, err := autodiff.Slice(v[0], -1, 1, 4)
+		if err != nil {
+			return nil, err
+		}
+		r, err := autodiff.Reshape(s, array.Shape{3, 2})
+		if err != nil {
+			return nil, err
+		}
+		tr, _ := autodiff.Transpose(r)
+		m, _ := autodiff.MatMul(tr, r)
+		return autodiff.SumAll(m)
+	}, x)
+
+	_, err = autodiff.Slice(tape.Variable(x), 0, 1, 3)
+	assert.Equal(t, autodiff.ErrInvalidSlice, err)
+	_, err = autodiff.Reshape(tape.Variable(x), array.Shape{3})
+	assert.Equal(t, array.ErrInvalidValuesLength, err)
+}
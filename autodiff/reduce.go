@@ -0,0 +1,91 @@
+package autodiff
+
+import (
+	"github.com/jimmyskull/math/array"
+)
+
+// split returns the number of items before, along and after an axis of
+// a shape, counting negative axes from the last dimension.
+func split(shape array.Shape, axis int) (outer, n, inner, normalized int, err error) {
+	ndim := len(shape)
+	if axis < -ndim || axis >= ndim {
+		return 0, 0, 0, 0, &array.AxisError{Axis: axis, NDim: ndim}
+	}
+	if axis < 0 {
+		axis += ndim
+	}
+	outer, inner = 1, 1
+	for k, dim := range shape {
+		switch {
+		case k < axis:
+			outer *= dim
+		case k > axis:
+			inner *= dim
+		}
+	}
+	return outer, shape[axis], inner, axis, nil
+}
+
+// Sum returns the sums of the items of x along axis, with the shape of
+// x without axis.  Negative axes count from the last dimension.
+func Sum(x *Var, axis int) (*Var, error) {
+	return scaledSum(x, axis, false)
+}
+
+// Mean returns the means of the items of x along axis, with the shape
+// of x without axis.  Negative axes count from the last dimension.
+func Mean(x *Var, axis int) (*Var, error) {
+	return scaledSum(x, axis, true)
+}
+
+func scaledSum(x *Var, axis int, mean bool) (*Var, error) {
+	outer, n, inner, _, err := split(x.Value.Shape, axis)
+	if err != nil {
+		return nil, err
+	}
+	y, err := array.Sum(x.Value, axis)
+	if err != nil {
+		return nil, err
+	}
+	scale := 1.0
+	if mean {
+		scale = 1 / float64(n)
+		if y, err = array.Map(y, func(v float64) float64 { return v * scale }); err != nil {
+			return nil, err
+		}
+	}
+	return x.tape.record(y, func(grad []float64, grads [][]float64) {
+		delta := make([]float64, outer*n*inner)
+		for o := 0; o < outer; o++ {
+			for k := 0; k < n; k++ {
+				for c := 0; c < inner; c++ {
+					delta[(o*n+k)*inner+c] = grad[o*inner+c] * scale
+				}
+			}
+		}
+		accumulate(grads, x, delta)
+	}), nil
+}
+
+// SumAll returns the sum of all the items of x, a zero-dimensional
+// variable.
+func SumAll(x *Var) (*Var, error) {
+	return scaledSumAll(x, 1)
+}
+
+// MeanAll returns the mean of all the items of x, a zero-dimensional
+// variable.
+func MeanAll(x *Var) (*Var, error) {
+	return scaledSumAll(x, 1/float64(x.Value.Size()))
+}
+
+func scaledSumAll(x *Var, scale float64) (*Var, error) {
+	y := array.Scalar(array.SumAll(x.Value) * scale)
+	return x.tape.record(y, func(grad []float64, grads [][]float64) {
+		delta := make([]float64, x.Value.Size())
+		for i := range delta {
+			delta[i] = grad[0] * scale
+		}
+		accumulate(grads, x, delta)
+	}), nil
+}
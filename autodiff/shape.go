@@ -0,0 +1,89 @@
+package autodiff
+
+import (
+	"github.com/jimmyskull/math/array"
+)
+
+func sameShape(a, b array.Shape) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Reshape returns the items of x in row-major order with another shape
+// of the same size.
+func Reshape(x *Var, shape array.Shape) (*Var, error) {
+	y, err := array.NewDenseWithValues(shape, x.Value.Values(), array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return x.tape.record(y, func(grad []float64, grads [][]float64) {
+		accumulate(grads, x, grad)
+	}), nil
+}
+
+// Slice returns the items of x whose index along axis is within [start,
+// stop).  Negative axes count from the last dimension.
+func Slice(x *Var, axis, start, stop int) (*Var, error) {
+	outer, n, inner, axis, err := split(x.Value.Shape, axis)
+	if err != nil {
+		return nil, err
+	}
+	if start < 0 || start > stop || stop > n {
+		return nil, ErrInvalidSlice
+	}
+	m := stop - start
+	values := x.Value.Values()
+	out := make([]float64, 0, outer*m*inner)
+	for o := 0; o < outer; o++ {
+		from := (o*n + start) * inner
+		out = append(out, values[from:from+m*inner]...)
+	}
+	shape := append(array.Shape{}, x.Value.Shape...)
+	shape[axis] = m
+	y, err := array.NewDenseWithValues(shape, out, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return x.tape.record(y, func(grad []float64, grads [][]float64) {
+		delta := make([]float64, len(values))
+		for o := 0; o < outer; o++ {
+			copy(delta[(o*n+start)*inner:], grad[o*m*inner:(o+1)*m*inner])
+		}
+		accumulate(grads, x, delta)
+	}), nil
+}
+
+// Transpose returns the transpose of a matrix.
+func Transpose(x *Var) (*Var, error) {
+	if len(x.Value.Shape) != 2 {
+		return nil, ErrDimensionMismatch
+	}
+	rows, cols := x.Value.Shape[0], x.Value.Shape[1]
+	y, err := array.NewDenseWithValues(
+		array.Shape{cols, rows}, transpose(x.Value.Values(), rows, cols), array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return x.tape.record(y, func(grad []float64, grads [][]float64) {
+		accumulate(grads, x, transpose(grad, cols, rows))
+	}), nil
+}
+
+// transpose returns the transpose of a rows×cols matrix in row-major
+// order.
+func transpose(m []float64, rows, cols int) []float64 {
+	out := make([]float64, len(m))
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			out[j*rows+i] = m[i*cols+j]
+		}
+	}
+	return out
+}
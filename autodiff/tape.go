@@ -0,0 +1,143 @@
+// Package autodiff computes gradients of computations on arrays by
+// reverse-mode automatic differentiation.
+//
+// Operations on variables are recorded on a Tape as they are computed.
+// Gradients of a scalar output with respect to any recorded variable
+// are then obtained in one backward pass over the tape, applying the
+// chain rule from the output to the inputs:
+//
+//	tape := autodiff.NewTape()
+//	w := tape.Variable(weights)
+//	y, _ := autodiff.MatMul(tape.Constant(x), w)
+//	loss, _ := autodiff.MeanAll(y)
+//	grads, _ := tape.Gradients(loss, w)
+//
+// Operations broadcast their operands as the array package does, and
+// the gradient of a broadcast operand is summed over the stretched
+// dimensions.
+package autodiff
+
+import (
+	"github.com/jimmyskull/math/array"
+)
+
+// Tape records the operations on variables for the computation of their
+// gradients.  A Tape is not safe for concurrent use.
+type Tape struct {
+	nodes []*node
+}
+
+// node is an operation recorded on a tape.
+type node struct {
+	shape array.Shape
+	// backward adds to the gradients of the operands the contribution
+	// of grad, the gradient of the node, both in row-major order.
+	backward func(grad []float64, grads [][]float64)
+}
+
+// Var is a variable of a computation recorded on a tape: an input, or
+// the result of an operation on other variables.
+type Var struct {
+	tape *Tape
+	id   int
+	// Value is the value of the variable.  It must not be modified.
+	Value *array.Dense
+}
+
+// Tape returns the tape on which the variable is recorded.
+func (v *Var) Tape() *Tape {
+	return v.tape
+}
+
+// NewTape returns an empty tape.
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Variable returns an input variable recorded on the tape, whose
+// gradient may be computed.
+func (t *Tape) Variable(value *array.Dense) *Var {
+	return t.record(value, nil)
+}
+
+// Constant returns an input variable recorded on the tape.  Constants
+// are variables like others; the name only marks those whose gradients
+// are not needed.
+func (t *Tape) Constant(value *array.Dense) *Var {
+	return t.record(value, nil)
+}
+
+// record adds a node to the tape and returns its variable.
+func (t *Tape) record(value *array.Dense, backward func(grad []float64, grads [][]float64)) *Var {
+	t.nodes = append(t.nodes, &node{
+		shape:    append(array.Shape{}, value.Shape...),
+		backward: backward,
+	})
+	return &Var{tape: t, id: len(t.nodes) - 1, Value: value}
+}
+
+// tapeOf returns the tape of variables, which must all be the same.
+func tapeOf(vars ...*Var) (*Tape, error) {
+	t := vars[0].tape
+	for _, v := range vars[1:] {
+		if v.tape != t {
+			return nil, ErrTapeMismatch
+		}
+	}
+	return t, nil
+}
+
+// Gradients returns the gradients of output, which must have a single
+// item, with respect to the variables wrt, arrays with the shapes of
+// their values.  The tape is kept, so that gradients of other outputs
+// may be computed.
+func (t *Tape) Gradients(output *Var, wrt ...*Var) ([]*array.Dense, error) {
+	if output.tape != t {
+		return nil, ErrTapeMismatch
+	}
+	for _, v := range wrt {
+		if v.tape != t {
+			return nil, ErrTapeMismatch
+		}
+	}
+	if output.Value.Size() != 1 {
+		return nil, ErrNotScalar
+	}
+	grads := make([][]float64, output.id+1)
+	grads[output.id] = []float64{1}
+	// Nodes are recorded after their operands, so that visiting them
+	// backwards completes the gradient of every node before its use.
+	for id := output.id; id >= 0; id-- {
+		n := t.nodes[id]
+		if grads[id] == nil || n.backward == nil {
+			continue
+		}
+		n.backward(grads[id], grads)
+	}
+	out := make([]*array.Dense, len(wrt))
+	for i, v := range wrt {
+		g := make([]float64, v.Value.Size())
+		if v.id < len(grads) && grads[v.id] != nil {
+			copy(g, grads[v.id])
+		}
+		d, err := array.NewDenseWithValues(v.Value.Shape, g, array.DefaultAttributes)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = d
+	}
+	return out, nil
+}
+
+// accumulate adds delta to the gradient of a variable, allocating it on
+// first use.
+func accumulate(grads [][]float64, v *Var, delta []float64) {
+	g := grads[v.id]
+	if g == nil {
+		g = make([]float64, len(delta))
+		grads[v.id] = g
+	}
+	for i, d := range delta {
+		g[i] += d
+	}
+}
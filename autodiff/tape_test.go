@@ -0,0 +1,122 @@
+package autodiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/autodiff"
+	"github.com/jimmyskull/math/internal/arraytest"
+)
+
+// checkGradients compares the gradients of the scalar computed by fn
+// from the inputs with central differences.
+func checkGradients(
+	t *testing.T, fn func(vars []*autodiff.Var) (*autodiff.Var, error), inputs ...*array.Dense,
+) {
+	t.Helper()
+	eval := func(values []*array.Dense) (*autodiff.Tape, []*autodiff.Var, *autodiff.Var) {
+		tape := autodiff.NewTape()
+		vars := make([]*autodiff.Var, len(values))
+		for i, v := range values {
+			vars[i] = tape.Variable(v)
+		}
+		out, err := fn(vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tape, vars, out
+	}
+	tape, vars, out := eval(inputs)
+	grads, err := tape.Gradients(out, vars...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	const h = 1e-6
+	for i, in := range inputs {
+		assert.Equal(t, in.Shape, grads[i].Shape)
+		got := grads[i].Values()
+		values := in.Values()
+		for j := range values {
+			shifted := func(delta float64) float64 {
+				v := append([]float64(nil), values...)
+				v[j] += delta
+				moved := append([]*array.Dense(nil), inputs...)
+				moved[i] = arraytest.Dense(t, in.Shape, v...)
+				_, _, out := eval(moved)
+				return array.SumAll(out.Value)
+			}
+			want := (shifted(h) - shifted(-h)) / (2 * h)
+			assert.InDelta(t, want, got[j], 1e-6, "input %d, item %d", i, j)
+		}
+	}
+}
+
+func TestGradients(t *testing.T) {
+	tape := autodiff.NewTape()
+	x := tape.Variable(arraytest.Dense(t, array.Shape{2}, 1, 2))
+	y := tape.Variable(arraytest.Dense(t, array.Shape{2}, 3, 4))
+	unused := tape.Variable(arraytest.Dense(t, array.Shape{3}, 0, 0, 0))
+	// Variables used twice collect both contributions.
+	xy, _ := autodiff.Mul(x, y)
+	xx, _ := autodiff.Mul(x, x)
+	sum, _ := autodiff.Add(xy, xx)
+	out, _ := autodiff.SumAll(sum)
+	assert.Equal(t, 3.0+4*2+1+4, out.Value.Values()[0])
+	grads, err := tape.Gradients(out, x, y, unused)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{5, 8}, grads[0].Values())
+		assert.Equal(t, []float64{1, 2}, grads[1].Values())
+		assert.Equal(t, []float64{0, 0, 0}, grads[2].Values())
+	}
+
+	// The tape may be used again for another output.
+	out2, _ := autodiff.SumAll(xx)
+	grads, err = tape.Gradients(out2, x)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{2, 4}, grads[0].Values())
+	}
+}
+
+func TestGradientsErrors(t *testing.T) {
+	tape, other := autodiff.NewTape(), autodiff.NewTape()
+	x := tape.Variable(arraytest.Dense(t, array.Shape{2}, 1, 2))
+	y := other.Variable(arraytest.Dense(t, array.Shape{2}, 3, 4))
+	_, err := autodiff.Add(x, y)
+	assert.Equal(t, autodiff.ErrTapeMismatch, err)
+	_, err = tape.Gradients(x, y)
+	assert.Equal(t, autodiff.ErrTapeMismatch, err)
+	_, err = tape.Gradients(x, x)
+	assert.Equal(t, autodiff.ErrNotScalar, err)
+}
+
+func TestTraining(t *testing.T) {
+	// Least squares fit of y = 2 x0 - x1 + 0.5 by gradient descent.
+	x := arraytest.Dense(t, array.Shape{4, 2}, 0, 0, 1, 0, 0, 1, 1, 1)
+	y := arraytest.Dense(t, array.Shape{4, 1}, 0.5, 2.5, -0.5, 1.5)
+	w := arraytest.Dense(t, array.Shape{2, 1}, 0, 0)
+	b := array.Scalar(0)
+	for step := 0; step < 2000; step++ {
+		tape := autodiff.NewTape()
+		wv, bv := tape.Variable(w), tape.Variable(b)
+		pred, _ := autodiff.MatMul(tape.Constant(x), wv)
+		pred, _ = autodiff.Add(pred, bv)
+		diff, _ := autodiff.Sub(pred, tape.Constant(y))
+		sq, _ := autodiff.Pow(diff, 2)
+		loss, _ := autodiff.MeanAll(sq)
+		grads, err := tape.Gradients(loss, wv, bv)
+		if !assert.NoError(t, err) {
+			return
+		}
+		update := func(d, g *array.Dense) *array.Dense {
+			out, _ := array.Map2(d, g, func(v, g float64) float64 { return v - 0.1*g })
+			return out
+		}
+		w, b = update(w, grads[0]), update(b, grads[1])
+	}
+	values := w.Values()
+	assert.InDelta(t, 2, values[0], 1e-6)
+	assert.InDelta(t, -1, values[1], 1e-6)
+	assert.InDelta(t, 0.5, b.Values()[0], 1e-6)
+}
@@ -0,0 +1,239 @@
+// Package expr builds deferred expressions of elementwise operations on
+// arrays, evaluated in a single pass.
+//
+// Operations on expressions record a tree instead of computing arrays,
+// so that
+//
+//	e := expr.Sub(expr.Add(expr.Mul(a, b), expr.Mul(c, d)), e)
+//	out, err := expr.Eval(e)
+//
+// broadcasts the arrays together and computes every item of the result
+// from the items of the arrays at once, without intermediate arrays for
+// the products and the sum.  Arrays are read when the expression is
+// evaluated, not when it is built.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Expr is a node of an expression tree: an array, a constant, or an
+// elementwise operation on other expressions.
+type Expr struct {
+	name     string
+	leaf     *array.Dense
+	value    float64
+	operands []*Expr
+	// Operations have one of these functions, by number of operands.
+	fn1 func(x float64) float64
+	fn2 func(x, y float64) float64
+	fn3 func(x, y, z float64) float64
+	fn  func(args []float64) float64
+}
+
+// Array returns an expression whose value is the array d.
+func Array(d *array.Dense) *Expr {
+	return &Expr{leaf: d}
+}
+
+// Const returns an expression whose value is the constant v, broadcast
+// against the other operands.
+func Const(v float64) *Expr {
+	return &Expr{value: v}
+}
+
+// Apply returns an expression applying fn to the items of the operands
+// broadcast together.  The name is used by String.  The slice given to
+// fn holds one item of each operand and must not be retained; fn may be
+// called concurrently.  Unlike the operations of this package, Apply
+// allocates the slice for every item.
+func Apply(name string, fn func(args []float64) float64, operands ...*Expr) *Expr {
+	return &Expr{name: name, fn: fn, operands: append([]*Expr{}, operands...)}
+}
+
+func unary(name string, fn func(float64) float64, x *Expr) *Expr {
+	return &Expr{name: name, fn1: fn, operands: []*Expr{x}}
+}
+
+func binary(name string, fn func(x, y float64) float64, a, b *Expr) *Expr {
+	return &Expr{name: name, fn2: fn, operands: []*Expr{a, b}}
+}
+
+// Add returns a + b.
+func Add(a, b *Expr) *Expr {
+	return binary("+", func(x, y float64) float64 { return x + y }, a, b)
+}
+
+// Sub returns a - b.
+func Sub(a, b *Expr) *Expr {
+	return binary("-", func(x, y float64) float64 { return x - y }, a, b)
+}
+
+// Mul returns the product of a and b.
+func Mul(a, b *Expr) *Expr {
+	return binary("*", func(x, y float64) float64 { return x * y }, a, b)
+}
+
+// Div returns the quotient of a and b.
+func Div(a, b *Expr) *Expr {
+	return binary("/", func(x, y float64) float64 { return x / y }, a, b)
+}
+
+// Pow returns a raised to the power b.
+func Pow(a, b *Expr) *Expr {
+	return binary("**", math.Pow, a, b)
+}
+
+// Max returns the larger of a and b.
+func Max(a, b *Expr) *Expr {
+	return binary("max", math.Max, a, b)
+}
+
+// Min returns the smaller of a and b.
+func Min(a, b *Expr) *Expr {
+	return binary("min", math.Min, a, b)
+}
+
+// Neg returns -x.
+func Neg(x *Expr) *Expr {
+	return unary("neg", func(v float64) float64 { return -v }, x)
+}
+
+// Abs returns the absolute value of x.
+func Abs(x *Expr) *Expr {
+	return unary("abs", math.Abs, x)
+}
+
+// Sqrt returns the square root of x.
+func Sqrt(x *Expr) *Expr {
+	return unary("sqrt", math.Sqrt, x)
+}
+
+// Exp returns the exponential of x.
+func Exp(x *Expr) *Expr {
+	return unary("exp", math.Exp, x)
+}
+
+// Log returns the natural logarithm of x.
+func Log(x *Expr) *Expr {
+	return unary("log", math.Log, x)
+}
+
+// Sin returns the sine of x, in radians.
+func Sin(x *Expr) *Expr {
+	return unary("sin", math.Sin, x)
+}
+
+// Cos returns the cosine of x, in radians.
+func Cos(x *Expr) *Expr {
+	return unary("cos", math.Cos, x)
+}
+
+// Tanh returns the hyperbolic tangent of x.
+func Tanh(x *Expr) *Expr {
+	return unary("tanh", math.Tanh, x)
+}
+
+// Where returns a where cond is non-zero, and b elsewhere.
+func Where(cond, a, b *Expr) *Expr {
+	return &Expr{name: "where", fn3: func(c, x, y float64) float64 {
+		if c != 0 {
+			return x
+		}
+		return y
+	}, operands: []*Expr{cond, a, b}}
+}
+
+// String returns the expression in prefix notation, with arrays given by
+// their shapes.
+func (e *Expr) String() string {
+	switch {
+	case e.leaf != nil:
+		return fmt.Sprintf("array%v", []int(e.leaf.Shape))
+	case e.operands == nil:
+		return fmt.Sprint(e.value)
+	}
+	operands := make([]string, len(e.operands))
+	for i, op := range e.operands {
+		operands[i] = op.String()
+	}
+	return fmt.Sprintf("(%s %s)", e.name, strings.Join(operands, " "))
+}
+
+// compile returns a function computing an item of e from the items of
+// the arrays of the expression, whose indices in the arguments are
+// assigned by leaves.
+func (e *Expr) compile(leaves map[*array.Dense]int) func(args []float64) float64 {
+	switch {
+	case e.leaf != nil:
+		i, ok := leaves[e.leaf]
+		if !ok {
+			i = len(leaves)
+			leaves[e.leaf] = i
+		}
+		return func(args []float64) float64 { return args[i] }
+	case e.operands == nil:
+		v := e.value
+		return func([]float64) float64 { return v }
+	}
+	operands := make([]func([]float64) float64, len(e.operands))
+	for i, op := range e.operands {
+		operands[i] = op.compile(leaves)
+	}
+	switch {
+	case e.fn1 != nil:
+		fn, x := e.fn1, operands[0]
+		return func(args []float64) float64 { return fn(x(args)) }
+	case e.fn2 != nil:
+		fn, x, y := e.fn2, operands[0], operands[1]
+		return func(args []float64) float64 { return fn(x(args), y(args)) }
+	case e.fn3 != nil:
+		fn, x, y, z := e.fn3, operands[0], operands[1], operands[2]
+		return func(args []float64) float64 { return fn(x(args), y(args), z(args)) }
+	}
+	fn := e.fn
+	return func(args []float64) float64 {
+		values := make([]float64, len(operands))
+		for i, op := range operands {
+			values[i] = op(args)
+		}
+		return fn(values)
+	}
+}
+
+// Shape returns the shape of the value of e, the arrays of the
+// expression broadcast together.
+func (e *Expr) Shape() (array.Shape, error) {
+	var shapes []array.Shape
+	e.walk(func(d *array.Dense) {
+		shapes = append(shapes, d.Shape)
+	})
+	return array.BroadcastShapes(shapes...)
+}
+
+// walk calls fn for every array of the expression.
+func (e *Expr) walk(fn func(d *array.Dense)) {
+	if e.leaf != nil {
+		fn(e.leaf)
+	}
+	for _, op := range e.operands {
+		op.walk(fn)
+	}
+}
+
+// Eval returns a new array holding the value of e, computed in a single
+// pass over the items of its arrays broadcast together.  Arrays used
+// several times in the expression are read once per item.
+func Eval(e *Expr) (*array.Dense, error) {
+	leaves := make(map[*array.Dense]int)
+	fn := e.compile(leaves)
+	operands := make([]*array.Dense, len(leaves))
+	for d, i := range leaves {
+		operands[i] = d
+	}
+	return array.MapN(fn, operands...)
+}
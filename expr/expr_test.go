@@ -0,0 +1,102 @@
+package expr_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/expr"
+	"github.com/jimmyskull/math/internal/arraytest"
+)
+
+func TestEval(t *testing.T) {
+	a := arraytest.Dense(t, array.Shape{2, 2}, 1, 2, 3, 4)
+	b := arraytest.Dense(t, array.Shape{2, 2}, 5, 6, 7, 8)
+	c := arraytest.Dense(t, array.Shape{2}, 1, -1)
+	d := arraytest.Dense(t, array.Shape{2, 1}, 2, 3)
+	e := array.Scalar(0.5)
+
+	x := expr.Sub(
+		expr.Add(expr.Mul(expr.Array(a), expr.Array(b)), expr.Mul(expr.Array(c), expr.Array(d))),
+		expr.Array(e))
+	shape, err := x.Shape()
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, shape)
+	}
+	out, err := expr.Eval(x)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, out.Shape)
+		assert.Equal(t, []float64{6.5, 9.5, 23.5, 28.5}, out.Values())
+	}
+	assert.Equal(t, "(- (+ (* array[2 2] array[2 2]) (* array[2] array[2 1])) array[])", x.String())
+}
+
+func TestFunctions(t *testing.T) {
+	values := []float64{0.25, 1, 2.5, 4}
+	x := expr.Array(arraytest.Dense(t, array.Shape{4}, values...))
+	cases := []struct {
+		e  *expr.Expr
+		fn func(v float64) float64
+	}{
+		{expr.Neg(x), func(v float64) float64 { return -v }},
+		{expr.Abs(expr.Neg(x)), math.Abs},
+		{expr.Sqrt(x), math.Sqrt},
+		{expr.Exp(x), math.Exp},
+		{expr.Log(x), math.Log},
+		{expr.Sin(x), math.Sin},
+		{expr.Cos(x), math.Cos},
+		{expr.Tanh(x), math.Tanh},
+		{expr.Pow(x, expr.Const(2)), func(v float64) float64 { return v * v }},
+		{expr.Div(expr.Const(1), x), func(v float64) float64 { return 1 / v }},
+		{expr.Max(x, expr.Const(2)), func(v float64) float64 { return math.Max(v, 2) }},
+		{expr.Min(x, expr.Const(2)), func(v float64) float64 { return math.Min(v, 2) }},
+		{expr.Where(expr.Sub(x, expr.Const(1)), x, expr.Const(-1)), func(v float64) float64 {
+			if v == 1 {
+				return -1
+			}
+			return v
+		}},
+		{expr.Apply("hypot3", func(args []float64) float64 {
+			return math.Sqrt(args[0]*args[0] + args[1]*args[1] + args[2]*args[2])
+		}, x, x, x), func(v float64) float64 { return math.Sqrt(3) * v }},
+	}
+	for _, c := range cases {
+		out, err := expr.Eval(c.e)
+		if !assert.NoError(t, err, c.e.String()) {
+			continue
+		}
+		for i, v := range out.Values() {
+			assert.InDelta(t, c.fn(values[i]), v, 1e-12, c.e.String())
+		}
+	}
+}
+
+func TestEvalIsDeferred(t *testing.T) {
+	a := arraytest.Dense(t, array.Shape{3}, 1, 2, 3)
+	// The array is used twice, but read once per item.
+	x := expr.Mul(expr.Array(a), expr.Array(a))
+	assert.NoError(t, a.Set(array.Indices{0}, 10))
+	out, err := expr.Eval(x)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{100, 4, 9}, out.Values())
+	}
+
+	// Expressions without arrays are scalars.
+	out, err = expr.Eval(expr.Add(expr.Const(1), expr.Const(2)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{}, out.Shape)
+		assert.Equal(t, []float64{3}, out.Values())
+	}
+}
+
+func TestEvalBroadcastError(t *testing.T) {
+	x := expr.Add(
+		expr.Array(arraytest.Dense(t, array.Shape{2}, 1, 2)),
+		expr.Array(arraytest.Dense(t, array.Shape{3}, 1, 2, 3)))
+	_, err := x.Shape()
+	assert.Equal(t, array.ErrBroadcast, err)
+	_, err = expr.Eval(x)
+	assert.Equal(t, array.ErrBroadcast, err)
+}
@@ -0,0 +1,50 @@
+package imageio
+
+import (
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/ctxio"
+)
+
+// Load decodes a PNG or JPEG image into an H×W×C array, as FromImage
+// does.  If opts is nil, DefaultOptions is used.
+func Load(r io.Reader, opts *Options) (*array.Dense, error) {
+	return LoadContext(context.Background(), r, opts)
+}
+
+// LoadContext is like Load, but stops reading and returns an error
+// wrapping the error of ctx once it is done.
+func LoadContext(ctx context.Context, r io.Reader, opts *Options) (*array.Dense, error) {
+	m, _, err := image.Decode(ctxio.NewReader(ctx, r))
+	if err != nil {
+		return nil, ctxio.Canceled(ctx, "load image", err)
+	}
+	return FromImage(m, opts)
+}
+
+// SavePNG encodes an H×W or H×W×C array as a PNG image, converted as
+// ToImage does.  If opts is nil, DefaultOptions is used.
+func SavePNG(w io.Writer, d *array.Dense, opts *Options) error {
+	m, err := ToImage(d, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, m)
+}
+
+// SaveJPEG encodes an H×W or H×W×C array as a JPEG image of the given
+// quality, from 1 to 100, converted as ToImage does.  JPEG images have
+// 8 bits per channel and no transparency, so that the alpha channel is
+// dropped.  If opts is nil, DefaultOptions is used.
+func SaveJPEG(w io.Writer, d *array.Dense, quality int, opts *Options) error {
+	m, err := ToImage(d, opts)
+	if err != nil {
+		return err
+	}
+	return jpeg.Encode(w, m, &jpeg.Options{Quality: quality})
+}
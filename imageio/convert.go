@@ -0,0 +1,232 @@
+// Package imageio converts between images of the standard library and
+// arrays, and reads and writes PNG and JPEG files as arrays.
+//
+// An image of height H and width W is represented by an array of shape
+// H×W×C, where C is the number of channels: one for grey images, three
+// for colour images without transparency (red, green and blue), and four
+// for those with transparency, whose alpha channel is not
+// premultiplied.
+package imageio
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Options controls the conversion of items between arrays and images.
+type Options struct {
+	// Normalize maps intensities to [0, 1]: items read are divided by the
+	// maximum intensity of the image, and items written are multiplied
+	// by it.
+	Normalize bool
+	// Rescale stretches items written linearly from their minimum and
+	// maximum to the full range of intensities, instead of clamping them.
+	Rescale bool
+	// Depth is the number of bits per channel of images written, 8 or
+	// 16.  When zero, it is 8.
+	Depth int
+}
+
+// DefaultOptions keeps intensities in their integer range and writes
+// images of 8 bits per channel.
+var DefaultOptions = Options{
+	Depth: 8,
+}
+
+// FromImage returns the intensities of an image in an H×W×C array.
+// Gray and Gray16 images have one channel, YCbCr images are converted
+// to three RGB channels, and other images have four NRGBA channels,
+// unless they are opaque, in which case the alpha channel is dropped.
+// Intensities range up to 65535 for Gray16, RGBA64 and NRGBA64 images,
+// and up to 255 for the others.  If opts is nil, DefaultOptions is used.
+func FromImage(m image.Image, opts *Options) (*array.Dense, error) {
+	if opts == nil {
+		opts = &DefaultOptions
+	}
+	b := m.Bounds()
+	h, w := b.Dy(), b.Dx()
+	channels, max := 4, 255.0
+	var pixel func(x, y int, dst []float64)
+	switch m := m.(type) {
+	case *image.Gray:
+		channels = 1
+		pixel = func(x, y int, dst []float64) {
+			dst[0] = float64(m.Pix[m.PixOffset(x, y)])
+		}
+	case *image.Gray16:
+		channels, max = 1, 65535
+		pixel = func(x, y int, dst []float64) {
+			c := m.Gray16At(x, y)
+			dst[0] = float64(c.Y)
+		}
+	case *image.YCbCr:
+		channels = 3
+		pixel = func(x, y int, dst []float64) {
+			c := m.YCbCrAt(x, y)
+			r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+			dst[0], dst[1], dst[2] = float64(r), float64(g), float64(b)
+		}
+	case *image.NRGBA:
+		pixel = func(x, y int, dst []float64) {
+			p := m.Pix[m.PixOffset(x, y):]
+			for i := 0; i < 4; i++ {
+				dst[i] = float64(p[i])
+			}
+		}
+	case *image.NRGBA64:
+		max = 65535
+		pixel = func(x, y int, dst []float64) {
+			c := m.NRGBA64At(x, y)
+			dst[0], dst[1], dst[2], dst[3] =
+				float64(c.R), float64(c.G), float64(c.B), float64(c.A)
+		}
+	case *image.RGBA64:
+		max = 65535
+		pixel = func(x, y int, dst []float64) {
+			c := color.NRGBA64Model.Convert(m.RGBA64At(x, y)).(color.NRGBA64)
+			dst[0], dst[1], dst[2], dst[3] =
+				float64(c.R), float64(c.G), float64(c.B), float64(c.A)
+		}
+	default:
+		pixel = func(x, y int, dst []float64) {
+			c := color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)
+			dst[0], dst[1], dst[2], dst[3] =
+				float64(c.R), float64(c.G), float64(c.B), float64(c.A)
+		}
+	}
+	if o, ok := m.(interface{ Opaque() bool }); ok && channels == 4 && o.Opaque() {
+		// The alpha channel is dropped.
+		channels = 3
+	}
+	values := make([]float64, h*w*channels)
+	var buf [4]float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pixel(b.Min.X+x, b.Min.Y+y, buf[:])
+			copy(values[(y*w+x)*channels:], buf[:channels])
+		}
+	}
+	if opts.Normalize {
+		for i := range values {
+			values[i] /= max
+		}
+	}
+	return array.NewDenseWithValues(array.Shape{h, w, channels}, values,
+		array.DefaultAttributes)
+}
+
+// ToImage returns an image with the intensities of an H×W or H×W×C
+// array.  Arrays with one channel give Gray or Gray16 images, those with
+// three channels opaque RGBA or RGBA64 images, and those with four
+// channels NRGBA or NRGBA64 images, as selected by the depth of the
+// options.  Intensities are rounded and clamped to the range of the
+// depth, NaN giving zero.  If opts is nil, DefaultOptions is used.
+func ToImage(d *array.Dense, opts *Options) (image.Image, error) {
+	if opts == nil {
+		opts = &DefaultOptions
+	}
+	var h, w, channels int
+	switch len(d.Shape) {
+	case 2:
+		h, w, channels = d.Shape[0], d.Shape[1], 1
+	case 3:
+		h, w, channels = d.Shape[0], d.Shape[1], d.Shape[2]
+	}
+	if channels != 1 && channels != 3 && channels != 4 {
+		return nil, ErrInvalidShape
+	}
+	var max float64
+	switch opts.Depth {
+	case 0, 8:
+		max = 255
+	case 16:
+		max = 65535
+	default:
+		return nil, ErrInvalidDepth
+	}
+	values := d.Values()
+	scale, offset := 1.0, 0.0
+	switch {
+	case opts.Rescale:
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, v := range values {
+			if !math.IsInf(v, 0) && !math.IsNaN(v) {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+		if hi > lo {
+			scale, offset = max/(hi-lo), -lo*max/(hi-lo)
+		} else {
+			scale = 0
+		}
+	case opts.Normalize:
+		scale = max
+	}
+	for i, v := range values {
+		v = math.Round(v*scale + offset)
+		switch {
+		case math.IsNaN(v) || v < 0:
+			v = 0
+		case v > max:
+			v = max
+		}
+		values[i] = v
+	}
+
+	r := image.Rect(0, 0, w, h)
+	switch {
+	case channels == 1 && max == 255:
+		m := image.NewGray(r)
+		for i, v := range values {
+			m.Pix[i] = uint8(v)
+		}
+		return m, nil
+	case channels == 1:
+		m := image.NewGray16(r)
+		for i, v := range values {
+			m.Pix[2*i], m.Pix[2*i+1] = uint8(uint16(v)>>8), uint8(v)
+		}
+		return m, nil
+	case max == 255:
+		var m image.Image
+		var pix []uint8
+		if channels == 3 {
+			rgba := image.NewRGBA(r)
+			m, pix = rgba, rgba.Pix
+		} else {
+			nrgba := image.NewNRGBA(r)
+			m, pix = nrgba, nrgba.Pix
+		}
+		for p := 0; p < h*w; p++ {
+			for c := 0; c < channels; c++ {
+				pix[4*p+c] = uint8(values[p*channels+c])
+			}
+			if channels == 3 {
+				pix[4*p+3] = 0xff
+			}
+		}
+		return m, nil
+	}
+	var m image.Image
+	var pix []uint8
+	if channels == 3 {
+		rgba := image.NewRGBA64(r)
+		m, pix = rgba, rgba.Pix
+	} else {
+		nrgba := image.NewNRGBA64(r)
+		m, pix = nrgba, nrgba.Pix
+	}
+	for p := 0; p < h*w; p++ {
+		for c := 0; c < 4; c++ {
+			v := uint16(0xffff)
+			if c < channels {
+				v = uint16(values[p*channels+c])
+			}
+			pix[8*p+2*c], pix[8*p+2*c+1] = uint8(v>>8), uint8(v)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,14 @@
+package imageio
+
+import "errors"
+
+var (
+	// ErrInvalidShape is returned when an array is not a H×W matrix or a
+	// H×W×C array with one, three or four channels.
+	ErrInvalidShape = errors.New(
+		"array must be H×W or H×W×C with 1, 3 or 4 channels")
+
+	// ErrInvalidDepth is returned when the depth of an image is neither
+	// 8 nor 16 bits.
+	ErrInvalidDepth = errors.New("depth must be 8 or 16 bits")
+)
@@ -0,0 +1,144 @@
+package imageio_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/imageio"
+)
+
+func TestFromImage(t *testing.T) {
+	gray := image.NewGray(image.Rect(1, 1, 4, 3))
+	gray.SetGray(3, 2, color.Gray{Y: 51})
+	d, err := imageio.FromImage(gray, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 3, 1}, d.Shape)
+		assert.Equal(t, []float64{0, 0, 0, 0, 0, 51}, d.Values())
+	}
+
+	g16 := image.NewGray16(image.Rect(0, 0, 1, 1))
+	g16.SetGray16(0, 0, color.Gray16{Y: 65535})
+	d, err = imageio.FromImage(g16, &imageio.Options{Normalize: true})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1}, d.Values())
+	}
+
+	// Premultiplied alpha is undone.
+	rgba := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	rgba.SetRGBA(0, 0, color.RGBA{R: 100, A: 200})
+	d, err = imageio.FromImage(rgba, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{127, 0, 0, 200}, d.Values())
+	}
+
+	ycbcr := image.NewYCbCr(image.Rect(0, 0, 2, 2), image.YCbCrSubsampleRatio420)
+	for i := range ycbcr.Y {
+		ycbcr.Y[i] = 255
+	}
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i], ycbcr.Cr[i] = 128, 128
+	}
+	d, err = imageio.FromImage(ycbcr, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 2, 3}, d.Shape)
+		assert.Equal(t, 255.0, d.Values()[11])
+	}
+}
+
+func TestToImage(t *testing.T) {
+	d, _ := array.NewDenseWithValues(array.Shape{1, 3},
+		[]float64{-5, 0.4, math.NaN()}, array.DefaultAttributes)
+	m, err := imageio.ToImage(d, &imageio.Options{Normalize: true, Depth: 8})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []uint8{0, 102, 0}, m.(*image.Gray).Pix)
+	}
+	// A zero depth is 8 bits.
+	m, err = imageio.ToImage(d, &imageio.Options{Normalize: true})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []uint8{0, 102, 0}, m.(*image.Gray).Pix)
+	}
+	m, err = imageio.ToImage(d, &imageio.Options{Rescale: true, Depth: 16})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []uint8{0, 0, 0xff, 0xff, 0, 0}, m.(*image.Gray16).Pix)
+	}
+
+	rgb, _ := array.NewDenseWithValues(array.Shape{1, 1, 3},
+		[]float64{300, 20, 30}, array.DefaultAttributes)
+	m, err = imageio.ToImage(rgb, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, color.RGBA{R: 255, G: 20, B: 30, A: 255}, m.At(0, 0))
+	}
+
+	bad, _ := array.NewDense(array.Shape{2, 2, 2}, array.DefaultAttributes)
+	_, err = imageio.ToImage(bad, nil)
+	assert.Equal(t, imageio.ErrInvalidShape, err)
+	_, err = imageio.ToImage(rgb, &imageio.Options{Depth: 12})
+	assert.Equal(t, imageio.ErrInvalidDepth, err)
+}
+
+func TestPNGRoundTrip(t *testing.T) {
+	for _, depth := range []int{8, 16} {
+		opts := &imageio.Options{Normalize: true, Depth: depth}
+		d, _ := array.NewDenseWithValues(array.Shape{2, 1, 4},
+			[]float64{0, 0.2, 0.4, 1, 1, 0.6, 0.8, 0.2}, array.DefaultAttributes)
+		var buf bytes.Buffer
+		if !assert.Nil(t, imageio.SavePNG(&buf, d, opts)) {
+			continue
+		}
+		back, err := imageio.Load(&buf, opts)
+		if assert.Nil(t, err) {
+			assert.Equal(t, d.Shape, back.Shape)
+			assert.InDeltaSlice(t, d.Values(), back.Values(), 0.5/255)
+		}
+	}
+}
+
+func TestJPEGRoundTrip(t *testing.T) {
+	d, _ := array.NewDense(array.Shape{8, 8, 3}, array.DefaultAttributes)
+	d.Fill(128, 0)
+	var buf bytes.Buffer
+	if !assert.Nil(t, imageio.SaveJPEG(&buf, d, 90, nil)) {
+		return
+	}
+	back, err := imageio.Load(&buf, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, d.Shape, back.Shape)
+		assert.InDeltaSlice(t, d.Values(), back.Values(), 2)
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	d, _ := array.NewDense(array.Shape{4, 4}, array.DefaultAttributes)
+	var buf bytes.Buffer
+	if !assert.Nil(t, imageio.SavePNG(&buf, d, nil)) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := imageio.LoadContext(ctx, &buf, nil)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestOpaqueImages(t *testing.T) {
+	// Opaque colour images keep three channels through PNG files.
+	d, _ := array.NewDenseWithValues(array.Shape{1, 2, 3},
+		[]float64{1, 2, 3, 4, 5, 6000}, array.DefaultAttributes)
+	opts := &imageio.Options{Depth: 16}
+	var buf bytes.Buffer
+	if !assert.Nil(t, imageio.SavePNG(&buf, d, opts)) {
+		return
+	}
+	back, err := imageio.Load(&buf, opts)
+	if assert.Nil(t, err) {
+		assert.Equal(t, d.Shape, back.Shape)
+		assert.Equal(t, d.Values(), back.Values())
+	}
+}
@@ -0,0 +1,26 @@
+// Package arraytest implements helpers shared by the tests of the
+// packages built on arrays.
+package arraytest
+
+import (
+	"testing"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Dense returns a new array with a given shape whose items are taken
+// from values in row-major order, failing the test if they do not fit.
+func Dense(t testing.TB, shape array.Shape, values ...float64) *array.Dense {
+	t.Helper()
+	d, err := array.NewDenseWithValues(shape, values, array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// Vector returns a new one-dimensional array holding values.
+func Vector(t testing.TB, values ...float64) *array.Dense {
+	t.Helper()
+	return Dense(t, array.Shape{len(values)}, values...)
+}
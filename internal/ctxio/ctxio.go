@@ -0,0 +1,37 @@
+// Package ctxio implements readers that stop once a context is done,
+// shared by the packages decoding files into arrays.
+package ctxio
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// NewReader returns a reader of r whose reads fail with the error of
+// ctx once it is done.
+func NewReader(ctx context.Context, r io.Reader) io.Reader {
+	return &reader{ctx: ctx, r: r}
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// Canceled returns an error of the operation wrapping the error of ctx
+// once it is done, and err otherwise.  Decoders may report a failed
+// read as malformed data, so the context is checked whatever err is.
+func Canceled(ctx context.Context, operation string, err error) error {
+	if cerr := ctx.Err(); cerr != nil {
+		return fmt.Errorf("%s: operation canceled: %w", operation, cerr)
+	}
+	return err
+}
@@ -0,0 +1,216 @@
+// Package flatbuf encodes and decodes the subset of the FlatBuffers
+// binary format used by the metadata of Apache Arrow messages.
+//
+// Objects are described as values and encoded front to back: a table
+// precedes the objects it refers to, so that every offset is positive
+// as the format requires.
+package flatbuf
+
+import "encoding/binary"
+
+// Value is the content of a field of a table.
+type Value interface {
+	// size and align give the inline size and alignment of the field.
+	size() int
+	align() int
+}
+
+// Table is a table whose fields are indexed by slot.  Nil fields are
+// absent.
+type Table []Value
+
+// scalar is a little-endian number stored inline.
+type scalar []byte
+
+func (s scalar) size() int  { return len(s) }
+func (s scalar) align() int { return len(s) }
+
+// Uint8 returns a one-byte scalar, also used for union types.
+func Uint8(v uint8) Value { return scalar{v} }
+
+// Bool returns a boolean scalar.
+func Bool(v bool) Value {
+	if v {
+		return scalar{1}
+	}
+	return scalar{0}
+}
+
+// Int16 returns a two-byte scalar, also used for enumerations.
+func Int16(v int16) Value {
+	b := make(scalar, 2)
+	binary.LittleEndian.PutUint16(b, uint16(v))
+	return b
+}
+
+// Int32 returns a four-byte scalar.
+func Int32(v int32) Value {
+	b := make(scalar, 4)
+	binary.LittleEndian.PutUint32(b, uint32(v))
+	return b
+}
+
+// Int64 returns an eight-byte scalar.
+func Int64(v int64) Value {
+	b := make(scalar, 8)
+	binary.LittleEndian.PutUint64(b, uint64(v))
+	return b
+}
+
+// Struct is a struct stored inline, aligned to eight bytes, as all the
+// structs of Arrow are.
+type Struct []byte
+
+func (s Struct) size() int  { return len(s) }
+func (s Struct) align() int { return 8 }
+
+// Int64s returns the encoding of a struct, or of a vector of structs,
+// made of the given eight-byte fields.
+func Int64s(values ...int64) Struct {
+	b := make(Struct, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(b[8*i:], uint64(v))
+	}
+	return b
+}
+
+// reference is a value stored out of line and referred to by an
+// offset.
+type reference interface {
+	Value
+	write(b *builder) int
+}
+
+func (Table) size() int  { return 4 }
+func (Table) align() int { return 4 }
+
+// String is a string referred to by an offset.
+type String string
+
+func (String) size() int  { return 4 }
+func (String) align() int { return 4 }
+
+// Tables is a vector of tables.
+type Tables []Table
+
+func (Tables) size() int  { return 4 }
+func (Tables) align() int { return 4 }
+
+// Structs is a vector of Count structs of eight-byte aligned fields,
+// holding the bytes of Data.  It also encodes vectors of int64.
+type Structs struct {
+	Count int
+	Data  Struct
+}
+
+func (Structs) size() int  { return 4 }
+func (Structs) align() int { return 4 }
+
+type builder struct {
+	buf []byte
+}
+
+func (b *builder) pad(align int) {
+	for len(b.buf)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// padBefore pads the buffer so that an object with a prefix of the
+// given length is followed by a position aligned to align.
+func (b *builder) padBefore(prefix, align int) {
+	for (len(b.buf)+prefix)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *builder) patch(at, target int) {
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(target-at))
+}
+
+func (b *builder) reserve(n int) int {
+	at := len(b.buf)
+	b.buf = append(b.buf, make([]byte, n)...)
+	return at
+}
+
+func (t Table) write(b *builder) int {
+	// Inline layout, relative to the start of the table.
+	offsets := make([]int, len(t))
+	end := 4
+	for slot, v := range t {
+		if v == nil {
+			continue
+		}
+		for end%v.align() != 0 {
+			end++
+		}
+		offsets[slot] = end
+		end += v.size()
+	}
+	b.pad(2)
+	vtable := b.reserve(4 + 2*len(t))
+	binary.LittleEndian.PutUint16(b.buf[vtable:], uint16(4+2*len(t)))
+	binary.LittleEndian.PutUint16(b.buf[vtable+2:], uint16(end))
+	for slot, offset := range offsets {
+		binary.LittleEndian.PutUint16(b.buf[vtable+4+2*slot:], uint16(offset))
+	}
+	b.pad(8)
+	table := b.reserve(end)
+	binary.LittleEndian.PutUint32(b.buf[table:], uint32(table-vtable))
+	var refs []int
+	for slot, v := range t {
+		if v == nil {
+			continue
+		}
+		switch v := v.(type) {
+		case scalar:
+			copy(b.buf[table+offsets[slot]:], v)
+		case Struct:
+			copy(b.buf[table+offsets[slot]:], v)
+		default:
+			refs = append(refs, slot)
+		}
+	}
+	for _, slot := range refs {
+		at := table + offsets[slot]
+		b.patch(at, t[slot].(reference).write(b))
+	}
+	return table
+}
+
+func (s String) write(b *builder) int {
+	b.pad(4)
+	at := b.reserve(4)
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return at
+}
+
+func (v Tables) write(b *builder) int {
+	b.pad(4)
+	at := b.reserve(4 + 4*len(v))
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(len(v)))
+	for i, t := range v {
+		b.patch(at+4+4*i, t.write(b))
+	}
+	return at
+}
+
+func (v Structs) write(b *builder) int {
+	b.padBefore(4, 8)
+	at := b.reserve(4)
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(v.Count))
+	b.buf = append(b.buf, v.Data...)
+	return at
+}
+
+// Encode returns the encoding of a buffer whose root is t, padded to a
+// multiple of eight bytes.
+func Encode(t Table) []byte {
+	b := &builder{buf: make([]byte, 4, 256)}
+	b.patch(0, t.write(b))
+	b.pad(8)
+	return b.buf
+}
@@ -0,0 +1,201 @@
+package flatbuf
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrMalformed is returned when decoding a buffer that is not a valid
+// encoding.
+var ErrMalformed = errors.New("malformed flatbuffer")
+
+// malformed is the panic value of reads outside the buffer, turned
+// into ErrMalformed by Catch.
+type malformed struct{}
+
+// Catch recovers from the panic raised by the methods of Ref on a
+// malformed buffer, setting *err to ErrMalformed.  It must be deferred.
+func Catch(err *error) {
+	if r := recover(); r != nil {
+		if _, ok := r.(malformed); !ok {
+			panic(r)
+		}
+		*err = ErrMalformed
+	}
+}
+
+// Ref is a reference to a table in a buffer.  Its methods panic on
+// malformed buffers; see Catch.
+type Ref struct {
+	buf []byte
+	pos int
+}
+
+func check(buf []byte, pos, n int) {
+	if pos < 0 || n < 0 || pos > len(buf)-n {
+		panic(malformed{})
+	}
+}
+
+func uint16At(buf []byte, pos int) int {
+	check(buf, pos, 2)
+	return int(binary.LittleEndian.Uint16(buf[pos:]))
+}
+
+func uint32At(buf []byte, pos int) int {
+	check(buf, pos, 4)
+	return int(binary.LittleEndian.Uint32(buf[pos:]))
+}
+
+// indirect returns the position referred to by the offset at pos.
+func indirect(buf []byte, pos int) int {
+	return pos + uint32At(buf, pos)
+}
+
+// Root returns the root table of buf.
+func Root(buf []byte) Ref {
+	return Ref{buf, indirect(buf, 0)}
+}
+
+// field returns the position of the field in slot, or zero if it is
+// absent.
+func (r Ref) field(slot int) int {
+	check(r.buf, r.pos, 4)
+	vtable := r.pos - int(int32(binary.LittleEndian.Uint32(r.buf[r.pos:])))
+	size := uint16At(r.buf, vtable)
+	if 4+2*slot >= size {
+		return 0
+	}
+	offset := uint16At(r.buf, vtable+4+2*slot)
+	if offset == 0 {
+		return 0
+	}
+	return r.pos + offset
+}
+
+// Has returns whether the field in slot is present.
+func (r Ref) Has(slot int) bool {
+	return r.field(slot) != 0
+}
+
+// Uint8 returns the one-byte field in slot, or def if it is absent.
+func (r Ref) Uint8(slot int, def uint8) uint8 {
+	pos := r.field(slot)
+	if pos == 0 {
+		return def
+	}
+	check(r.buf, pos, 1)
+	return r.buf[pos]
+}
+
+// Bool returns the boolean field in slot, or def if it is absent.
+func (r Ref) Bool(slot int, def bool) bool {
+	if !r.Has(slot) {
+		return def
+	}
+	return r.Uint8(slot, 0) != 0
+}
+
+// Int16 returns the two-byte field in slot, or def if it is absent.
+func (r Ref) Int16(slot int, def int16) int16 {
+	pos := r.field(slot)
+	if pos == 0 {
+		return def
+	}
+	return int16(uint16At(r.buf, pos))
+}
+
+// Int32 returns the four-byte field in slot, or def if it is absent.
+func (r Ref) Int32(slot int, def int32) int32 {
+	pos := r.field(slot)
+	if pos == 0 {
+		return def
+	}
+	return int32(uint32At(r.buf, pos))
+}
+
+// Int64 returns the eight-byte field in slot, or def if it is absent.
+func (r Ref) Int64(slot int, def int64) int64 {
+	pos := r.field(slot)
+	if pos == 0 {
+		return def
+	}
+	check(r.buf, pos, 8)
+	return int64(binary.LittleEndian.Uint64(r.buf[pos:]))
+}
+
+// Table returns the table referred to by the field in slot, and
+// whether it is present.
+func (r Ref) Table(slot int) (Ref, bool) {
+	pos := r.field(slot)
+	if pos == 0 {
+		return Ref{}, false
+	}
+	return Ref{r.buf, indirect(r.buf, pos)}, true
+}
+
+// String returns the string referred to by the field in slot, or the
+// empty string if it is absent.
+func (r Ref) String(slot int) string {
+	pos := r.field(slot)
+	if pos == 0 {
+		return ""
+	}
+	at := indirect(r.buf, pos)
+	n := uint32At(r.buf, at)
+	check(r.buf, at+4, n)
+	return string(r.buf[at+4 : at+4+n])
+}
+
+// vector returns the position of the first element and the length of
+// the vector referred to by the field in slot.
+func (r Ref) vector(slot int) (int, int) {
+	pos := r.field(slot)
+	if pos == 0 {
+		return 0, 0
+	}
+	at := indirect(r.buf, pos)
+	return at + 4, uint32At(r.buf, at)
+}
+
+// Tables returns the tables of the vector referred to by the field in
+// slot.
+func (r Ref) Tables(slot int) []Ref {
+	at, n := r.vector(slot)
+	check(r.buf, at, 4*n)
+	tables := make([]Ref, n)
+	for i := range tables {
+		tables[i] = Ref{r.buf, indirect(r.buf, at+4*i)}
+	}
+	return tables
+}
+
+// Int64s returns the eight-byte fields of the vector of structs, or of
+// int64, referred to by the field in slot, where every struct is made
+// of the given number of eight-byte fields.
+func (r Ref) Int64s(slot, fields int) []int64 {
+	at, n := r.vector(slot)
+	return int64s(r.buf, at, n*fields)
+}
+
+// Struct returns the eight-byte fields of the inline struct in slot,
+// or nil if it is absent.
+func (r Ref) Struct(slot, fields int) []int64 {
+	pos := r.field(slot)
+	if pos == 0 {
+		return nil
+	}
+	return int64s(r.buf, pos, fields)
+}
+
+func int64s(buf []byte, at, n int) []int64 {
+	if n > len(buf)/8 {
+		panic(malformed{})
+	}
+	check(buf, at, 8*n)
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = int64(binary.LittleEndian.Uint64(buf[at+8*i:]))
+	}
+	return values
+}
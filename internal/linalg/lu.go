@@ -0,0 +1,104 @@
+// Package linalg implements the small dense linear algebra kernels
+// shared by the numerical packages.  Matrices are float64 slices in
+// row-major order.
+package linalg
+
+import (
+	"context"
+	"math"
+
+	"github.com/jimmyskull/math/internal/ctxio"
+)
+
+// LU is the LU factorization with partial pivoting of a square matrix.
+type LU struct {
+	n     int
+	lu    []float64
+	pivot []int
+}
+
+// Factorize computes the LU factorization of the n-by-n matrix a,
+// which is left unmodified.  It returns false when a is singular.
+func Factorize(a []float64, n int) (*LU, bool) {
+	f, ok, _ := FactorizeContext(context.Background(), a, n)
+	return f, ok
+}
+
+// FactorizeContext is like Factorize, but stops early and returns an
+// error wrapping the error of ctx once it is done.  The context is
+// checked before the elimination of every column.
+func FactorizeContext(ctx context.Context, a []float64, n int) (*LU, bool, error) {
+	lu := make([]float64, n*n)
+	copy(lu, a)
+	pivot := make([]int, n)
+	for k := 0; k < n; k++ {
+		if ctx.Err() != nil {
+			return nil, false, ctxio.Canceled(ctx, "factorize", nil)
+		}
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i*n+k]) > math.Abs(lu[p*n+k]) {
+				p = i
+			}
+		}
+		pivot[k] = p
+		if lu[p*n+k] == 0 {
+			return nil, false, nil
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				lu[k*n+j], lu[p*n+j] = lu[p*n+j], lu[k*n+j]
+			}
+		}
+		for i := k + 1; i < n; i++ {
+			lu[i*n+k] /= lu[k*n+k]
+			l := lu[i*n+k]
+			if l == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				lu[i*n+j] -= l * lu[k*n+j]
+			}
+		}
+	}
+	return &LU{n: n, lu: lu, pivot: pivot}, true, nil
+}
+
+// Solve overwrites b with the solution x of A x = b.
+func (f *LU) Solve(b []float64) {
+	n := f.n
+	for k := 0; k < n; k++ {
+		if p := f.pivot[k]; p != k {
+			b[k], b[p] = b[p], b[k]
+		}
+	}
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			b[i] -= f.lu[i*n+j] * b[j]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			b[i] -= f.lu[i*n+j] * b[j]
+		}
+		b[i] /= f.lu[i*n+i]
+	}
+}
+
+// Inverse returns the inverse of the factorized matrix.
+func (f *LU) Inverse() []float64 {
+	n := f.n
+	inv := make([]float64, n*n)
+	col := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := range col {
+			col[i] = 0
+		}
+		col[j] = 1
+		f.Solve(col)
+		for i := 0; i < n; i++ {
+			inv[i*n+j] = col[i]
+		}
+	}
+	return inv
+}
@@ -0,0 +1,54 @@
+package linalg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/internal/linalg"
+)
+
+func TestLU(t *testing.T) {
+	a := []float64{
+		0, 2, 1,
+		1, 1, 0,
+		3, 0, 1,
+	}
+	lu, ok := linalg.Factorize(a, 3)
+	if assert.True(t, ok) {
+		b := []float64{5, 3, 4}
+		lu.Solve(b)
+		assert.InDeltaSlice(t, []float64{1, 2, 1}, b, 1e-12)
+		inv := lu.Inverse()
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				sum := 0.0
+				for k := 0; k < 3; k++ {
+					sum += a[i*3+k] * inv[k*3+j]
+				}
+				expected := 0.0
+				if i == j {
+					expected = 1
+				}
+				assert.InDelta(t, expected, sum, 1e-12)
+			}
+		}
+	}
+	_, ok = linalg.Factorize([]float64{1, 2, 2, 4}, 2)
+	assert.False(t, ok)
+}
+
+func TestFactorizeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lu, ok, err := linalg.FactorizeContext(ctx, []float64{2, 1, 1, 3}, 2)
+	if assert.NoError(t, err) && assert.True(t, ok) {
+		b := []float64{3, 4}
+		lu.Solve(b)
+		assert.InDeltaSlice(t, []float64{1, 1}, b, 1e-12)
+	}
+	cancel()
+	_, ok, err = linalg.FactorizeContext(ctx, []float64{2, 1, 1, 3}, 2)
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,506 @@
+package specfun
+
+import "math"
+
+// Coefficients of the power series of 1/Γ(1+z) about zero, from
+// Abramowitz and Stegun, equation 6.1.34.
+var reciprocalGamma = [...]float64{
+	1.0,
+	0.5772156649015329,
+	-0.6558780715202538,
+	-0.0420026350340952,
+	0.1665386113822915,
+	-0.0421977345555443,
+	-0.0096219715278770,
+	0.0072189432466630,
+	-0.0011651675918591,
+	-0.0002152416741149,
+	0.0001280502823882,
+	-0.0000201348547807,
+	-0.0000012504934821,
+	0.0000011330272320,
+	-0.0000002056338417,
+	0.0000000061160950,
+	0.0000000050020075,
+	-0.0000000011812746,
+	0.0000000001043427,
+	0.0000000000077823,
+	-0.0000000000036968,
+	0.0000000000005100,
+	-0.0000000000000206,
+	-0.0000000000000054,
+	0.0000000000000014,
+	0.0000000000000001,
+}
+
+// temme returns the quantities used by Temme's series for |mu| <= 1/2:
+// gam1 = (1/Γ(1-mu) - 1/Γ(1+mu))/(2 mu), gam2 = (1/Γ(1-mu) +
+// 1/Γ(1+mu))/2, gampl = 1/Γ(1+mu) and gammi = 1/Γ(1-mu).
+func temme(mu float64) (gam1, gam2, gampl, gammi float64) {
+	power := 1.0
+	for k, c := range reciprocalGamma {
+		if k%2 == 0 {
+			gam2 += c * power
+		} else {
+			gam1 -= c * power / mu
+		}
+		power *= mu
+	}
+	// The odd terms were divided by mu after multiplying by mu^k,
+	// which is exact except at zero.
+	if mu == 0 {
+		gam1 = -reciprocalGamma[1]
+	}
+	return gam1, gam2, gam2 - mu*gam1, gam2 + mu*gam1
+}
+
+// besselJY returns J_nu(x) and Y_nu(x) for x > 0 and nu >= 0 using
+// Steed's method and Temme's series, as described in Press et al.,
+// "Numerical Recipes", section 6.7.
+func besselJY(nu, x float64) (float64, float64) {
+	const xmin = 2.0
+	if x > 1e4 && x > nu*nu {
+		return besselJYAsymptotic(nu, x)
+	}
+	var nl int
+	if x < xmin {
+		nl = int(nu + 0.5)
+	} else {
+		nl = int(math.Max(0, nu-x+1.5))
+	}
+	mu := nu - float64(nl)
+	mu2 := mu * mu
+	xi := 1 / x
+	xi2 := 2 * xi
+	w := xi2 / math.Pi
+	// Continued fraction CF1 for J'_nu/J_nu.
+	sign := 1.0
+	h := math.Max(nu*xi, fpmin)
+	b := xi2 * nu
+	d := 0.0
+	c := h
+	for i := 1; i <= 10*maxIter; i++ {
+		b += xi2
+		d = b - d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = b - 1/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		del := c * d
+		h *= del
+		if d < 0 {
+			sign = -sign
+		}
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	// Downward recurrence to mu.
+	jl := sign * fpmin
+	jpl := h * jl
+	jl1 := jl
+	fact := nu * xi
+	for l := nl; l >= 1; l-- {
+		jtemp := fact*jl + jpl
+		fact -= xi
+		jpl = fact*jtemp - jl
+		jl = jtemp
+	}
+	if jl == 0 {
+		jl = epsilon
+	}
+	f := jpl / jl
+	var jmu, ymu, y1 float64
+	if x < xmin {
+		x2 := x / 2
+		pimu := math.Pi * mu
+		fact := 1.0
+		if math.Abs(pimu) >= epsilon {
+			fact = pimu / math.Sin(pimu)
+		}
+		d := -math.Log(x2)
+		e := mu * d
+		fact2 := 1.0
+		if math.Abs(e) >= epsilon {
+			fact2 = math.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := temme(mu)
+		ff := 2 / math.Pi * fact * (gam1*math.Cosh(e) + gam2*fact2*d)
+		e = math.Exp(e)
+		p := e / (gampl * math.Pi)
+		q := 1 / (e * math.Pi * gammi)
+		pimu2 := pimu / 2
+		fact3 := 1.0
+		if math.Abs(pimu2) >= epsilon {
+			fact3 = math.Sin(pimu2) / pimu2
+		}
+		r := math.Pi * pimu2 * fact3 * fact3
+		c := 1.0
+		d = -x2 * x2
+		sum := ff + r*q
+		sum1 := p
+		for i := 1; i <= maxIter; i++ {
+			fi := float64(i)
+			ff = (fi*ff + p + q) / (fi*fi - mu2)
+			c *= d / fi
+			p /= fi - mu
+			q /= fi + mu
+			del := c * (ff + r*q)
+			sum += del
+			sum1 += c*p - fi*del
+			if math.Abs(del) < (1+math.Abs(sum))*epsilon {
+				break
+			}
+		}
+		ymu = -sum
+		y1 = -sum1 * xi2
+		ymup := mu*xi*ymu - y1
+		jmu = w / (ymup - f*ymu)
+	} else {
+		// Complex continued fraction CF2 for p + iq.
+		a := 0.25 - mu2
+		p := -0.5 * xi
+		q := 1.0
+		br := 2 * x
+		bi := 2.0
+		fact := a * xi / (p*p + q*q)
+		cr := br + q*fact
+		ci := bi + p*fact
+		den := br*br + bi*bi
+		dr := br / den
+		di := -bi / den
+		dlr := cr*dr - ci*di
+		dli := cr*di + ci*dr
+		p, q = p*dlr-q*dli, p*dli+q*dlr
+		for i := 2; i <= maxIter; i++ {
+			a += float64(2 * (i - 1))
+			bi += 2
+			dr = a*dr + br
+			di = a*di + bi
+			if math.Abs(dr)+math.Abs(di) < fpmin {
+				dr = fpmin
+			}
+			fact = a / (cr*cr + ci*ci)
+			cr = br + cr*fact
+			ci = bi - ci*fact
+			if math.Abs(cr)+math.Abs(ci) < fpmin {
+				cr = fpmin
+			}
+			den = dr*dr + di*di
+			dr /= den
+			di /= -den
+			dlr = cr*dr - ci*di
+			dli = cr*di + ci*dr
+			p, q = p*dlr-q*dli, p*dli+q*dlr
+			if math.Abs(dlr-1)+math.Abs(dli) < epsilon {
+				break
+			}
+		}
+		gam := (p - f) / q
+		jmu = math.Copysign(math.Sqrt(w/((p-f)*gam+q)), jl)
+		ymu = jmu * gam
+		ymup := ymu * (p + q/gam)
+		y1 = mu*xi*ymu - ymup
+	}
+	j := jl1 * jmu / jl
+	// Upward recurrence of Y to nu.
+	for i := 1; i <= nl; i++ {
+		ytemp := (mu+float64(i))*xi2*y1 - ymu
+		ymu = y1
+		y1 = ytemp
+	}
+	return j, ymu
+}
+
+// besselJYAsymptotic evaluates Hankel's asymptotic expansion of J and
+// Y for large arguments.
+func besselJYAsymptotic(nu, x float64) (float64, float64) {
+	mu := 4 * nu * nu
+	p, q := hankelPQ(mu, x)
+	omega := x - (nu/2+0.25)*math.Pi
+	s, c := math.Sincos(omega)
+	scale := math.Sqrt(2 / (math.Pi * x))
+	return scale * (p*c - q*s), scale * (p*s + q*c)
+}
+
+func hankelPQ(mu, x float64) (float64, float64) {
+	p, q := 1.0, 0.0
+	term := 1.0
+	for k := 1; k < 30; k++ {
+		odd := float64(2*k - 1)
+		term *= (mu - odd*odd) / (float64(k) * 8 * x)
+		if k%2 == 1 {
+			if k%4 == 3 {
+				q -= term
+			} else {
+				q += term
+			}
+		} else {
+			if k%4 == 2 {
+				p -= term
+			} else {
+				p += term
+			}
+		}
+		if math.Abs(term) < epsilon {
+			break
+		}
+	}
+	return p, q
+}
+
+// besselIK returns I_nu(x) and K_nu(x) for x > 0 and nu >= 0 using
+// Steed's method and Temme's series, as described in Press et al.,
+// "Numerical Recipes", section 6.7.
+func besselIK(nu, x float64) (float64, float64) {
+	const xmin = 2.0
+	if x > 700 && x > nu*nu {
+		return besselIKAsymptotic(nu, x)
+	}
+	nl := int(nu + 0.5)
+	mu := nu - float64(nl)
+	mu2 := mu * mu
+	xi := 1 / x
+	xi2 := 2 * xi
+	// Continued fraction CF1 for I'_nu/I_nu.
+	h := math.Max(nu*xi, fpmin)
+	b := xi2 * nu
+	d := 0.0
+	c := h
+	for i := 1; i <= maxIter; i++ {
+		b += xi2
+		d = 1 / (b + d)
+		c = b + 1/c
+		del := c * d
+		h *= del
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	// Downward recurrence to mu.
+	il := fpmin
+	ipl := h * il
+	il1 := il
+	fact := nu * xi
+	for l := nl; l >= 1; l-- {
+		itemp := fact*il + ipl
+		fact -= xi
+		ipl = fact*itemp + il
+		il = itemp
+	}
+	f := ipl / il
+	var kmu, k1 float64
+	if x < xmin {
+		x2 := x / 2
+		pimu := math.Pi * mu
+		fact := 1.0
+		if math.Abs(pimu) >= epsilon {
+			fact = pimu / math.Sin(pimu)
+		}
+		d := -math.Log(x2)
+		e := mu * d
+		fact2 := 1.0
+		if math.Abs(e) >= epsilon {
+			fact2 = math.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := temme(mu)
+		ff := fact * (gam1*math.Cosh(e) + gam2*fact2*d)
+		sum := ff
+		e = math.Exp(e)
+		p := 0.5 * e / gampl
+		q := 0.5 / (e * gammi)
+		c := 1.0
+		d = x2 * x2
+		sum1 := p
+		for i := 1; i <= maxIter; i++ {
+			fi := float64(i)
+			ff = (fi*ff + p + q) / (fi*fi - mu2)
+			c *= d / fi
+			p /= fi - mu
+			q /= fi + mu
+			del := c * ff
+			sum += del
+			sum1 += c * (p - fi*ff)
+			if math.Abs(del) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		kmu = sum
+		k1 = sum1 * xi2
+	} else {
+		// Steed's algorithm for the continued fraction CF2.
+		b := 2 * (1 + x)
+		d := 1 / b
+		h := d
+		delh := d
+		q1, q2 := 0.0, 1.0
+		a1 := 0.25 - mu2
+		q := a1
+		c := a1
+		a := -a1
+		s := 1 + q*delh
+		for i := 2; i <= maxIter; i++ {
+			fi := float64(i)
+			a -= 2 * (fi - 1)
+			c = -a * c / fi
+			qnew := (q1 - b*q2) / a
+			q1, q2 = q2, qnew
+			q += c * qnew
+			b += 2
+			d = 1 / (b + a*d)
+			delh = (b*d - 1) * delh
+			h += delh
+			dels := q * delh
+			s += dels
+			if math.Abs(dels/s) < epsilon {
+				break
+			}
+		}
+		h *= a1
+		kmu = math.Sqrt(math.Pi/(2*x)) * math.Exp(-x) / s
+		k1 = kmu * (mu + x + 0.5 - h) * xi
+	}
+	kmup := mu*xi*kmu - k1
+	imu := xi / (f*kmu - kmup)
+	i := imu * il1 / il
+	// Upward recurrence of K to nu.
+	for l := 1; l <= nl; l++ {
+		ktemp := (mu+float64(l))*xi2*k1 + kmu
+		kmu = k1
+		k1 = ktemp
+	}
+	return i, kmu
+}
+
+// besselIKAsymptotic evaluates the asymptotic expansions of I and K
+// for large arguments.
+func besselIKAsymptotic(nu, x float64) (float64, float64) {
+	mu := 4 * nu * nu
+	si, sk := 1.0, 1.0
+	term := 1.0
+	for k := 1; k < 30; k++ {
+		odd := float64(2*k - 1)
+		term *= (mu - odd*odd) / (float64(k) * 8 * x)
+		sk += term
+		if k%2 == 1 {
+			si -= term
+		} else {
+			si += term
+		}
+		if math.Abs(term) < epsilon {
+			break
+		}
+	}
+	i := math.Exp(x) / math.Sqrt(2*math.Pi*x) * si
+	k := math.Sqrt(math.Pi/(2*x)) * math.Exp(-x) * sk
+	return i, k
+}
+
+// isInteger reports whether v is an integer.
+func isInteger(v float64) bool {
+	return v == math.Trunc(v) && !math.IsInf(v, 0)
+}
+
+// Jv returns the Bessel function of the first kind of real order nu.
+func Jv(nu, x float64) float64 {
+	switch {
+	case math.IsNaN(nu) || math.IsNaN(x) || math.IsInf(nu, 0):
+		return math.NaN()
+	case isInteger(nu) && math.Abs(nu) < 1<<30:
+		return math.Jn(int(nu), x)
+	case x < 0:
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return 0
+	case x == 0:
+		if nu > 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	if nu < 0 {
+		j, y := besselJY(-nu, x)
+		s, c := math.Sincos(-nu * math.Pi)
+		return c*j - s*y
+	}
+	j, _ := besselJY(nu, x)
+	return j
+}
+
+// Yv returns the Bessel function of the second kind of real order nu.
+func Yv(nu, x float64) float64 {
+	switch {
+	case math.IsNaN(nu) || math.IsNaN(x) || math.IsInf(nu, 0):
+		return math.NaN()
+	case isInteger(nu) && math.Abs(nu) < 1<<30:
+		return math.Yn(int(nu), x)
+	case x < 0:
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return 0
+	case x == 0:
+		return math.Inf(-1)
+	}
+	if nu < 0 {
+		j, y := besselJY(-nu, x)
+		s, c := math.Sincos(-nu * math.Pi)
+		return s*j + c*y
+	}
+	_, y := besselJY(nu, x)
+	return y
+}
+
+// Iv returns the modified Bessel function of the first kind of real
+// order nu.
+func Iv(nu, x float64) float64 {
+	switch {
+	case math.IsNaN(nu) || math.IsNaN(x) || math.IsInf(nu, 0):
+		return math.NaN()
+	case x < 0:
+		if !isInteger(nu) {
+			return math.NaN()
+		}
+		if math.Mod(nu, 2) != 0 {
+			return -Iv(nu, -x)
+		}
+		return Iv(nu, -x)
+	case math.IsInf(x, 1):
+		return x
+	case x == 0:
+		switch {
+		case nu == 0:
+			return 1
+		case nu > 0 || isInteger(nu):
+			return 0
+		}
+		return math.Inf(1)
+	}
+	if nu < 0 {
+		i, k := besselIK(-nu, x)
+		if isInteger(nu) {
+			return i
+		}
+		return i + 2/math.Pi*math.Sin(-nu*math.Pi)*k
+	}
+	i, _ := besselIK(nu, x)
+	return i
+}
+
+// Kv returns the modified Bessel function of the second kind of real
+// order nu.
+func Kv(nu, x float64) float64 {
+	switch {
+	case math.IsNaN(nu) || math.IsNaN(x) || math.IsInf(nu, 0) || x < 0:
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return 0
+	case x == 0:
+		return math.Inf(1)
+	}
+	_, k := besselIK(math.Abs(nu), x)
+	return k
+}
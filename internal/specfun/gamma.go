@@ -0,0 +1,220 @@
+// Package specfun implements scalar special functions shared by the
+// special and stats packages.
+package specfun
+
+import "math"
+
+const (
+	epsilon = 2.220446049250313e-16
+	fpmin   = 1e-300
+	maxIter = 10000
+)
+
+// LGamma returns the natural logarithm of the absolute value of the
+// gamma function.
+func LGamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
+
+// Digamma returns the logarithmic derivative of the gamma function.
+func Digamma(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, -1):
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return x
+	case x <= 0 && x == math.Floor(x):
+		return math.NaN()
+	case x < 0:
+		// Reflection formula.
+		return Digamma(1-x) - math.Pi/math.Tan(math.Pi*x)
+	}
+	result := 0.0
+	for x < 10 {
+		result -= 1 / x
+		x++
+	}
+	// Asymptotic expansion in Bernoulli numbers.
+	x2 := 1 / (x * x)
+	series := x2 * (1./12 - x2*(1./120-x2*(1./252-x2*(1./240-
+		x2*(1./132-x2*691./32760)))))
+	return result + math.Log(x) - 0.5/x - series
+}
+
+// LBeta returns the natural logarithm of the absolute value of the
+// beta function.
+func LBeta(a, b float64) float64 {
+	return LGamma(a) + LGamma(b) - LGamma(a+b)
+}
+
+// Beta returns the beta function B(a, b) = Γ(a)Γ(b)/Γ(a+b).
+func Beta(a, b float64) float64 {
+	if a+b < 170 && a > 0 && b > 0 {
+		return math.Gamma(a) * math.Gamma(b) / math.Gamma(a+b)
+	}
+	la, sa := math.Lgamma(a)
+	lb, sb := math.Lgamma(b)
+	lab, sab := math.Lgamma(a + b)
+	return float64(sa*sb*sab) * math.Exp(la+lb-lab)
+}
+
+// GammaInc returns the regularized lower incomplete gamma function
+// P(a, x).
+func GammaInc(a, x float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(x) || a <= 0 || x < 0:
+		return math.NaN()
+	case x == 0:
+		return 0
+	case math.IsInf(x, 1):
+		return 1
+	case x < a+1:
+		return gammaSeries(a, x)
+	}
+	return 1 - gammaFraction(a, x)
+}
+
+// GammaIncC returns the regularized upper incomplete gamma function
+// Q(a, x) = 1 - P(a, x).
+func GammaIncC(a, x float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(x) || a <= 0 || x < 0:
+		return math.NaN()
+	case x == 0:
+		return 1
+	case math.IsInf(x, 1):
+		return 0
+	case x < a+1:
+		return 1 - gammaSeries(a, x)
+	}
+	return gammaFraction(a, x)
+}
+
+// gammaSeries evaluates P(a, x) by its series representation.
+func gammaSeries(a, x float64) float64 {
+	ap := a
+	sum := 1 / a
+	del := sum
+	for i := 0; i < maxIter; i++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*epsilon {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-LGamma(a))
+}
+
+// gammaFraction evaluates Q(a, x) by its continued fraction with the
+// modified Lentz's method.
+func gammaFraction(a, x float64) float64 {
+	b := x + 1 - a
+	c := 1 / fpmin
+	d := 1 / b
+	h := d
+	for i := 1; i <= maxIter; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = b + an/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-LGamma(a)) * h
+}
+
+// BetaInc returns the regularized incomplete beta function I_x(a, b).
+func BetaInc(a, b, x float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(x):
+		return math.NaN()
+	case a <= 0 || b <= 0 || x < 0 || x > 1:
+		return math.NaN()
+	case x == 0:
+		return 0
+	case x == 1:
+		return 1
+	}
+	front := math.Exp(a*math.Log(x) + b*math.Log1p(-x) - LBeta(a, b))
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta
+// function with the modified Lentz's method.
+func betaFraction(a, b, x float64) float64 {
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < fpmin {
+		d = fpmin
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// Expit returns the logistic sigmoid 1/(1+exp(-x)).
+func Expit(x float64) float64 {
+	if x < 0 {
+		e := math.Exp(x)
+		return e / (1 + e)
+	}
+	return 1 / (1 + math.Exp(-x))
+}
+
+// Logit returns the inverse of the logistic sigmoid, log(p/(1-p)).
+func Logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
+
+// Softplus returns log(1+exp(x)) without overflow.
+func Softplus(x float64) float64 {
+	return math.Max(x, 0) + math.Log1p(math.Exp(-math.Abs(x)))
+}
@@ -0,0 +1,134 @@
+package specfun
+
+import "math"
+
+// GammaIncInv returns x such that GammaInc(a, x) = p.
+func GammaIncInv(a, p float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(p) || a <= 0 || p < 0 || p > 1:
+		return math.NaN()
+	case p == 0:
+		return 0
+	case p == 1:
+		return math.Inf(1)
+	}
+	gln := LGamma(a)
+	a1 := a - 1
+	lna1, afac := 0.0, 0.0
+	var x float64
+	if a > 1 {
+		// Wilson-Hilferty approximation.
+		lna1 = math.Log(a1)
+		afac = math.Exp(a1*(lna1-1) - gln)
+		z := normalGuess(p)
+		x = a * math.Pow(1-1/(9*a)-z/(3*math.Sqrt(a)), 3)
+		x = math.Max(1e-3, x)
+	} else {
+		t := 1 - a*(0.253+a*0.12)
+		if p < t {
+			x = math.Pow(p/t, 1/a)
+		} else {
+			x = 1 - math.Log(1-(p-t)/(1-t))
+		}
+	}
+	// Halley's method.
+	for i := 0; i < 100; i++ {
+		if x <= 0 {
+			return 0
+		}
+		err := GammaInc(a, x) - p
+		var t float64
+		if a > 1 {
+			t = afac * math.Exp(-(x-a1)+a1*(math.Log(x)-lna1))
+		} else {
+			t = math.Exp(-x + a1*math.Log(x) - gln)
+		}
+		if t == 0 {
+			break
+		}
+		u := err / t
+		step := u / (1 - 0.5*math.Min(1, u*(a1/x-1)))
+		x -= step
+		if x <= 0 {
+			x = 0.5 * (x + step)
+		}
+		if math.Abs(step) <= 4*epsilon*x {
+			break
+		}
+	}
+	return x
+}
+
+// BetaIncInv returns x such that BetaInc(a, b, x) = p.
+func BetaIncInv(a, b, p float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(p):
+		return math.NaN()
+	case a <= 0 || b <= 0 || p < 0 || p > 1:
+		return math.NaN()
+	case p == 0:
+		return 0
+	case p == 1:
+		return 1
+	}
+	a1, b1 := a-1, b-1
+	var x float64
+	if a >= 1 && b >= 1 {
+		z := normalGuess(p)
+		al := (z*z - 3) / 6
+		h := 2 / (1/(2*a-1) + 1/(2*b-1))
+		w := z*math.Sqrt(al+h)/h - (1/(2*b-1)-1/(2*a-1))*(al+5./6-2/(3*h))
+		x = a / (a + b*math.Exp(2*w))
+	} else {
+		lna := math.Log(a / (a + b))
+		lnb := math.Log(b / (a + b))
+		t := math.Exp(a*lna) / a
+		u := math.Exp(b*lnb) / b
+		w := t + u
+		if p < t/w {
+			x = math.Pow(a*w*p, 1/a)
+		} else {
+			x = 1 - math.Pow(b*w*(1-p), 1/b)
+		}
+	}
+	afac := -LBeta(a, b)
+	// Halley's method.
+	for i := 0; i < 100; i++ {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+		err := BetaInc(a, b, x) - p
+		t := math.Exp(a1*math.Log(x) + b1*math.Log1p(-x) + afac)
+		if t == 0 {
+			break
+		}
+		u := err / t
+		step := u / (1 - 0.5*math.Min(1, u*(a1/x-b1/(1-x))))
+		x -= step
+		if x <= 0 {
+			x = 0.5 * (x + step)
+		}
+		if x >= 1 {
+			x = 0.5 * (x + step + 1)
+		}
+		if math.Abs(step) <= 4*epsilon*x && i > 0 {
+			break
+		}
+	}
+	return x
+}
+
+// normalGuess returns a rough approximation of the quantile of the
+// standard normal distribution at p, with the sign flipped.
+func normalGuess(p float64) float64 {
+	pp := p
+	if p >= 0.5 {
+		pp = 1 - p
+	}
+	t := math.Sqrt(-2 * math.Log(pp))
+	z := (2.30753+t*0.27061)/(1+t*(0.99229+t*0.04481)) - t
+	if p < 0.5 {
+		z = -z
+	}
+	return z
+}
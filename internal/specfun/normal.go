@@ -0,0 +1,107 @@
+package specfun
+
+import "math"
+
+// Coefficients of Wichura's algorithm AS 241, PPND16, for the quantile
+// of the standard normal distribution: in the centre, for |p - 0.5| at
+// most 0.425, and in the tails, for r = sqrt(-log(p)) at most 5 and
+// beyond.
+var (
+	ndtriCentreNum = [...]float64{
+		3.387132872796366608, 133.14166789178437745, 1971.5909503065514427,
+		13731.693765509461125, 45921.953931549871457, 67265.770927008700853,
+		33430.575583588128105, 2509.0809287301226727,
+	}
+	ndtriCentreDen = [...]float64{
+		1, 42.313330701600911252, 687.1870074920579083,
+		5394.1960214247511077, 21213.794301586595867, 39307.89580009271061,
+		28729.085735721942674, 5226.495278852545925,
+	}
+	ndtriNearNum = [...]float64{
+		1.42343711074968357734, 4.6303378461565452959, 5.7694972214606914055,
+		3.64784832476320460504, 1.27045825245236838258, 0.24178072517745061177,
+		0.0227238449892691845833, 7.7454501427834140764e-4,
+	}
+	ndtriNearDen = [...]float64{
+		1, 2.05319162663775882187, 1.6763848301838038494,
+		0.68976733498510000455, 0.14810397642748007459, 0.0151986665636164571966,
+		5.475938084995344946e-4, 1.05075007164441684324e-9,
+	}
+	ndtriFarNum = [...]float64{
+		6.6579046435011037772, 5.4637849111641143699, 1.7848265399172913358,
+		0.29656057182850489123, 0.026532189526576123093, 0.0012426609473880784386,
+		2.71155556874348757815e-5, 2.01033439929228813265e-7,
+	}
+	ndtriFarDen = [...]float64{
+		1, 0.59983220655588793769, 0.13692988092273580531,
+		0.0148753612908506148525, 7.868691311456132591e-4, 1.8463183175100546818e-5,
+		1.4215117583164458887e-7, 2.04426310338993978564e-15,
+	}
+)
+
+// polynomial returns the polynomial with coefficients c, by increasing
+// degree, at x.
+func polynomial(c []float64, x float64) float64 {
+	sum := 0.0
+	for i := len(c) - 1; i >= 0; i-- {
+		sum = sum*x + c[i]
+	}
+	return sum
+}
+
+// NormalQuantile returns the quantile of the standard normal
+// distribution at p, accurate to about 1e-16 relative to the result
+// down to the smallest p, by Wichura's algorithm AS 241.
+func NormalQuantile(p float64) float64 {
+	switch {
+	case math.IsNaN(p) || p < 0 || p > 1:
+		return math.NaN()
+	case p == 0:
+		return math.Inf(-1)
+	case p == 1:
+		return math.Inf(1)
+	}
+	q := p - 0.5
+	if math.Abs(q) <= 0.425 {
+		r := 0.180625 - q*q
+		return q * polynomial(ndtriCentreNum[:], r) / polynomial(ndtriCentreDen[:], r)
+	}
+	r := p
+	if q > 0 {
+		r = 1 - p
+	}
+	z := normalTail(-math.Log(r))
+	if q < 0 {
+		z = -z
+	}
+	return z
+}
+
+// normalTail returns the magnitude of the quantile of the standard
+// normal distribution at a tail probability exp(-l) below 0.075.
+func normalTail(l float64) float64 {
+	r := math.Sqrt(l)
+	if r <= 5 {
+		r -= 1.6
+		return polynomial(ndtriNearNum[:], r) / polynomial(ndtriNearDen[:], r)
+	}
+	r -= 5
+	return polynomial(ndtriFarNum[:], r) / polynomial(ndtriFarDen[:], r)
+}
+
+// Erfcinv returns x such that math.Erfc(x) = y.  Unlike math.Erfcinv,
+// which inverts the error function at 1 - y, it keeps its accuracy for
+// y near 0 and 2.
+func Erfcinv(y float64) float64 {
+	switch {
+	case y > 0 && y < 0.15:
+		// erfc(x) = 2Φ(-x√2), whose tail is computed from the logarithm
+		// of y/2, as halving subnormal arguments may lose them.
+		return normalTail(math.Ln2-math.Log(y)) / math.Sqrt2
+	case y > 0 && y < 0.5:
+		return -NormalQuantile(y/2) / math.Sqrt2
+	case y > 1.5 && y < 2:
+		return -Erfcinv(2 - y)
+	}
+	return math.Erfcinv(y)
+}
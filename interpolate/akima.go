@@ -0,0 +1,48 @@
+package interpolate
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// NewAkima returns the Akima interpolant of the samples (x, y), a
+// piecewise cubic Hermite interpolant whose derivatives are weighted
+// averages of the neighbouring secant slopes.  It avoids the overshoot
+// of cubic splines near outliers.
+func NewAkima(x, y *array.Dense) (*PiecewiseCubic, error) {
+	xs, ys, err := samples(x, y, true)
+	if err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	_, delta := secants(xs, ys)
+	t := make([]float64, n)
+	if n == 2 {
+		t[0], t[1] = delta[0], delta[0]
+		return &PiecewiseCubic{x: xs, y: ys, dydx: t}, nil
+	}
+	// Secant slopes extended by two quadratic extrapolations at each
+	// end, so that m[i+2] is the slope of the interval i.
+	m := make([]float64, n+3)
+	copy(m[2:], delta)
+	m[1] = 2*m[2] - m[3]
+	m[0] = 2*m[1] - m[2]
+	m[n+1] = 2*m[n] - m[n-1]
+	m[n+2] = 2*m[n+1] - m[n]
+	maxWeight := 0.0
+	for i := 0; i < n; i++ {
+		maxWeight = math.Max(maxWeight,
+			math.Abs(m[i+3]-m[i+2])+math.Abs(m[i+1]-m[i]))
+	}
+	for i := 0; i < n; i++ {
+		f1 := math.Abs(m[i+3] - m[i+2])
+		f2 := math.Abs(m[i+1] - m[i])
+		if f1+f2 > 1e-9*maxWeight {
+			t[i] = (f1*m[i+1] + f2*m[i+2]) / (f1 + f2)
+		} else {
+			t[i] = (m[i+1] + m[i+2]) / 2
+		}
+	}
+	return &PiecewiseCubic{x: xs, y: ys, dydx: t}, nil
+}
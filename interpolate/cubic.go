@@ -0,0 +1,160 @@
+package interpolate
+
+import (
+	"github.com/jimmyskull/math/array"
+)
+
+// PiecewiseCubic is a piecewise cubic Hermite interpolant defined by
+// the value and the first derivative at each sample point.  Points
+// outside the sample range are extrapolated with the first and last
+// polynomial pieces.
+type PiecewiseCubic struct {
+	x, y, dydx []float64
+}
+
+// NewHermite returns the piecewise cubic Hermite interpolant matching
+// the values y and the first derivatives dydx at the sample points x.
+func NewHermite(x, y, dydx *array.Dense) (*PiecewiseCubic, error) {
+	xs, ys, err := samples(x, y, true)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := vector(dydx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ds) != len(xs) {
+		return nil, ErrUnmatchedLengths
+	}
+	return &PiecewiseCubic{x: xs, y: ys, dydx: ds}, nil
+}
+
+// At evaluates the interpolant at x.
+func (p *PiecewiseCubic) At(x float64) float64 {
+	i := interval(p.x, x)
+	h := p.x[i+1] - p.x[i]
+	delta := (p.y[i+1] - p.y[i]) / h
+	m0, m1 := p.dydx[i], p.dydx[i+1]
+	c2 := (3*delta - 2*m0 - m1) / h
+	c3 := (m0 + m1 - 2*delta) / (h * h)
+	t := x - p.x[i]
+	return p.y[i] + t*(m0+t*(c2+t*c3))
+}
+
+// Eval evaluates the interpolant at every item of x.
+func (p *PiecewiseCubic) Eval(x *array.Dense) (*array.Dense, error) {
+	return evaluate(x, p.At)
+}
+
+// BoundaryKind identifies an end condition of a cubic spline.
+type BoundaryKind int
+
+const (
+	// NotAKnot requires the third derivative to be continuous at the
+	// second and the penultimate sample points.
+	NotAKnot BoundaryKind = iota
+
+	// Natural sets the second derivative to zero at both ends.
+	Natural
+
+	// Clamped sets the first derivative at both ends to the values in
+	// Boundary.Left and Boundary.Right.
+	Clamped
+)
+
+// Boundary specifies the end conditions of a cubic spline.
+type Boundary struct {
+	Kind BoundaryKind
+	// Left and Right are the end derivatives of a Clamped spline.
+	Left, Right float64
+}
+
+// NewCubicSpline returns the cubic spline with continuous second
+// derivative interpolating the samples (x, y) under the given end
+// conditions.  The sample points x must be strictly increasing.
+func NewCubicSpline(x, y *array.Dense, bc Boundary) (*PiecewiseCubic, error) {
+	xs, ys, err := samples(x, y, true)
+	if err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	h, delta := secants(xs, ys)
+	m := make([]float64, n)
+	if n == 2 && bc.Kind != Clamped {
+		m[0], m[1] = delta[0], delta[0]
+		return &PiecewiseCubic{x: xs, y: ys, dydx: m}, nil
+	}
+	if n == 3 && bc.Kind == NotAKnot {
+		// The not-a-knot spline through three points is the parabola
+		// interpolating them.
+		c := (delta[1] - delta[0]) / (xs[2] - xs[0])
+		for i := range m {
+			m[i] = delta[0] + (2*xs[i]-xs[0]-xs[1])*c
+		}
+		return &PiecewiseCubic{x: xs, y: ys, dydx: m}, nil
+	}
+	// Tridiagonal system with sub-diagonal a, diagonal b, super-diagonal
+	// c and right-hand side r.
+	a := make([]float64, n)
+	b := make([]float64, n)
+	c := make([]float64, n)
+	r := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		a[i] = h[i]
+		b[i] = 2 * (h[i-1] + h[i])
+		c[i] = h[i-1]
+		r[i] = 3 * (h[i]*delta[i-1] + h[i-1]*delta[i])
+	}
+	switch bc.Kind {
+	case Clamped:
+		b[0], r[0] = 1, bc.Left
+		b[n-1], r[n-1] = 1, bc.Right
+	case Natural:
+		b[0], c[0], r[0] = 2, 1, 3*delta[0]
+		a[n-1], b[n-1], r[n-1] = 1, 2, 3*delta[n-2]
+	case NotAKnot:
+		d := xs[2] - xs[0]
+		b[0], c[0] = h[1], d
+		r[0] = ((h[0]+2*d)*h[1]*delta[0] + h[0]*h[0]*delta[1]) / d
+		d = xs[n-1] - xs[n-3]
+		a[n-1], b[n-1] = d, h[n-3]
+		r[n-1] = (h[n-2]*h[n-2]*delta[n-3] +
+			(2*d+h[n-2])*h[n-3]*delta[n-2]) / d
+	default:
+		return nil, &array.Error{
+			Operation: "cubic spline",
+			Message:   "unknown boundary condition",
+		}
+	}
+	solveTridiagonal(a, b, c, r, m)
+	return &PiecewiseCubic{x: xs, y: ys, dydx: m}, nil
+}
+
+// secants returns the interval widths and the slopes of the secant
+// lines between consecutive samples.
+func secants(xs, ys []float64) (h, delta []float64) {
+	n := len(xs)
+	h = make([]float64, n-1)
+	delta = make([]float64, n-1)
+	for i := 0; i < n-1; i++ {
+		h[i] = xs[i+1] - xs[i]
+		delta[i] = (ys[i+1] - ys[i]) / h[i]
+	}
+	return h, delta
+}
+
+// solveTridiagonal solves a tridiagonal linear system using the Thomas
+// algorithm, storing the solution in x.  The slices b and r are
+// overwritten.
+func solveTridiagonal(a, b, c, r, x []float64) {
+	n := len(b)
+	for i := 1; i < n; i++ {
+		w := a[i] / b[i-1]
+		b[i] -= w * c[i-1]
+		r[i] -= w * r[i-1]
+	}
+	x[n-1] = r[n-1] / b[n-1]
+	for i := n - 2; i >= 0; i-- {
+		x[i] = (r[i] - c[i]*x[i+1]) / b[i]
+	}
+}
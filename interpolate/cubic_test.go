@@ -0,0 +1,133 @@
+package interpolate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/interpolate"
+)
+
+func cubic(x float64) float64 {
+	return x*x*x - 2*x*x + 3*x - 1
+}
+
+func cubicDerivative(x float64) float64 {
+	return 3*x*x - 4*x + 3
+}
+
+func samplePoints(t *testing.T, fn func(float64) float64, xs ...float64) (
+	*array.Dense, *array.Dense,
+) {
+	t.Helper()
+	ys := make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = fn(x)
+	}
+	return arraytest.Vector(t, xs...), arraytest.Vector(t, ys...)
+}
+
+func TestCubicSplineReproducesCubic(t *testing.T) {
+	x, y := samplePoints(t, cubic, 0, 0.5, 1.5, 2, 3.5, 4)
+	splines := map[string]interpolate.Boundary{
+		"not-a-knot": {Kind: interpolate.NotAKnot},
+		"clamped": {
+			Kind:  interpolate.Clamped,
+			Left:  cubicDerivative(0),
+			Right: cubicDerivative(4),
+		},
+	}
+	for name, bc := range splines {
+		s, err := interpolate.NewCubicSpline(x, y, bc)
+		if assert.Nil(t, err, name) {
+			for _, v := range []float64{-0.5, 0.25, 1, 2.7, 3.9, 4.5} {
+				assert.InDelta(t, cubic(v), s.At(v), 1e-9, name)
+			}
+		}
+	}
+}
+
+func TestCubicSplineNatural(t *testing.T) {
+	line := func(x float64) float64 { return 2*x - 1 }
+	x, y := samplePoints(t, line, 0, 1, 3, 4)
+	s, err := interpolate.NewCubicSpline(
+		x, y, interpolate.Boundary{Kind: interpolate.Natural})
+	if assert.Nil(t, err) {
+		assert.InDelta(t, line(2.2), s.At(2.2), 1e-12)
+	}
+	// Natural spline through (0, 0), (1, 1), (2, 0) has slopes 1.5, 0,
+	// -1.5 at the knots.
+	s, err = interpolate.NewCubicSpline(
+		arraytest.Vector(t, 0, 1, 2), arraytest.Vector(t, 0, 1, 0),
+		interpolate.Boundary{Kind: interpolate.Natural})
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.6875, s.At(0.5), 1e-12)
+	}
+	// Three points with not-a-knot conditions give a parabola.
+	parabola := func(x float64) float64 { return x*x - x }
+	x, y = samplePoints(t, parabola, 0, 1, 3)
+	s, err = interpolate.NewCubicSpline(
+		x, y, interpolate.Boundary{Kind: interpolate.NotAKnot})
+	if assert.Nil(t, err) {
+		assert.InDelta(t, parabola(2), s.At(2), 1e-12)
+	}
+	_, err = interpolate.NewCubicSpline(
+		arraytest.Vector(t, 1), arraytest.Vector(t, 1), interpolate.Boundary{})
+	assert.ErrorIs(t, err, interpolate.ErrTooFewPoints)
+	_, err = interpolate.NewCubicSpline(
+		arraytest.Vector(t, 1, 1), arraytest.Vector(t, 1, 2), interpolate.Boundary{})
+	assert.ErrorIs(t, err, interpolate.ErrNotIncreasing)
+}
+
+func TestPCHIPPreservesMonotonicity(t *testing.T) {
+	x := arraytest.Vector(t, 0, 1, 2, 3, 4, 5)
+	y := arraytest.Vector(t, 0, 0, 0.1, 5, 5.1, 5.1)
+	p, err := interpolate.NewPCHIP(x, y)
+	if assert.Nil(t, err) {
+		q, err := array.Linspace(0, 5, 101, true)
+		assert.Nil(t, err)
+		values, err := p.Eval(q)
+		if assert.Nil(t, err) {
+			v := values.Values()
+			for i := 1; i < len(v); i++ {
+				assert.GreaterOrEqual(t, v[i], v[i-1])
+			}
+			assert.InDelta(t, 5.0, v[60], 1e-12)
+			// Flat regions stay flat.
+			assert.Equal(t, 0.0, v[10])
+			assert.InDelta(t, 5.1, v[90], 1e-12)
+		}
+	}
+}
+
+func TestAkima(t *testing.T) {
+	line := func(x float64) float64 { return 3 - x/2 }
+	x, y := samplePoints(t, line, 0, 1, 2.5, 3, 5)
+	a, err := interpolate.NewAkima(x, y)
+	if assert.Nil(t, err) {
+		for _, v := range []float64{0.3, 1.7, 4.2} {
+			assert.InDelta(t, line(v), a.At(v), 1e-12)
+		}
+	}
+	// A step does not overshoot.
+	x = arraytest.Vector(t, 0, 1, 2, 3, 4, 5, 6)
+	y = arraytest.Vector(t, 0, 0, 0, 1, 1, 1, 1)
+	a, err = interpolate.NewAkima(x, y)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0.0, a.At(1.5))
+		assert.Equal(t, 1.0, a.At(4.5))
+	}
+}
+
+func TestHermite(t *testing.T) {
+	x, y := samplePoints(t, cubic, 0, 1, 2)
+	_, dydx := samplePoints(t, cubicDerivative, 0, 1, 2)
+	h, err := interpolate.NewHermite(x, y, dydx)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, cubic(1.3), h.At(1.3), 1e-12)
+	}
+	_, err = interpolate.NewHermite(x, y, arraytest.Vector(t, 1))
+	assert.ErrorIs(t, err, interpolate.ErrUnmatchedLengths)
+}
@@ -0,0 +1,26 @@
+package interpolate
+
+import "errors"
+
+var (
+	// ErrNotVector is returned when a one-dimensional array is expected.
+	ErrNotVector = errors.New("array must be one-dimensional")
+
+	// ErrUnmatchedLengths is returned when sample points and sample
+	// values have different lengths.
+	ErrUnmatchedLengths = errors.New(
+		"sample points and values must have the same length")
+
+	// ErrNotIncreasing is returned when sample points are not sorted in
+	// increasing order.
+	ErrNotIncreasing = errors.New("sample points must be increasing")
+
+	// ErrTooFewPoints is returned when there are not enough sample
+	// points to build an interpolant.
+	ErrTooFewPoints = errors.New("at least two sample points are required")
+
+	// ErrInvalidPoints is returned when the query points do not match
+	// the dimensions of a grid.
+	ErrInvalidPoints = errors.New(
+		"query points do not match the grid dimensions")
+)
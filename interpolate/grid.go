@@ -0,0 +1,122 @@
+package interpolate
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// RegularGrid is a multilinear interpolant of values sampled on a
+// rectilinear grid in any number of dimensions.
+type RegularGrid struct {
+	// FillValue is returned for query points outside the grid when
+	// Extrapolate is false.  It defaults to NaN.
+	FillValue float64
+
+	// Extrapolate enables linear extrapolation from the border cells
+	// for query points outside the grid.
+	Extrapolate bool
+
+	points  [][]float64
+	values  []float64
+	strides []int
+}
+
+// NewRegularGrid returns the multilinear interpolant of values sampled
+// at the grid defined by points.  Each item of points holds the
+// strictly increasing coordinates along one dimension, and values must
+// have the shape given by the lengths of those coordinates.
+func NewRegularGrid(points []*array.Dense, values *array.Dense) (*RegularGrid, error) {
+	nd := len(points)
+	if nd == 0 || len(values.Shape) != nd {
+		return nil, ErrInvalidPoints
+	}
+	g := &RegularGrid{
+		FillValue: math.NaN(),
+		points:    make([][]float64, nd),
+		values:    values.Values(),
+		strides:   make([]int, nd),
+	}
+	for axis, p := range points {
+		xs, err := vector(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(xs) < 2 {
+			return nil, ErrTooFewPoints
+		}
+		if len(xs) != values.Shape[axis] {
+			return nil, ErrUnmatchedLengths
+		}
+		if err := increasing(xs, true); err != nil {
+			return nil, err
+		}
+		g.points[axis] = xs
+	}
+	// Strides, in items, of the values in row-major order.
+	stride := 1
+	for axis := nd - 1; axis >= 0; axis-- {
+		g.strides[axis] = stride
+		stride *= values.Shape[axis]
+	}
+	return g, nil
+}
+
+// At evaluates the interpolant at a single point with one coordinate
+// per grid dimension.  NaN is returned if the number of coordinates
+// does not match the grid dimensions.
+func (g *RegularGrid) At(point []float64) float64 {
+	nd := len(g.points)
+	if len(point) != nd {
+		return math.NaN()
+	}
+	base := 0
+	weights := make([]float64, nd)
+	for axis, v := range point {
+		xs := g.points[axis]
+		if math.IsNaN(v) {
+			return math.NaN()
+		}
+		if !g.Extrapolate && (v < xs[0] || v > xs[len(xs)-1]) {
+			return g.FillValue
+		}
+		i := interval(xs, v)
+		weights[axis] = (v - xs[i]) / (xs[i+1] - xs[i])
+		base += i * g.strides[axis]
+	}
+	// Accumulate the contribution of the 2^nd corners of the cell.
+	sum := 0.0
+	for corner := 0; corner < 1<<uint(nd); corner++ {
+		w := 1.0
+		pos := base
+		for axis := 0; axis < nd; axis++ {
+			if corner&(1<<uint(axis)) != 0 {
+				w *= weights[axis]
+				pos += g.strides[axis]
+			} else {
+				w *= 1 - weights[axis]
+			}
+		}
+		if w != 0 {
+			sum += w * g.values[pos]
+		}
+	}
+	return sum
+}
+
+// Eval evaluates the interpolant at the query points xi, an array whose
+// last axis holds the coordinates of each point.  The result has the
+// shape of xi without its last axis.
+func (g *RegularGrid) Eval(xi *array.Dense) (*array.Dense, error) {
+	nd := len(g.points)
+	if len(xi.Shape) == 0 || xi.Shape[len(xi.Shape)-1] != nd {
+		return nil, ErrInvalidPoints
+	}
+	coords := xi.Values()
+	out := make([]float64, len(coords)/nd)
+	for i := range out {
+		out[i] = g.At(coords[i*nd : (i+1)*nd])
+	}
+	shape := append(array.Shape{}, xi.Shape[:len(xi.Shape)-1]...)
+	return array.NewDenseWithValues(shape, out, array.DefaultAttributes)
+}
@@ -0,0 +1,58 @@
+package interpolate_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/interpolate"
+)
+
+func TestRegularGrid(t *testing.T) {
+	plane := func(x, y float64) float64 { return 2*x - 3*y + 1 }
+	xs := []float64{0, 1, 3}
+	ys := []float64{-1, 0, 2, 4}
+	values := make([]float64, 0, len(xs)*len(ys))
+	for _, x := range xs {
+		for _, y := range ys {
+			values = append(values, plane(x, y))
+		}
+	}
+	v, err := array.NewDenseWithValues(
+		array.Shape{3, 4}, values, array.ColumnMajorLayout)
+	assert.Nil(t, err)
+	g, err := interpolate.NewRegularGrid(
+		[]*array.Dense{arraytest.Vector(t, xs...), arraytest.Vector(t, ys...)}, v)
+	if !assert.Nil(t, err) {
+		return
+	}
+	query, err := array.NewDenseWithValues(
+		array.Shape{2, 2, 2},
+		[]float64{0.5, 0.5, 2.9, 3.9, 3, 4, 5, 0},
+		array.RowMajorLayout)
+	assert.Nil(t, err)
+	out, err := g.Eval(query)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, out.Shape)
+		r := out.Values()
+		assert.InDelta(t, plane(0.5, 0.5), r[0], 1e-12)
+		assert.InDelta(t, plane(2.9, 3.9), r[1], 1e-12)
+		assert.InDelta(t, plane(3, 4), r[2], 1e-12)
+		assert.True(t, math.IsNaN(r[3]))
+	}
+	g.Extrapolate = true
+	assert.InDelta(t, plane(5, 0), g.At([]float64{5, 0}), 1e-12)
+	g.Extrapolate, g.FillValue = false, -1
+	assert.Equal(t, -1.0, g.At([]float64{5, 0}))
+
+	_, err = g.Eval(arraytest.Vector(t, 1, 2, 3))
+	assert.ErrorIs(t, err, interpolate.ErrInvalidPoints)
+	_, err = interpolate.NewRegularGrid([]*array.Dense{arraytest.Vector(t, xs...)}, v)
+	assert.ErrorIs(t, err, interpolate.ErrInvalidPoints)
+	_, err = interpolate.NewRegularGrid(
+		[]*array.Dense{arraytest.Vector(t, ys...), arraytest.Vector(t, xs...)}, v)
+	assert.ErrorIs(t, err, interpolate.ErrUnmatchedLengths)
+}
@@ -0,0 +1,115 @@
+// Package interpolate provides one-dimensional and regular-grid
+// interpolation of samples stored in Dense arrays.
+package interpolate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Interpolator is a function built from samples that can be evaluated
+// at arbitrary points.
+type Interpolator interface {
+	// At evaluates the interpolant at a single point.
+	At(x float64) float64
+
+	// Eval evaluates the interpolant at every item of x, returning an
+	// array with the same shape as x.
+	Eval(x *array.Dense) (*array.Dense, error)
+}
+
+// Interp returns the one-dimensional piecewise linear interpolant of
+// the samples (xp, fp) evaluated at every item of x.  The sample points
+// xp must be increasing.  Points below xp[0] are set to left and points
+// above the last sample point are set to right.
+func Interp(x, xp, fp *array.Dense, left, right float64) (*array.Dense, error) {
+	xs, ys, err := samples(xp, fp, false)
+	if err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	return evaluate(x, func(v float64) float64 {
+		switch {
+		case math.IsNaN(v):
+			return math.NaN()
+		case v < xs[0]:
+			return left
+		case v > xs[n-1]:
+			return right
+		}
+		i := sort.SearchFloat64s(xs, v)
+		if xs[i] == v {
+			return ys[i]
+		}
+		t := (v - xs[i-1]) / (xs[i] - xs[i-1])
+		return ys[i-1] + t*(ys[i]-ys[i-1])
+	})
+}
+
+// evaluate applies fn to every item of x, returning the results in an
+// array with the same shape.
+func evaluate(x *array.Dense, fn func(float64) float64) (*array.Dense, error) {
+	values := x.Values()
+	for i, v := range values {
+		values[i] = fn(v)
+	}
+	return array.NewDenseWithValues(x.Shape, values, array.DefaultAttributes)
+}
+
+// vector returns the items of a one-dimensional array.
+func vector(d *array.Dense) ([]float64, error) {
+	if len(d.Shape) != 1 {
+		return nil, ErrNotVector
+	}
+	return d.Values(), nil
+}
+
+// samples validates and returns sample points and values.  When strict
+// is set, sample points must be strictly increasing.
+func samples(x, y *array.Dense, strict bool) ([]float64, []float64, error) {
+	xs, err := vector(x)
+	if err != nil {
+		return nil, nil, err
+	}
+	ys, err := vector(y)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(xs) != len(ys) {
+		return nil, nil, ErrUnmatchedLengths
+	}
+	if len(xs) == 0 || (strict && len(xs) < 2) {
+		return nil, nil, ErrTooFewPoints
+	}
+	if err := increasing(xs, strict); err != nil {
+		return nil, nil, err
+	}
+	return xs, ys, nil
+}
+
+func increasing(xs []float64, strict bool) error {
+	for i := 1; i < len(xs); i++ {
+		if xs[i] < xs[i-1] || (strict && xs[i] == xs[i-1]) {
+			return ErrNotIncreasing
+		}
+		if math.IsNaN(xs[i]) {
+			return ErrNotIncreasing
+		}
+	}
+	return nil
+}
+
+// interval returns the index i of the interval [xs[i], xs[i+1]]
+// containing v, clamped to the first and last intervals.
+func interval(xs []float64, v float64) int {
+	i := sort.SearchFloat64s(xs, v) - 1
+	if i < 0 {
+		return 0
+	}
+	if i > len(xs)-2 {
+		return len(xs) - 2
+	}
+	return i
+}
@@ -0,0 +1,44 @@
+package interpolate_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/interpolate"
+)
+
+func TestInterp(t *testing.T) {
+	xp := arraytest.Vector(t, 1, 2, 3)
+	fp := arraytest.Vector(t, 3, 2, 0)
+	x := arraytest.Vector(t, 0, 1, 1.5, 2.5, 3, 4, math.NaN())
+	y, err := interpolate.Interp(x, xp, fp, -1, 99)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{7}, y.Shape)
+		values := y.Values()
+		assert.Equal(t, []float64{-1, 3, 2.5, 1, 0, 99}, values[:6])
+		assert.True(t, math.IsNaN(values[6]))
+	}
+	// Query points keep their shape.
+	grid, err := array.NewDenseWithValues(
+		array.Shape{2, 2}, []float64{1, 1.25, 2.5, 2.75}, array.RowMajorLayout)
+	if assert.Nil(t, err) {
+		y, err = interpolate.Interp(grid, xp, fp, 0, 0)
+		if assert.Nil(t, err) {
+			assert.Equal(t, array.Shape{2, 2}, y.Shape)
+			v, _ := y.Get(array.Indices{0, 1})
+			assert.Equal(t, 2.75, v)
+			v, _ = y.Get(array.Indices{1, 1})
+			assert.Equal(t, 0.5, v)
+		}
+	}
+	_, err = interpolate.Interp(x, arraytest.Vector(t, 1, 2), fp, 0, 0)
+	assert.ErrorIs(t, err, interpolate.ErrUnmatchedLengths)
+	_, err = interpolate.Interp(x, arraytest.Vector(t, 1, 3, 2), fp, 0, 0)
+	assert.ErrorIs(t, err, interpolate.ErrNotIncreasing)
+	_, err = interpolate.Interp(x, grid, fp, 0, 0)
+	assert.ErrorIs(t, err, interpolate.ErrNotVector)
+}
@@ -0,0 +1,59 @@
+package interpolate
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// NewPCHIP returns the piecewise cubic Hermite interpolating polynomial
+// of the samples (x, y) whose derivatives are chosen by the
+// Fritsch-Carlson method to preserve monotonicity of the data.
+func NewPCHIP(x, y *array.Dense) (*PiecewiseCubic, error) {
+	xs, ys, err := samples(x, y, true)
+	if err != nil {
+		return nil, err
+	}
+	n := len(xs)
+	h, delta := secants(xs, ys)
+	m := make([]float64, n)
+	if n == 2 {
+		m[0], m[1] = delta[0], delta[0]
+		return &PiecewiseCubic{x: xs, y: ys, dydx: m}, nil
+	}
+	for k := 1; k < n-1; k++ {
+		if delta[k-1]*delta[k] <= 0 {
+			continue
+		}
+		// Weighted harmonic mean of the neighbouring secants.
+		w1 := 2*h[k] + h[k-1]
+		w2 := h[k] + 2*h[k-1]
+		m[k] = (w1 + w2) / (w1/delta[k-1] + w2/delta[k])
+	}
+	m[0] = pchipEdge(h[0], h[1], delta[0], delta[1])
+	m[n-1] = pchipEdge(h[n-2], h[n-3], delta[n-2], delta[n-3])
+	return &PiecewiseCubic{x: xs, y: ys, dydx: m}, nil
+}
+
+// pchipEdge computes an end derivative using a non-centered,
+// shape-preserving three-point formula.
+func pchipEdge(h0, h1, m0, m1 float64) float64 {
+	d := ((2*h0+h1)*m0 - h0*m1) / (h0 + h1)
+	switch {
+	case sign(d) != sign(m0):
+		return 0
+	case sign(m0) != sign(m1) && math.Abs(d) > 3*math.Abs(m0):
+		return 3 * m0
+	}
+	return d
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
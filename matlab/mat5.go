@@ -0,0 +1,525 @@
+package matlab
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/ctxio"
+)
+
+// Data types of MAT-file elements.
+const (
+	miINT8       = 1
+	miUINT8      = 2
+	miINT16      = 3
+	miUINT16     = 4
+	miINT32      = 5
+	miUINT32     = 6
+	miSINGLE     = 7
+	miDOUBLE     = 9
+	miINT64      = 12
+	miUINT64     = 13
+	miMATRIX     = 14
+	miCOMPRESSED = 15
+	miUTF8       = 16
+	miUTF16      = 17
+	miUTF32      = 18
+)
+
+// Classes of MAT-file arrays.
+const (
+	mxCELL   = 1
+	mxSTRUCT = 2
+	mxCHAR   = 4
+	mxDOUBLE = 6
+	mxUINT64 = 15
+)
+
+// Array flags.
+const (
+	flagComplex = 0x08
+	flagLogical = 0x02
+)
+
+// headerLength is the length of the header of MAT-files.
+const headerLength = 128
+
+// headerText starts the header of the MAT-files written.
+const headerText = "MATLAB 5.0 MAT-file, written by github.com/jimmyskull/math"
+
+// decoder decodes the elements of a MAT-file of a given byte order.
+type decoder struct {
+	order binary.ByteOrder
+}
+
+// ReadMAT reads the variables of a MATLAB Level 5 MAT-file, either
+// little- or big-endian, with or without compressed elements.
+func ReadMAT(r io.Reader) (map[string]interface{}, error) {
+	return ReadMATContext(context.Background(), r)
+}
+
+// ReadMATContext is like ReadMAT, but stops reading and returns an
+// error wrapping the error of ctx once it is done.
+func ReadMATContext(ctx context.Context, r io.Reader) (map[string]interface{}, error) {
+	vars, err := readMAT(ctxio.NewReader(ctx, r))
+	if err != nil {
+		return nil, ctxio.Canceled(ctx, "read MAT-file", err)
+	}
+	return vars, nil
+}
+
+func readMAT(r io.Reader) (map[string]interface{}, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < headerLength {
+		return nil, ErrInvalidFile
+	}
+	var dec decoder
+	switch string(b[126:128]) {
+	case "IM":
+		dec.order = binary.LittleEndian
+	case "MI":
+		dec.order = binary.BigEndian
+	default:
+		return nil, ErrInvalidFile
+	}
+	if dec.order.Uint16(b[124:]) != 0x0100 {
+		return nil, ErrInvalidFile
+	}
+	vars := make(map[string]interface{})
+	for rest := b[headerLength:]; len(rest) > 0; {
+		typ, data, next, err := dec.element(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = next
+		name, value, err := dec.variable(typ, data)
+		if err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// element splits the first element of b into its type and data,
+// returning the bytes that follow it.
+func (dec *decoder) element(b []byte) (uint32, []byte, []byte, error) {
+	if len(b) < 8 {
+		return 0, nil, nil, ErrInvalidFile
+	}
+	tag := dec.order.Uint32(b)
+	if n := tag >> 16; n != 0 {
+		// Small data element, packed with its tag in eight bytes.
+		if n > 4 {
+			return 0, nil, nil, ErrInvalidFile
+		}
+		return tag & 0xffff, b[4 : 4+n], b[8:], nil
+	}
+	n := uint64(dec.order.Uint32(b[4:]))
+	if n > uint64(len(b)-8) {
+		return 0, nil, nil, ErrInvalidFile
+	}
+	end := 8 + n
+	if tag != miCOMPRESSED {
+		// Other elements are padded to eight bytes, except possibly the
+		// last one.
+		end = (end + 7) &^ 7
+		if end > uint64(len(b)) {
+			end = uint64(len(b))
+		}
+	}
+	return tag, b[8 : 8+n], b[end:], nil
+}
+
+// variable decodes a top-level element.
+func (dec *decoder) variable(typ uint32, data []byte) (string, interface{}, error) {
+	switch typ {
+	case miCOMPRESSED:
+		z, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", nil, ErrInvalidFile
+		}
+		inflated, err := ioutil.ReadAll(z)
+		if err != nil {
+			return "", nil, ErrInvalidFile
+		}
+		typ, data, _, err := dec.element(inflated)
+		if err != nil {
+			return "", nil, err
+		}
+		return dec.variable(typ, data)
+	case miMATRIX:
+		return dec.matrix(data)
+	}
+	return "", nil, ErrInvalidFile
+}
+
+// matrix decodes the data of a miMATRIX element.
+func (dec *decoder) matrix(data []byte) (string, interface{}, error) {
+	if len(data) == 0 {
+		// Empty items of cell arrays and structs.
+		d, err := array.NewDense(array.Shape{0, 0}, array.DefaultAttributes)
+		return "", d, err
+	}
+	typ, flags, data, err := dec.element(data)
+	if err != nil || typ != miUINT32 || len(flags) != 8 {
+		return "", nil, ErrInvalidFile
+	}
+	typ, dims, data, err := dec.element(data)
+	if err != nil || typ != miINT32 {
+		return "", nil, ErrInvalidFile
+	}
+	shape, err := dec.ints(dims)
+	if err != nil {
+		return "", nil, err
+	}
+	typ, rawName, data, err := dec.element(data)
+	if err != nil || (typ != miINT8 && typ != miUINT8) {
+		return "", nil, ErrInvalidFile
+	}
+	name := string(rawName)
+	class := dec.order.Uint32(flags) & 0xff
+	attrs := dec.order.Uint32(flags) >> 8 & 0xff
+	unsupported := func() (string, interface{}, error) {
+		return "", nil, fmt.Errorf("variable %q: %w", name, ErrUnsupported)
+	}
+	if attrs&flagComplex != 0 {
+		return unsupported()
+	}
+	var value interface{}
+	switch {
+	case class >= mxDOUBLE && class <= mxUINT64:
+		value, err = dec.numeric(shape, data)
+	case class == mxCHAR:
+		if len(shape) != 2 || shape[0] > 1 {
+			return unsupported()
+		}
+		value, err = dec.text(data)
+	case class == mxCELL:
+		value, err = dec.cell(shape, data)
+	case class == mxSTRUCT:
+		if n, _ := shape.Size(); n != 1 {
+			return unsupported()
+		}
+		value, err = dec.structure(data)
+	default:
+		return unsupported()
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return name, value, nil
+}
+
+// ints decodes the dimensions of an array.
+func (dec *decoder) ints(data []byte) (array.Shape, error) {
+	if len(data)%4 != 0 {
+		return nil, ErrInvalidFile
+	}
+	shape := make(array.Shape, len(data)/4)
+	for i := range shape {
+		shape[i] = int(int32(dec.order.Uint32(data[4*i:])))
+	}
+	if err := shape.Validate(); err != nil {
+		return nil, ErrInvalidFile
+	}
+	if _, err := shape.Size(); err != nil {
+		return nil, ErrInvalidFile
+	}
+	return shape, nil
+}
+
+// numeric decodes the real part of a numeric array, whose items may
+// be stored in any numeric type.
+func (dec *decoder) numeric(shape array.Shape, data []byte) (*array.Dense, error) {
+	typ, real, _, err := dec.element(data)
+	if err != nil {
+		return nil, err
+	}
+	values, err := dec.values(typ, real)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := shape.Size(); n != len(values) {
+		return nil, ErrInvalidFile
+	}
+	d, err := array.NewDense(shape, array.DefaultAttributes)
+	if err != nil {
+		return nil, ErrInvalidFile
+	}
+	copy(d.Data, values)
+	return d, nil
+}
+
+// values converts the items of a numeric element to float64.
+func (dec *decoder) values(typ uint32, data []byte) ([]float64, error) {
+	size := map[uint32]int{
+		miINT8: 1, miUINT8: 1, miINT16: 2, miUINT16: 2, miINT32: 4,
+		miUINT32: 4, miSINGLE: 4, miDOUBLE: 8, miINT64: 8, miUINT64: 8,
+	}[typ]
+	if size == 0 || len(data)%size != 0 {
+		return nil, ErrInvalidFile
+	}
+	values := make([]float64, len(data)/size)
+	for i := range values {
+		b := data[size*i:]
+		switch typ {
+		case miINT8:
+			values[i] = float64(int8(b[0]))
+		case miUINT8:
+			values[i] = float64(b[0])
+		case miINT16:
+			values[i] = float64(int16(dec.order.Uint16(b)))
+		case miUINT16:
+			values[i] = float64(dec.order.Uint16(b))
+		case miINT32:
+			values[i] = float64(int32(dec.order.Uint32(b)))
+		case miUINT32:
+			values[i] = float64(dec.order.Uint32(b))
+		case miSINGLE:
+			values[i] = float64(math.Float32frombits(dec.order.Uint32(b)))
+		case miDOUBLE:
+			values[i] = math.Float64frombits(dec.order.Uint64(b))
+		case miINT64:
+			values[i] = float64(int64(dec.order.Uint64(b)))
+		case miUINT64:
+			values[i] = float64(dec.order.Uint64(b))
+		}
+	}
+	return values, nil
+}
+
+// text decodes a character row vector.
+func (dec *decoder) text(data []byte) (string, error) {
+	typ, chars, _, err := dec.element(data)
+	if err != nil {
+		return "", err
+	}
+	switch typ {
+	case miUTF8:
+		return string(chars), nil
+	case miUINT16, miUTF16:
+		if len(chars)%2 != 0 {
+			return "", ErrInvalidFile
+		}
+		units := make([]uint16, len(chars)/2)
+		for i := range units {
+			units[i] = dec.order.Uint16(chars[2*i:])
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	values, err := dec.values(typ, chars)
+	if err != nil {
+		return "", err
+	}
+	runes := make([]rune, len(values))
+	for i, v := range values {
+		runes[i] = rune(v)
+	}
+	return string(runes), nil
+}
+
+// cell decodes the items of a cell array.
+func (dec *decoder) cell(shape array.Shape, data []byte) (*Cell, error) {
+	// Every item takes at least the eight bytes of its tag.
+	if n, _ := shape.Size(); n > len(data)/8 {
+		return nil, ErrInvalidFile
+	}
+	c, _ := NewCell(shape)
+	for i := range c.Items {
+		typ, item, rest, err := dec.element(data)
+		if err != nil || typ != miMATRIX {
+			return nil, ErrInvalidFile
+		}
+		data = rest
+		if _, c.Items[i], err = dec.matrix(item); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// structure decodes the fields of a scalar struct.
+func (dec *decoder) structure(data []byte) (Struct, error) {
+	typ, length, data, err := dec.element(data)
+	if err != nil || typ != miINT32 || len(length) != 4 {
+		return nil, ErrInvalidFile
+	}
+	n := int(dec.order.Uint32(length))
+	typ, names, data, err := dec.element(data)
+	if err != nil || (typ != miINT8 && typ != miUINT8) || n <= 0 || len(names)%n != 0 {
+		return nil, ErrInvalidFile
+	}
+	s := make(Struct)
+	for i := 0; i < len(names); i += n {
+		typ, field, rest, err := dec.element(data)
+		if err != nil || typ != miMATRIX {
+			return nil, ErrInvalidFile
+		}
+		data = rest
+		name := strings.TrimRight(string(names[i:i+n]), "\x00")
+		if _, s[name], err = dec.matrix(field); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// encoder encodes the elements of a little-endian MAT-file.
+type encoder struct {
+	buf bytes.Buffer
+}
+
+// WriteMAT writes variables to a little-endian MATLAB Level 5 MAT-file,
+// compressing every variable if compress is true.  Variables are
+// written in increasing order of name.
+func WriteMAT(w io.Writer, vars map[string]interface{}, compress bool) error {
+	header := make([]byte, headerLength)
+	copy(header, headerText)
+	for i := len(headerText); i < 116; i++ {
+		header[i] = ' '
+	}
+	binary.LittleEndian.PutUint16(header[124:], 0x0100)
+	copy(header[126:], "IM")
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	for _, name := range sortedNames(vars) {
+		var enc encoder
+		if err := enc.matrix(name, vars[name]); err != nil {
+			return fmt.Errorf("variable %q: %w", name, err)
+		}
+		element := enc.buf.Bytes()
+		if compress {
+			var z bytes.Buffer
+			zw := zlib.NewWriter(&z)
+			zw.Write(element)
+			if err := zw.Close(); err != nil {
+				return err
+			}
+			var tag [8]byte
+			binary.LittleEndian.PutUint32(tag[:], miCOMPRESSED)
+			binary.LittleEndian.PutUint32(tag[4:], uint32(z.Len()))
+			element = append(tag[:], z.Bytes()...)
+		}
+		if _, err := w.Write(element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// element appends an element with the given type and data, padded to
+// eight bytes.
+func (enc *encoder) element(typ uint32, data []byte) {
+	var tag [8]byte
+	binary.LittleEndian.PutUint32(tag[:], typ)
+	binary.LittleEndian.PutUint32(tag[4:], uint32(len(data)))
+	enc.buf.Write(tag[:])
+	enc.buf.Write(data)
+	for enc.buf.Len()%8 != 0 {
+		enc.buf.WriteByte(0)
+	}
+}
+
+// int32s appends an element of int32 values.
+func (enc *encoder) int32s(values ...int) {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], uint32(int32(v)))
+	}
+	enc.element(miINT32, data)
+}
+
+// matrix appends a miMATRIX element holding value.
+func (enc *encoder) matrix(name string, value interface{}) error {
+	var inner encoder
+	if err := inner.array(name, value); err != nil {
+		return err
+	}
+	enc.element(miMATRIX, inner.buf.Bytes())
+	return nil
+}
+
+// array appends the subelements of a miMATRIX element.
+func (enc *encoder) array(name string, value interface{}) error {
+	header := func(class int, shape array.Shape) {
+		flags := make([]byte, 8)
+		binary.LittleEndian.PutUint32(flags, uint32(class))
+		enc.element(miUINT32, flags)
+		enc.int32s(shape...)
+		enc.element(miINT8, []byte(name))
+	}
+	switch v := value.(type) {
+	case *array.Dense:
+		header(mxDOUBLE, matlabShape(v.Shape))
+		values := columnMajor(v)
+		data := make([]byte, 8*len(values))
+		for i, x := range values {
+			binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(x))
+		}
+		enc.element(miDOUBLE, data)
+	case string:
+		units := utf16.Encode([]rune(v))
+		shape := array.Shape{1, len(units)}
+		if len(units) == 0 {
+			shape = array.Shape{0, 0}
+		}
+		header(mxCHAR, shape)
+		data := make([]byte, 2*len(units))
+		for i, u := range units {
+			binary.LittleEndian.PutUint16(data[2*i:], u)
+		}
+		enc.element(miUINT16, data)
+	case Struct:
+		header(mxSTRUCT, array.Shape{1, 1})
+		fields := sortedNames(v)
+		length := 32
+		for _, f := range fields {
+			if len(f) >= length {
+				length = len(f) + 1
+			}
+		}
+		enc.int32s(length)
+		names := make([]byte, length*len(fields))
+		for i, f := range fields {
+			copy(names[i*length:], f)
+		}
+		enc.element(miINT8, names)
+		for _, f := range fields {
+			if err := enc.matrix("", v[f]); err != nil {
+				return err
+			}
+		}
+	case *Cell:
+		if n, err := v.Shape.Size(); err != nil || n != len(v.Items) {
+			return array.ErrInvalidValuesLength
+		}
+		header(mxCELL, matlabShape(v.Shape))
+		for _, item := range v.Items {
+			if item == nil {
+				// An empty matrix.
+				enc.element(miMATRIX, nil)
+				continue
+			}
+			if err := enc.matrix("", item); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrUnsupported
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Package matlab reads and writes the variables of MATLAB Level 5
+// MAT-files and of Octave text files.
+//
+// Variables are represented by the following Go values:
+//
+//	*array.Dense  numeric and logical arrays, logical items being 0 or 1
+//	string        character row vectors
+//	Struct        scalar structs
+//	*Cell         cell arrays
+//
+// MATLAB stores arrays in column-major order, so numeric arrays are read
+// into arrays with the ColumnMajorLayout attribute without reordering
+// their items.  Arrays always have at least two dimensions in MATLAB:
+// zero-dimensional arrays are written as 1×1 matrices, and
+// one-dimensional ones as row vectors.
+package matlab
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+var (
+	// ErrInvalidFile is returned when reading data that does not follow
+	// the file format.
+	ErrInvalidFile = errors.New("invalid file")
+
+	// ErrUnsupported is returned when reading or writing a variable that
+	// has no representation, such as complex or sparse arrays, objects,
+	// struct arrays and character matrices.
+	ErrUnsupported = errors.New("unsupported variable")
+)
+
+// Struct is a scalar struct, mapping field names to values.
+type Struct map[string]interface{}
+
+// Cell is a cell array, whose items are stored in column-major order.
+type Cell struct {
+	Shape array.Shape
+	Items []interface{}
+}
+
+// NewCell returns a cell array with the given shape whose items are
+// nil.
+func NewCell(shape array.Shape) (*Cell, error) {
+	n, err := shape.Size()
+	if err != nil {
+		return nil, err
+	}
+	return &Cell{Shape: shape, Items: make([]interface{}, n)}, nil
+}
+
+// matlabShape returns the shape of an array as stored by MATLAB, with
+// at least two dimensions.
+func matlabShape(shape array.Shape) array.Shape {
+	switch len(shape) {
+	case 0:
+		return array.Shape{1, 1}
+	case 1:
+		return array.Shape{1, shape[0]}
+	}
+	return shape
+}
+
+// columnMajor returns the items of d in column-major order.
+func columnMajor(d *array.Dense) []float64 {
+	if d.DType.Native() && d.IsContiguous(array.ColumnMajorLayout) &&
+		len(d.Data) == d.Size() {
+		return d.Data
+	}
+	c, _ := array.NewDenseWithValues(d.Shape, d.Values(), array.DefaultAttributes)
+	return c.Data
+}
+
+// sortedNames returns the keys of m in increasing order, so that files
+// are written deterministically.
+func sortedNames(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
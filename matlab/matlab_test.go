@@ -0,0 +1,245 @@
+package matlab_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/matlab"
+)
+
+func variables(t *testing.T) map[string]interface{} {
+	t.Helper()
+	m, err := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{1, 2, 3, 4, 5, math.Inf(-1)}, array.DefaultAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cube, _ := array.NewDenseWithValues(array.Shape{2, 1, 2},
+		[]float64{1, 2, 3, 4}, array.DefaultAttributes)
+	x, _ := array.NewDenseWithValues(array.Shape{1, 1}, []float64{0.1},
+		array.DefaultAttributes)
+	c, _ := matlab.NewCell(array.Shape{1, 2})
+	c.Items[0] = x
+	c.Items[1] = "two"
+	return map[string]interface{}{
+		"m":     m,
+		"cube":  cube,
+		"title": "héllo, world",
+		"empty": "",
+		"s":     matlab.Struct{"x": x, "names": c},
+	}
+}
+
+func assertVariables(t *testing.T, want, got map[string]interface{}) {
+	t.Helper()
+	if !assert.Equal(t, len(want), len(got)) {
+		return
+	}
+	for name, w := range want {
+		assertValue(t, w, got[name], name)
+	}
+}
+
+func assertValue(t *testing.T, want, got interface{}, name string) {
+	t.Helper()
+	switch w := want.(type) {
+	case *array.Dense:
+		g, ok := got.(*array.Dense)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, w.Shape, g.Shape, name)
+			assert.Equal(t, w.Values(), g.Values(), name)
+		}
+	case matlab.Struct:
+		g, ok := got.(matlab.Struct)
+		if assert.True(t, ok, name) && assert.Equal(t, len(w), len(g), name) {
+			for field := range w {
+				assertValue(t, w[field], g[field], name+"."+field)
+			}
+		}
+	case *matlab.Cell:
+		g, ok := got.(*matlab.Cell)
+		if assert.True(t, ok, name) && assert.Equal(t, w.Shape, g.Shape, name) {
+			for i := range w.Items {
+				assertValue(t, w.Items[i], g.Items[i], name)
+			}
+		}
+	default:
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestMATRoundTrip(t *testing.T) {
+	vars := variables(t)
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		if !assert.Nil(t, matlab.WriteMAT(&buf, vars, compress)) {
+			continue
+		}
+		assert.True(t, strings.HasPrefix(buf.String(), "MATLAB 5.0 MAT-file"))
+		got, err := matlab.ReadMAT(&buf)
+		if assert.Nil(t, err) {
+			assertVariables(t, vars, got)
+		}
+	}
+
+	err := matlab.WriteMAT(&bytes.Buffer{}, map[string]interface{}{"x": 1}, false)
+	assert.True(t, errors.Is(err, matlab.ErrUnsupported))
+}
+
+func TestMATRoundTripComplex(t *testing.T) {
+	// Complex arrays are written as real arrays with a last axis of parts.
+	c, err := array.NewComplex(array.Shape{2, 2},
+		[]complex128{1 + 2i, 3 - 4i, -5, 6i}, array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	vars := map[string]interface{}{"c": c}
+	var buf bytes.Buffer
+	if !assert.Nil(t, matlab.WriteMAT(&buf, vars, false)) {
+		return
+	}
+	got, err := matlab.ReadMAT(&buf)
+	if assert.Nil(t, err) {
+		assertVariables(t, vars, got)
+	}
+}
+
+func TestMATRoundTripStrides(t *testing.T) {
+	// The attributes claim a column-major layout, but the strides are
+	// row-major.
+	d, err := array.NewDenseWithStrides(array.Shape{2, 3}, array.Strides{24, 8},
+		array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	copy(d.Data, []float64{1, 2, 3, 4, 5, 6})
+	vars := map[string]interface{}{"d": d}
+	var buf bytes.Buffer
+	if !assert.Nil(t, matlab.WriteMAT(&buf, vars, false)) {
+		return
+	}
+	got, err := matlab.ReadMAT(&buf)
+	if assert.Nil(t, err) {
+		assertVariables(t, vars, got)
+	}
+}
+
+// element encodes a big-endian data element.
+func element(typ uint32, data []byte) []byte {
+	b := make([]byte, 8, 8+len(data)+7)
+	binary.BigEndian.PutUint32(b, typ)
+	binary.BigEndian.PutUint32(b[4:], uint32(len(data)))
+	b = append(b, data...)
+	for len(b)%8 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func TestReadMATBigEndian(t *testing.T) {
+	// A 2×2 int16 matrix, stored in int8 as MATLAB does for small items,
+	// with its name in a small data element.
+	header := make([]byte, 128)
+	copy(header, "MATLAB 5.0 MAT-file")
+	binary.BigEndian.PutUint16(header[124:], 0x0100)
+	copy(header[126:], "MI")
+	flags := []byte{0, 0, 0, 10, 0, 0, 0, 0}
+	dims := []byte{0, 0, 0, 2, 0, 0, 0, 2}
+	name := []byte{0, 2, 0, 1, 'a', 'b', 0, 0}
+	data := element(1, []byte{1, 0xff, 3, 4})
+	body := append(element(6, flags), element(5, dims)...)
+	body = append(append(body, name...), data...)
+	file := append(header, element(14, body)...)
+
+	vars, err := matlab.ReadMAT(bytes.NewReader(file))
+	if !assert.Nil(t, err) {
+		return
+	}
+	d, ok := vars["ab"].(*array.Dense)
+	if assert.True(t, ok) {
+		assert.Equal(t, array.Shape{2, 2}, d.Shape)
+		assert.Equal(t, []float64{1, 3, -1, 4}, d.Values())
+	}
+
+	// Complex arrays are not supported.
+	flags[2] = 0x08
+	body = append(element(6, flags), body[16:]...)
+	_, err = matlab.ReadMAT(bytes.NewReader(append(header, element(14, body)...)))
+	assert.True(t, errors.Is(err, matlab.ErrUnsupported))
+
+	for _, bad := range [][]byte{
+		file[:100],
+		file[:140],
+		append(header, element(14, body[:20])...),
+	} {
+		_, err := matlab.ReadMAT(bytes.NewReader(bad))
+		assert.NotNil(t, err)
+	}
+}
+
+func TestOctaveRoundTrip(t *testing.T) {
+	vars := variables(t)
+	vars["lines"] = "two\nlines\n"
+	var buf bytes.Buffer
+	if !assert.Nil(t, matlab.WriteOctave(&buf, vars)) {
+		return
+	}
+	assert.Contains(t, buf.String(),
+		"# name: m\n# type: matrix\n# rows: 2\n# columns: 3\n 1 2 3\n 4 5 -Inf\n")
+	got, err := matlab.ReadOctave(&buf)
+	if assert.Nil(t, err) {
+		assertVariables(t, vars, got)
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	vars := variables(t)
+	var mat, octave bytes.Buffer
+	if !assert.Nil(t, matlab.WriteMAT(&mat, vars, false)) ||
+		!assert.Nil(t, matlab.WriteOctave(&octave, vars)) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := matlab.ReadMATContext(ctx, &mat)
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = matlab.ReadOctaveContext(ctx, &octave)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestReadOctave(t *testing.T) {
+	text := `# Created by Octave 6.4.0
+# name: flag
+# type: bool
+1
+
+
+# name: v
+# type: scalar
+NaN
+
+
+# name: z
+# type: complex scalar
+(1,2)
+`
+	_, err := matlab.ReadOctave(strings.NewReader(text))
+	assert.True(t, errors.Is(err, matlab.ErrUnsupported))
+
+	vars, err := matlab.ReadOctave(strings.NewReader(text[:strings.Index(text, "# name: z")]))
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1}, vars["flag"].(*array.Dense).Values())
+		assert.True(t, math.IsNaN(vars["v"].(*array.Dense).Values()[0]))
+	}
+
+	_, err = matlab.ReadOctave(strings.NewReader("# name: m\n# type: matrix\n# rows: 2\n# columns: 2\n1 2\n"))
+	assert.True(t, errors.Is(err, matlab.ErrInvalidFile))
+}
@@ -0,0 +1,412 @@
+package matlab
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/ctxio"
+)
+
+// cellElement is the name of the items of cell arrays in Octave text
+// files.
+const cellElement = "<cell-element>"
+
+// octaveReader parses the lines of an Octave text file.
+type octaveReader struct {
+	lines []string
+	pos   int
+}
+
+// ReadOctave reads the variables of a file written by Octave's
+// save -text.  Numeric and logical scalars are read as 1×1 arrays.
+func ReadOctave(r io.Reader) (map[string]interface{}, error) {
+	return ReadOctaveContext(context.Background(), r)
+}
+
+// ReadOctaveContext is like ReadOctave, but stops reading and returns
+// an error wrapping the error of ctx once it is done.
+func ReadOctaveContext(ctx context.Context, r io.Reader) (map[string]interface{}, error) {
+	vars, err := readOctave(ctxio.NewReader(ctx, r))
+	if err != nil {
+		return nil, ctxio.Canceled(ctx, "read Octave file", err)
+	}
+	return vars, nil
+}
+
+func readOctave(r io.Reader) (map[string]interface{}, error) {
+	o := &octaveReader{}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, math.MaxInt32)
+	for s.Scan() {
+		o.lines = append(o.lines, strings.TrimRight(s.Text(), "\r"))
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	vars := make(map[string]interface{})
+	for {
+		// Skip blank lines and comments such as the creation notice.
+		for o.pos < len(o.lines) && !strings.HasPrefix(o.lines[o.pos], "# name:") {
+			if line := strings.TrimSpace(o.lines[o.pos]); line != "" && line[0] != '#' {
+				return nil, o.errorf("unexpected data")
+			}
+			o.pos++
+		}
+		if o.pos == len(o.lines) {
+			return vars, nil
+		}
+		name, value, err := o.variable()
+		if err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+}
+
+// errorf returns an error wrapping ErrInvalidFile at the current line.
+func (o *octaveReader) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("line %d: %s: %w", o.pos+1, fmt.Sprintf(format, a...),
+		ErrInvalidFile)
+}
+
+// header returns the value of the next header line, which must have the
+// given key.
+func (o *octaveReader) header(key string) (string, error) {
+	for o.pos < len(o.lines) && strings.TrimSpace(o.lines[o.pos]) == "" {
+		o.pos++
+	}
+	if o.pos == len(o.lines) {
+		return "", o.errorf("missing %s", key)
+	}
+	prefix := "# " + key + ":"
+	line := o.lines[o.pos]
+	if !strings.HasPrefix(line, prefix) {
+		return "", o.errorf("missing %s", key)
+	}
+	o.pos++
+	return strings.TrimSpace(line[len(prefix):]), nil
+}
+
+// count returns the non-negative integer value of a header line.
+func (o *octaveReader) count(key string) (int, error) {
+	value, err := o.header(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, o.errorf("invalid %s %q", key, value)
+	}
+	return n, nil
+}
+
+// numbers returns the next n numbers, which may span several lines.
+func (o *octaveReader) numbers(n int) ([]float64, error) {
+	var values []float64
+	for len(values) < n {
+		if o.pos == len(o.lines) {
+			return nil, o.errorf("missing values")
+		}
+		for _, field := range strings.Fields(o.lines[o.pos]) {
+			v, err := parseOctaveFloat(field)
+			if err != nil || len(values) == n {
+				return nil, o.errorf("invalid value %q", field)
+			}
+			values = append(values, v)
+		}
+		o.pos++
+	}
+	return values, nil
+}
+
+// shape returns the shape given by either rows and columns, or ndims
+// followed by the dimensions.
+func (o *octaveReader) shape() (array.Shape, error) {
+	if o.pos < len(o.lines) && strings.HasPrefix(o.lines[o.pos], "# ndims:") {
+		ndims, err := o.count("ndims")
+		if err != nil {
+			return nil, err
+		}
+		dims, err := o.numbers(ndims)
+		if err != nil {
+			return nil, err
+		}
+		shape := make(array.Shape, ndims)
+		for i, d := range dims {
+			if d < 0 || d != math.Trunc(d) || d > math.MaxInt32 {
+				return nil, o.errorf("invalid dimension %v", d)
+			}
+			shape[i] = int(d)
+		}
+		return shape, nil
+	}
+	rows, err := o.count("rows")
+	if err != nil {
+		return nil, err
+	}
+	columns, err := o.count("columns")
+	if err != nil {
+		return nil, err
+	}
+	return array.Shape{rows, columns}, nil
+}
+
+// variable reads a name, a type and a value.
+func (o *octaveReader) variable() (string, interface{}, error) {
+	name, err := o.header("name")
+	if err != nil {
+		return "", nil, err
+	}
+	typ, err := o.header("type")
+	if err != nil {
+		return "", nil, err
+	}
+	var value interface{}
+	switch typ {
+	case "scalar", "bool":
+		value, err = o.matrix(array.Shape{1, 1}, false)
+	case "matrix", "bool matrix":
+		value, err = o.matrix(nil, true)
+	case "string", "sq_string":
+		value, err = o.text(name)
+	case "cell":
+		value, err = o.cell()
+	case "scalar struct":
+		value, err = o.structure()
+	default:
+		return "", nil, fmt.Errorf("variable %q of type %q: %w", name, typ,
+			ErrUnsupported)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return name, value, nil
+}
+
+// matrix reads a numeric array.  The items of matrices are written row
+// by row, and those of arrays with more dimensions in column-major
+// order.
+func (o *octaveReader) matrix(shape array.Shape, header bool) (*array.Dense, error) {
+	ndims := o.pos < len(o.lines) && strings.HasPrefix(o.lines[o.pos], "# ndims:")
+	if header {
+		var err error
+		if shape, err = o.shape(); err != nil {
+			return nil, err
+		}
+	}
+	n, err := shape.Size()
+	if err != nil {
+		return nil, o.errorf("invalid shape %v", shape)
+	}
+	values, err := o.numbers(n)
+	if err != nil {
+		return nil, err
+	}
+	if !ndims {
+		return array.NewDenseWithValues(shape, values, array.DefaultAttributes)
+	}
+	d, err := array.NewDense(shape, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	copy(d.Data, values)
+	return d, nil
+}
+
+// text reads a character row vector.
+func (o *octaveReader) text(name string) (string, error) {
+	elements, err := o.count("elements")
+	if err != nil {
+		return "", err
+	}
+	if elements == 0 {
+		return "", nil
+	}
+	if elements > 1 {
+		return "", fmt.Errorf("variable %q: %w", name, ErrUnsupported)
+	}
+	length, err := o.count("length")
+	if err != nil {
+		return "", err
+	}
+	// The text follows on the next line, and spans several lines when it
+	// holds line breaks.
+	var text string
+	for first := true; first || len(text) < length; first = false {
+		if o.pos == len(o.lines) {
+			return "", o.errorf("missing text")
+		}
+		if !first {
+			text += "\n"
+		}
+		text += o.lines[o.pos]
+		o.pos++
+	}
+	if len(text) != length {
+		return "", o.errorf("text of length %d, expected %d", len(text), length)
+	}
+	return text, nil
+}
+
+// cell reads a cell array.
+func (o *octaveReader) cell() (*Cell, error) {
+	shape, err := o.shape()
+	if err != nil {
+		return nil, err
+	}
+	// Every item takes at least two lines.
+	if n, err := shape.Size(); err != nil || n > (len(o.lines)-o.pos)/2 {
+		return nil, o.errorf("invalid shape %v", shape)
+	}
+	c, _ := NewCell(shape)
+	for i := range c.Items {
+		name, value, err := o.variable()
+		if err != nil {
+			return nil, err
+		}
+		if name != cellElement {
+			return nil, o.errorf("invalid cell element %q", name)
+		}
+		c.Items[i] = value
+	}
+	return c, nil
+}
+
+// structure reads a scalar struct.
+func (o *octaveReader) structure() (Struct, error) {
+	if _, err := o.shape(); err != nil {
+		return nil, err
+	}
+	length, err := o.count("length")
+	if err != nil {
+		return nil, err
+	}
+	s := make(Struct)
+	for i := 0; i < length; i++ {
+		name, value, err := o.variable()
+		if err != nil {
+			return nil, err
+		}
+		s[name] = value
+	}
+	return s, nil
+}
+
+// parseOctaveFloat parses a number, including Octave's spellings of
+// non-finite values.
+func parseOctaveFloat(s string) (float64, error) {
+	if s == "NA" {
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// formatOctaveFloat formats a number as Octave does, so that it is read
+// back exactly.
+func formatOctaveFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteOctave writes variables in the text format of Octave, readable by
+// its load function.  Variables are written in increasing order of name.
+func WriteOctave(w io.Writer, vars map[string]interface{}) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("# Created by github.com/jimmyskull/math\n")
+	for _, name := range sortedNames(vars) {
+		if err := writeOctaveVariable(b, name, vars[name]); err != nil {
+			return fmt.Errorf("variable %q: %w", name, err)
+		}
+	}
+	return b.Flush()
+}
+
+// writeOctaveVariable writes the name, type and value of a variable,
+// followed by blank lines.
+func writeOctaveVariable(b *bufio.Writer, name string, value interface{}) error {
+	fmt.Fprintf(b, "# name: %s\n", name)
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("# type: matrix\n# rows: 0\n# columns: 0\n")
+	case *array.Dense:
+		writeOctaveMatrix(b, v)
+	case string:
+		fmt.Fprintf(b, "# type: string\n# elements: 1\n# length: %d\n%s\n",
+			len(v), v)
+	case Struct:
+		fmt.Fprintf(b, "# type: scalar struct\n# ndims: 2\n 1 1\n# length: %d\n",
+			len(v))
+		for _, field := range sortedNames(v) {
+			if err := writeOctaveVariable(b, field, v[field]); err != nil {
+				return err
+			}
+		}
+	case *Cell:
+		if n, err := v.Shape.Size(); err != nil || n != len(v.Items) {
+			return array.ErrInvalidValuesLength
+		}
+		b.WriteString("# type: cell\n")
+		writeOctaveShape(b, matlabShape(v.Shape))
+		for _, item := range v.Items {
+			if err := writeOctaveVariable(b, cellElement, item); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrUnsupported
+	}
+	b.WriteString("\n\n")
+	return nil
+}
+
+// writeOctaveShape writes rows and columns for matrices, or the
+// dimensions of arrays with more dimensions.
+func writeOctaveShape(b *bufio.Writer, shape array.Shape) {
+	if len(shape) == 2 {
+		fmt.Fprintf(b, "# rows: %d\n# columns: %d\n", shape[0], shape[1])
+		return
+	}
+	fmt.Fprintf(b, "# ndims: %d\n", len(shape))
+	for _, d := range shape {
+		fmt.Fprintf(b, " %d", d)
+	}
+	b.WriteByte('\n')
+}
+
+// writeOctaveMatrix writes the type and items of a numeric array.
+func writeOctaveMatrix(b *bufio.Writer, d *array.Dense) {
+	if len(d.Shape) == 0 {
+		fmt.Fprintf(b, "# type: scalar\n%s\n", formatOctaveFloat(d.Values()[0]))
+		return
+	}
+	shape := matlabShape(d.Shape)
+	b.WriteString("# type: matrix\n")
+	writeOctaveShape(b, shape)
+	if len(shape) == 2 {
+		values := d.Values()
+		for i := 0; i < shape[0]; i++ {
+			for j := 0; j < shape[1]; j++ {
+				b.WriteByte(' ')
+				b.WriteString(formatOctaveFloat(values[i*shape[1]+j]))
+			}
+			b.WriteByte('\n')
+		}
+		return
+	}
+	for _, v := range columnMajor(d) {
+		b.WriteByte(' ')
+		b.WriteString(formatOctaveFloat(v))
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,30 @@
+package ndimage
+
+import "errors"
+
+var (
+	// ErrDimensionMismatch is returned when weights, a structuring
+	// element or per-axis parameters do not match the dimensions of an
+	// array.
+	ErrDimensionMismatch = errors.New(
+		"parameters do not match the dimensions of the array")
+
+	// ErrInvalidSize is returned when a filter has no items.
+	ErrInvalidSize = errors.New("filter size must be positive")
+
+	// ErrInvalidSigma is returned when the standard deviation of a
+	// Gaussian filter is negative.
+	ErrInvalidSigma = errors.New("standard deviation must be non-negative")
+
+	// ErrInvalidOrder is returned when the order of spline interpolation
+	// is outside [0, 5].
+	ErrInvalidOrder = errors.New("spline order must be between 0 and 5")
+
+	// ErrInvalidZoom is returned when a zoom factor is negative or not
+	// finite.
+	ErrInvalidZoom = errors.New("zoom factors must be finite and non-negative")
+
+	// ErrInvalidAxes is returned when the axes of a rotation are not two
+	// distinct axes of the array.
+	ErrInvalidAxes = errors.New("rotation axes must be distinct")
+)
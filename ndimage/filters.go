@@ -0,0 +1,236 @@
+package ndimage
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Correlate returns the correlation of d with weights, which must have
+// as many dimensions as d:
+//
+//	out[i] = Σ_j weights[j] d[i + j - c]
+//
+// where c, the centre of weights, is at half the length of every axis.
+// If opts is nil, DefaultOptions is used.
+func Correlate(d, weights *array.Dense, opts *Options) (*array.Dense, error) {
+	return correlate(d, weights, false, opts)
+}
+
+// Convolve returns the convolution of d with weights, which must have as
+// many dimensions as d:
+//
+//	out[i] = Σ_j weights[j] d[i - j + c]
+//
+// where c, the centre of weights, is at half the length of every axis.
+// If opts is nil, DefaultOptions is used.
+func Convolve(d, weights *array.Dense, opts *Options) (*array.Dense, error) {
+	return correlate(d, weights, true, opts)
+}
+
+func correlate(d, weights *array.Dense, flip bool, opts *Options) (*array.Dense, error) {
+	if len(weights.Shape) != len(d.Shape) {
+		return nil, ErrDimensionMismatch
+	}
+	if weights.Size() == 0 {
+		return nil, ErrInvalidSize
+	}
+	w := weights.Values()
+	var kept []float64
+	offsets := footprint(weights.Shape, func(pos int) bool {
+		if w[pos] == 0 {
+			return false
+		}
+		kept = append(kept, w[pos])
+		return true
+	})
+	if flip {
+		for _, offset := range offsets {
+			for k := range offset {
+				offset[k] = -offset[k]
+			}
+		}
+	}
+	return neighborhood(d, offsets, options(opts), func(window []float64) float64 {
+		sum := 0.0
+		for i, v := range window {
+			sum += kept[i] * v
+		}
+		return sum
+	})
+}
+
+// Correlate1D returns the correlation of d with weights along axis,
+// defined as in Correlate.  Negative axes count from the last dimension.
+// If opts is nil, DefaultOptions is used.
+func Correlate1D(d *array.Dense, weights []float64, axis int, opts *Options) (*array.Dense, error) {
+	return correlate1D(d, weights, len(weights)/2, axis, options(opts))
+}
+
+// Convolve1D returns the convolution of d with weights along axis,
+// defined as in Convolve.  Negative axes count from the last dimension.
+// If opts is nil, DefaultOptions is used.
+func Convolve1D(d *array.Dense, weights []float64, axis int, opts *Options) (*array.Dense, error) {
+	// A convolution is a correlation with reversed weights, whose centre
+	// moves accordingly.
+	n := len(weights)
+	reversed := make([]float64, n)
+	for i, w := range weights {
+		reversed[n-1-i] = w
+	}
+	return correlate1D(d, reversed, n-1-n/2, axis, options(opts))
+}
+
+// correlate1D correlates the lanes of d along axis with weights centred
+// at c.
+func correlate1D(d *array.Dense, weights []float64, c, axis int, opts *Options) (*array.Dense, error) {
+	if len(weights) == 0 {
+		return nil, ErrInvalidSize
+	}
+	n, err := axisLength(d, axis)
+	if err != nil {
+		return nil, err
+	}
+	return array.ApplyAlongAxis(d, axis, n, func(dst, src []float64) {
+		for i := range dst {
+			sum := 0.0
+			for j, w := range weights {
+				v := opts.Cval
+				if at, ok := opts.Mode.index(i+j-c, n); ok {
+					v = src[at]
+				}
+				sum += w * v
+			}
+			dst[i] = sum
+		}
+	})
+}
+
+// separable applies the one-dimensional weights along every axis of d.
+func separable(d *array.Dense, weights []float64, opts *Options) (*array.Dense, error) {
+	out := d
+	for axis := range d.Shape {
+		var err error
+		if out, err = correlate1D(out, weights, len(weights)/2, axis, opts); err != nil {
+			return nil, err
+		}
+	}
+	if out == d {
+		// A zero-dimensional array is left as it is.
+		return array.NewDenseWithValues(array.Shape{}, d.Values(), array.DefaultAttributes)
+	}
+	return out, nil
+}
+
+// gaussianWeights returns the normalized weights of a Gaussian of
+// standard deviation sigma, truncated at four standard deviations.
+func gaussianWeights(sigma float64) ([]float64, error) {
+	if !(sigma >= 0) {
+		return nil, ErrInvalidSigma
+	}
+	radius := int(4*sigma + 0.5)
+	weights := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range weights {
+		x := float64(i - radius)
+		weights[i] = math.Exp(-x * x / (2 * sigma * sigma))
+		sum += weights[i]
+	}
+	if radius == 0 {
+		weights[0], sum = 1, 1
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+	return weights, nil
+}
+
+// GaussianFilter returns d smoothed along every axis by a Gaussian of
+// standard deviation sigma, truncated at four standard deviations.  If
+// opts is nil, DefaultOptions is used.
+func GaussianFilter(d *array.Dense, sigma float64, opts *Options) (*array.Dense, error) {
+	weights, err := gaussianWeights(sigma)
+	if err != nil {
+		return nil, err
+	}
+	return separable(d, weights, options(opts))
+}
+
+// GaussianFilter1D returns d smoothed along axis by a Gaussian of
+// standard deviation sigma, truncated at four standard deviations.
+// Negative axes count from the last dimension.  If opts is nil,
+// DefaultOptions is used.
+func GaussianFilter1D(d *array.Dense, sigma float64, axis int, opts *Options) (*array.Dense, error) {
+	weights, err := gaussianWeights(sigma)
+	if err != nil {
+		return nil, err
+	}
+	return correlate1D(d, weights, len(weights)/2, axis, options(opts))
+}
+
+// UniformFilter returns the mean of the box of the given size around
+// every item of d.  If opts is nil, DefaultOptions is used.
+func UniformFilter(d *array.Dense, size int, opts *Options) (*array.Dense, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	weights := make([]float64, size)
+	for i := range weights {
+		weights[i] = 1 / float64(size)
+	}
+	return separable(d, weights, options(opts))
+}
+
+// MedianFilter returns the median of the box of the given size around
+// every item of d.  For boxes with an even number of items, the greater
+// of the two middle items is taken.  If opts is nil, DefaultOptions is
+// used.
+func MedianFilter(d *array.Dense, size int, opts *Options) (*array.Dense, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return rankFilter(d, size, opts, func(sorted []float64) float64 {
+		return sorted[len(sorted)/2]
+	})
+}
+
+// rankFilter returns the result of fn applied to the sorted items of
+// the box of the given size around every item of d.
+func rankFilter(
+	d *array.Dense, size int, opts *Options, fn func(sorted []float64) float64,
+) (*array.Dense, error) {
+	offsets := box(len(d.Shape), size)
+	sorted := make([]float64, len(offsets))
+	return neighborhood(d, offsets, options(opts), func(window []float64) float64 {
+		copy(sorted, window)
+		sort.Float64s(sorted)
+		return fn(sorted)
+	})
+}
+
+// Sobel returns the derivative of d along axis estimated by the Sobel
+// operator: central differences along axis, smoothed by the weights
+// 1, 2, 1 along the other axes.  Negative axes count from the last
+// dimension.  If opts is nil, DefaultOptions is used.
+func Sobel(d *array.Dense, axis int, opts *Options) (*array.Dense, error) {
+	opts = options(opts)
+	if _, err := axisLength(d, axis); err != nil {
+		return nil, err
+	}
+	if axis < 0 {
+		axis += len(d.Shape)
+	}
+	out, err := correlate1D(d, []float64{-1, 0, 1}, 1, axis, opts)
+	if err != nil {
+		return nil, err
+	}
+	for k := range d.Shape {
+		if k != axis {
+			if out, err = correlate1D(out, []float64{1, 2, 1}, 1, k, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
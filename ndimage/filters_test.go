@@ -0,0 +1,108 @@
+package ndimage_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/ndimage"
+)
+
+func TestModes(t *testing.T) {
+	d := arraytest.Dense(t, array.Shape{4}, 1, 2, 3, 4)
+	// The weights pick the item two positions before.
+	weights := []float64{1, 0, 0, 0, 0}
+	cases := []struct {
+		mode ndimage.Mode
+		want []float64
+	}{
+		{ndimage.Reflect, []float64{2, 1, 1, 2}},
+		{ndimage.Constant, []float64{-1, -1, 1, 2}},
+		{ndimage.Nearest, []float64{1, 1, 1, 2}},
+		{ndimage.Wrap, []float64{3, 4, 1, 2}},
+	}
+	for _, c := range cases {
+		opts := &ndimage.Options{Mode: c.mode, Cval: -1}
+		out, err := ndimage.Correlate1D(d, weights, 0, opts)
+		if assert.Nil(t, err, c.mode.String()) {
+			assert.Equal(t, c.want, out.Values(), c.mode.String())
+		}
+	}
+}
+
+func TestCorrelateAndConvolve(t *testing.T) {
+	d := arraytest.Dense(t, array.Shape{5}, 1, 2, 3, 4, 5)
+	opts := &ndimage.Options{Mode: ndimage.Constant}
+	out, err := ndimage.Convolve1D(d, []float64{1, 2, 3}, 0, opts)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{4, 10, 16, 22, 22}, out.Values())
+	}
+	out, err = ndimage.Correlate1D(d, []float64{1, 1, 1}, -1, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{4, 6, 9, 12, 14}, out.Values())
+	}
+	_, err = ndimage.Correlate1D(d, []float64{1}, 1, nil)
+	assert.NotNil(t, err)
+
+	// Along the second axis of a matrix, and in two dimensions.
+	m := arraytest.Dense(t, array.Shape{2, 3}, 1, 2, 3, 4, 5, 6)
+	w := arraytest.Dense(t, array.Shape{1, 3}, 1, 2, 3)
+	corr, err := ndimage.Correlate(m, w, opts)
+	if assert.Nil(t, err) {
+		want, _ := ndimage.Correlate1D(m, []float64{1, 2, 3}, 1, opts)
+		assert.Equal(t, want.Values(), corr.Values())
+		assert.Equal(t, []float64{8, 14, 8, 23, 32, 17}, corr.Values())
+	}
+	conv, err := ndimage.Convolve(m, w, opts)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{4, 10, 12, 13, 28, 27}, conv.Values())
+	}
+	_, err = ndimage.Correlate(m, arraytest.Dense(t, array.Shape{3}, 1, 2, 3), nil)
+	assert.Equal(t, ndimage.ErrDimensionMismatch, err)
+}
+
+func TestSmoothingFilters(t *testing.T) {
+	d, _ := array.NewDense(array.Shape{4, 5}, array.DefaultAttributes)
+	d.Fill(2, 0)
+	g, err := ndimage.GaussianFilter(d, 1.5, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, d.Values(), g.Values(), 1e-12)
+	}
+	impulse := arraytest.Dense(t, array.Shape{9}, 0, 0, 0, 0, 1, 0, 0, 0, 0)
+	g, err = ndimage.GaussianFilter1D(impulse, 1, 0, nil)
+	if assert.Nil(t, err) {
+		v := g.Values()
+		assert.InDelta(t, 1, v[0]+v[1]+v[2]+v[3]+v[4]+v[5]+v[6]+v[7]+v[8], 1e-12)
+		assert.InDelta(t, v[3], v[5], 1e-15)
+		assert.InDelta(t, math.Exp(-0.5), v[5]/v[4], 1e-12)
+	}
+	_, err = ndimage.GaussianFilter(d, -1, nil)
+	assert.Equal(t, ndimage.ErrInvalidSigma, err)
+
+	u, err := ndimage.UniformFilter(arraytest.Dense(t, array.Shape{4}, 3, 6, 9, 0), 3, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{4, 6, 5, 3}, u.Values(), 1e-12)
+	}
+	m, err := ndimage.MedianFilter(arraytest.Dense(t, array.Shape{5}, 1, 9, 2, 8, 3), 3, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 2, 8, 3, 3}, m.Values())
+	}
+	_, err = ndimage.MedianFilter(d, 0, nil)
+	assert.Equal(t, ndimage.ErrInvalidSize, err)
+}
+
+func TestSobel(t *testing.T) {
+	// A ramp along the columns.
+	d := arraytest.Dense(t, array.Shape{3, 4}, 0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3)
+	s, err := ndimage.Sobel(d, 1, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{4, 8, 8, 4, 4, 8, 8, 4, 4, 8, 8, 4}, s.Values())
+	}
+	s, err = ndimage.Sobel(d, -2, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, make([]float64, 12), s.Values())
+	}
+}
@@ -0,0 +1,395 @@
+package ndimage
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// splinePoles returns the poles of the recursive filter turning samples
+// into the coefficients of a B-spline of the given order.
+func splinePoles(order int) []float64 {
+	switch order {
+	case 2:
+		return []float64{math.Sqrt(8) - 3}
+	case 3:
+		return []float64{math.Sqrt(3) - 2}
+	case 4:
+		return []float64{
+			math.Sqrt(664-math.Sqrt(438976)) + math.Sqrt(304) - 19,
+			math.Sqrt(664+math.Sqrt(438976)) - math.Sqrt(304) - 19,
+		}
+	case 5:
+		return []float64{
+			math.Sqrt(67.5-math.Sqrt(4436.25)) + math.Sqrt(26.25) - 6.5,
+			math.Sqrt(67.5+math.Sqrt(4436.25)) - math.Sqrt(26.25) - 6.5,
+		}
+	}
+	return nil
+}
+
+// splineFilter turns the samples of c into B-spline coefficients in
+// place, assuming mirror-symmetric boundaries.
+func splineFilter(c []float64, poles []float64) {
+	n := len(c)
+	if n < 2 {
+		return
+	}
+	gain := 1.0
+	for _, z := range poles {
+		gain *= (1 - z) * (1 - 1/z)
+	}
+	for i := range c {
+		c[i] *= gain
+	}
+	for _, z := range poles {
+		// Causal initialization, truncated once the powers of the pole
+		// are negligible.
+		horizon := int(math.Ceil(math.Log(1e-15) / math.Log(math.Abs(z))))
+		if horizon < n {
+			sum, zn := c[0], z
+			for k := 1; k < horizon; k++ {
+				sum += zn * c[k]
+				zn *= z
+			}
+			c[0] = sum
+		} else {
+			zn := z
+			z2n := math.Pow(z, float64(n-1))
+			sum := c[0] + z2n*c[n-1]
+			z2n *= z2n / z
+			for k := 1; k < n-1; k++ {
+				sum += (zn + z2n) * c[k]
+				zn *= z
+				z2n /= z
+			}
+			c[0] = sum / (1 - zn*zn)
+		}
+		for k := 1; k < n; k++ {
+			c[k] += z * c[k-1]
+		}
+		c[n-1] = z / (z*z - 1) * (z*c[n-2] + c[n-1])
+		for k := n - 2; k >= 0; k-- {
+			c[k] = z * (c[k+1] - c[k])
+		}
+	}
+}
+
+// bspline returns the centred B-spline of the given order at x.
+func bspline(order int, x float64) float64 {
+	// Sum of truncated powers of the centred B-spline.
+	sum, binomial, factorial := 0.0, 1.0, 1.0
+	for k := 2; k <= order; k++ {
+		factorial *= float64(k)
+	}
+	for k := 0; k <= order+1; k++ {
+		if t := x + float64(order+1)/2 - float64(k); t > 0 {
+			term := binomial * math.Pow(t, float64(order))
+			if k%2 == 1 {
+				term = -term
+			}
+			sum += term
+		}
+		binomial = binomial * float64(order+1-k) / float64(k+1)
+	}
+	return sum / factorial
+}
+
+// mirror maps an index of an array of length n mirrored about its edge
+// items, the extension assumed by the spline coefficients.
+func mirror(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	period := 2 * (n - 1)
+	i %= period
+	if i < 0 {
+		i += period
+	}
+	if i >= n {
+		i = period - i
+	}
+	return i
+}
+
+// coordinate maps a coordinate beyond the borders of an axis of length
+// n to the coordinate it is interpolated at.  Coordinates between the
+// last and first items are interpolated between them when wrapping.
+func (m Mode) coordinate(x, n float64) float64 {
+	switch m {
+	case Reflect:
+		// Reflection about the outer edges of the items at the borders.
+		x = math.Mod(x+0.5, 2*n)
+		if x < 0 {
+			x += 2 * n
+		}
+		if x > n {
+			x = 2*n - x
+		}
+		x -= 0.5
+	case Wrap:
+		x = math.Mod(x, n)
+		if x < 0 {
+			x += n
+		}
+		return x
+	}
+	return math.Max(0, math.Min(x, n-1))
+}
+
+// transform returns an array of the given shape whose items are
+// interpolated from d at the coordinates set by coords for every index
+// of the result.
+func transform(
+	d *array.Dense, shape array.Shape, coords func(out []int, in []float64), opts *Options,
+) (*array.Dense, error) {
+	opts = options(opts)
+	order := opts.Order
+	if order < 0 || order > 5 {
+		return nil, ErrInvalidOrder
+	}
+	for _, n := range shape {
+		if n < 0 {
+			return nil, array.ErrInvalidShapeDim
+		}
+	}
+	c := d
+	if poles := splinePoles(order); poles != nil {
+		for axis, n := range d.Shape {
+			var err error
+			c, err = array.ApplyAlongAxis(c, axis, n, func(dst, src []float64) {
+				copy(dst, src)
+				splineFilter(dst, poles)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	coefficients := c.Values()
+	inShape := d.Shape
+	strides := rowMajor(inShape)
+	ndim := len(inShape)
+
+	size := 1
+	for _, n := range shape {
+		size *= n
+	}
+	values := make([]float64, size)
+	index := make([]int, len(shape))
+	x := make([]float64, ndim)
+	starts := make([]int, ndim)
+	weights := make([][]float64, ndim)
+	for k := range weights {
+		weights[k] = make([]float64, order+1)
+	}
+	taps := make([]int, ndim)
+	for pos := range values {
+		unravel(pos, shape, index)
+		coords(index, x)
+		outside := false
+		for k, xk := range x {
+			n := float64(inShape[k])
+			if xk < -1e-9 || xk > n-1+1e-9 {
+				outside = true
+				xk = opts.Mode.coordinate(xk, n)
+			}
+			// The taps of the spline around xk, and their weights.
+			starts[k] = int(math.Floor(xk - float64(order-1)/2))
+			for j := range weights[k] {
+				if order == 0 {
+					weights[k][j] = 1
+				} else {
+					weights[k][j] = bspline(order, xk-float64(starts[k]+j))
+				}
+			}
+		}
+		if (outside && opts.Mode == Constant) || len(coefficients) == 0 {
+			values[pos] = opts.Cval
+			continue
+		}
+		sum := 0.0
+		for k := range taps {
+			taps[k] = 0
+		}
+		for {
+			w, at := 1.0, 0
+			for k, j := range taps {
+				w *= weights[k][j]
+				i := starts[k] + j
+				if opts.Mode == Wrap {
+					i, _ = opts.Mode.index(i, inShape[k])
+				} else {
+					i = mirror(i, inShape[k])
+				}
+				at += i * strides[k]
+			}
+			sum += w * coefficients[at]
+			// Next combination of taps.
+			k := ndim - 1
+			for ; k >= 0; k-- {
+				if taps[k]++; taps[k] <= order {
+					break
+				}
+				taps[k] = 0
+			}
+			if k < 0 {
+				break
+			}
+		}
+		values[pos] = sum
+	}
+	return array.NewDenseWithValues(append(array.Shape{}, shape...), values,
+		array.DefaultAttributes)
+}
+
+// AffineTransform returns an array of the given shape whose item at
+// index o is interpolated from d at matrix·o + offset, using splines of
+// the order of the options.  Matrix must be a square matrix of the
+// dimension of d.  If offset is nil, it is zero, and if shape is nil,
+// the result has the shape of d.  Items mapped beyond the borders of d
+// are taken from its extension, or are the constant value of the
+// options.  If opts is nil, DefaultOptions is used.
+func AffineTransform(
+	d, matrix *array.Dense, offset []float64, shape array.Shape, opts *Options,
+) (*array.Dense, error) {
+	ndim := len(d.Shape)
+	if len(matrix.Shape) != 2 || matrix.Shape[0] != ndim || matrix.Shape[1] != ndim ||
+		(offset != nil && len(offset) != ndim) {
+		return nil, ErrDimensionMismatch
+	}
+	if shape == nil {
+		shape = d.Shape
+	}
+	if len(shape) != ndim {
+		return nil, ErrDimensionMismatch
+	}
+	m := matrix.Values()
+	return transform(d, shape, func(out []int, in []float64) {
+		for i := range in {
+			in[i] = 0
+			if offset != nil {
+				in[i] = offset[i]
+			}
+			for j, o := range out {
+				in[i] += m[i*ndim+j] * float64(o)
+			}
+		}
+	}, opts)
+}
+
+// perAxis returns the values of a parameter for every axis, repeating a
+// single value.
+func perAxis(values []float64, ndim int) ([]float64, error) {
+	if len(values) == 1 {
+		repeated := make([]float64, ndim)
+		for k := range repeated {
+			repeated[k] = values[0]
+		}
+		return repeated, nil
+	}
+	if len(values) != ndim {
+		return nil, ErrDimensionMismatch
+	}
+	return values, nil
+}
+
+// Shift returns d shifted by the given number of items along every
+// axis, interpolated with splines of the order of the options.  The
+// shift is given for every axis, or as a single value for all of them.
+// If opts is nil, DefaultOptions is used.
+func Shift(d *array.Dense, shift []float64, opts *Options) (*array.Dense, error) {
+	shift, err := perAxis(shift, len(d.Shape))
+	if err != nil {
+		return nil, err
+	}
+	return transform(d, d.Shape, func(out []int, in []float64) {
+		for k, o := range out {
+			in[k] = float64(o) - shift[k]
+		}
+	}, opts)
+}
+
+// Zoom returns d resized by the given factors along every axis,
+// interpolated with splines of the order of the options.  The length of
+// every axis is multiplied by its factor and rounded, and the items at
+// the corners of d are kept at the corners of the result.  The factors
+// are given for every axis, or as a single value for all of them.  If
+// opts is nil, DefaultOptions is used.
+func Zoom(d *array.Dense, zoom []float64, opts *Options) (*array.Dense, error) {
+	zoom, err := perAxis(zoom, len(d.Shape))
+	if err != nil {
+		return nil, err
+	}
+	shape := make(array.Shape, len(d.Shape))
+	scale := make([]float64, len(d.Shape))
+	for k, n := range d.Shape {
+		if !(zoom[k] >= 0) || math.IsInf(zoom[k], 0) {
+			return nil, ErrInvalidZoom
+		}
+		shape[k] = int(math.Round(float64(n) * zoom[k]))
+		if shape[k] > 1 {
+			scale[k] = float64(n-1) / float64(shape[k]-1)
+		}
+	}
+	return transform(d, shape, func(out []int, in []float64) {
+		for k, o := range out {
+			in[k] = float64(o) * scale[k]
+		}
+	}, opts)
+}
+
+// Rotate returns d rotated by angle degrees in the plane of two axes,
+// from the first axis toward the second, about the centre of the
+// plane, interpolated with splines of the order of the options.  A
+// rotation of 90 degrees in the plane of axes 0 and 1 turns the last
+// column of a matrix into its first row.  If reshape is true, the
+// result is enlarged to hold all of d, otherwise it has the shape of d.
+// Negative axes count from the last dimension.  If opts is nil,
+// DefaultOptions is used.
+func Rotate(
+	d *array.Dense, angle float64, axes [2]int, reshape bool, opts *Options,
+) (*array.Dense, error) {
+	ndim := len(d.Shape)
+	for i := range axes {
+		if _, err := axisLength(d, axes[i]); err != nil {
+			return nil, err
+		}
+		if axes[i] < 0 {
+			axes[i] += ndim
+		}
+	}
+	if axes[0] == axes[1] {
+		return nil, ErrInvalidAxes
+	}
+	theta := angle * math.Pi / 180
+	cos, sin := math.Cos(theta), math.Sin(theta)
+	// Exact values for right angles.
+	switch math.Mod(math.Mod(angle, 360)+360, 360) {
+	case 0:
+		cos, sin = 1, 0
+	case 90:
+		cos, sin = 0, 1
+	case 180:
+		cos, sin = -1, 0
+	case 270:
+		cos, sin = 0, -1
+	}
+	p, q := axes[0], axes[1]
+	shape := append(array.Shape{}, d.Shape...)
+	if reshape {
+		np, nq := float64(d.Shape[p]), float64(d.Shape[q])
+		shape[p] = int(math.Round(math.Abs(cos)*np + math.Abs(sin)*nq))
+		shape[q] = int(math.Round(math.Abs(sin)*np + math.Abs(cos)*nq))
+	}
+	inP, inQ := float64(d.Shape[p]-1)/2, float64(d.Shape[q]-1)/2
+	outP, outQ := float64(shape[p]-1)/2, float64(shape[q]-1)/2
+	return transform(d, shape, func(out []int, in []float64) {
+		for k, o := range out {
+			in[k] = float64(o)
+		}
+		u, v := float64(out[p])-outP, float64(out[q])-outQ
+		in[p] = cos*u + sin*v + inP
+		in[q] = -sin*u + cos*v + inQ
+	}, opts)
+}
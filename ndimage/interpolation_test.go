@@ -0,0 +1,101 @@
+package ndimage_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/ndimage"
+)
+
+func TestShift(t *testing.T) {
+	d := arraytest.Dense(t, array.Shape{2, 4}, 1, 5, 2, 7, 3, 0, 8, 4)
+	for order := 0; order <= 5; order++ {
+		opts := &ndimage.Options{Mode: ndimage.Constant, Cval: -1, Order: order}
+		// Splines interpolate the items themselves.
+		same, err := ndimage.Shift(d, []float64{0}, opts)
+		if assert.Nil(t, err, order) {
+			assert.InDeltaSlice(t, d.Values(), same.Values(), 1e-9, order)
+		}
+		moved, err := ndimage.Shift(d, []float64{0, 1}, opts)
+		if assert.Nil(t, err, order) {
+			assert.InDeltaSlice(t,
+				[]float64{-1, 1, 5, 2, -1, 3, 0, 8}, moved.Values(), 1e-9, order)
+		}
+	}
+
+	ramp := arraytest.Dense(t, array.Shape{4}, 0, 1, 2, 3)
+	half, err := ndimage.Shift(ramp, []float64{0.5},
+		&ndimage.Options{Mode: ndimage.Nearest, Order: 1})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, 0.5, 1.5, 2.5}, half.Values())
+	}
+	_, err = ndimage.Shift(ramp, []float64{1, 2}, nil)
+	assert.Equal(t, ndimage.ErrDimensionMismatch, err)
+	_, err = ndimage.Shift(ramp, []float64{1}, &ndimage.Options{Order: 6})
+	assert.Equal(t, ndimage.ErrInvalidOrder, err)
+}
+
+func TestZoom(t *testing.T) {
+	ramp := arraytest.Dense(t, array.Shape{2, 3}, 0, 1, 2, 10, 11, 12)
+	z, err := ndimage.Zoom(ramp, []float64{1, 5.0 / 3},
+		&ndimage.Options{Order: 1})
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 5}, z.Shape)
+		assert.InDeltaSlice(t,
+			[]float64{0, 0.5, 1, 1.5, 2, 10, 10.5, 11, 11.5, 12}, z.Values(), 1e-12)
+	}
+
+	// Cubic splines follow a smooth function between its samples, here
+	// one that is symmetric about the borders as the splines assume.
+	x, _ := array.Linspace(0, math.Pi, 9, true)
+	cosine, _ := array.Map(x, math.Cos)
+	z, err = ndimage.Zoom(cosine, []float64{33.0 / 9}, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{33}, z.Shape)
+		for i, v := range z.Values() {
+			assert.InDelta(t, math.Cos(math.Pi*float64(i)/32), v, 2e-3)
+		}
+	}
+	_, err = ndimage.Zoom(ramp, []float64{-1}, nil)
+	assert.Equal(t, ndimage.ErrInvalidZoom, err)
+}
+
+func TestRotate(t *testing.T) {
+	m := arraytest.Dense(t, array.Shape{2, 2}, 1, 2, 3, 4)
+	r, err := ndimage.Rotate(m, 90, [2]int{0, 1}, false, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{2, 4, 1, 3}, r.Values(), 1e-12)
+	}
+	r, err = ndimage.Rotate(m, -90, [2]int{1, 0}, false, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{2, 4, 1, 3}, r.Values(), 1e-12)
+	}
+
+	wide := arraytest.Dense(t, array.Shape{2, 3}, 1, 2, 3, 4, 5, 6)
+	r, err = ndimage.Rotate(wide, 270, [2]int{0, 1}, true, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{3, 2}, r.Shape)
+		assert.InDeltaSlice(t, []float64{4, 1, 5, 2, 6, 3}, r.Values(), 1e-12)
+	}
+
+	_, err = ndimage.Rotate(m, 45, [2]int{1, -1}, false, nil)
+	assert.Equal(t, ndimage.ErrInvalidAxes, err)
+	_, err = ndimage.Rotate(m, 45, [2]int{0, 2}, false, nil)
+	assert.NotNil(t, err)
+}
+
+func TestAffineTransform(t *testing.T) {
+	m := arraytest.Dense(t, array.Shape{2, 3}, 1, 2, 3, 4, 5, 6)
+	// Transposes the matrix.
+	swap := arraytest.Dense(t, array.Shape{2, 2}, 0, 1, 1, 0)
+	out, err := ndimage.AffineTransform(m, swap, nil, array.Shape{3, 2}, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{1, 4, 2, 5, 3, 6}, out.Values(), 1e-12)
+	}
+	_, err = ndimage.AffineTransform(m, swap, []float64{1}, nil, nil)
+	assert.Equal(t, ndimage.ErrDimensionMismatch, err)
+}
@@ -0,0 +1,211 @@
+// Package ndimage processes multi-dimensional images stored in arrays:
+// correlation and convolution, smoothing and edge filters, mathematical
+// morphology, labelling of connected components and geometric
+// transforms with spline interpolation.
+//
+// Filters read items beyond the borders of an array, which are
+// extended as selected by the Mode of the options:
+//
+//	Reflect   d c b a | a b c d | d c b a
+//	Constant  k k k k | a b c d | k k k k
+//	Nearest   a a a a | a b c d | d d d d
+//	Wrap      a b c d | a b c d | a b c d
+//
+// Results are new arrays with the shape of their input, unless noted
+// otherwise.
+package ndimage
+
+import (
+	"github.com/jimmyskull/math/array"
+)
+
+// Mode is the extension of an array beyond its borders.
+type Mode int
+
+// Modes of extension.
+const (
+	// Reflect mirrors the array about its edges, repeating the items at
+	// the edges.
+	Reflect Mode = iota
+	// Constant extends the array with the constant value of the options.
+	Constant
+	// Nearest repeats the items at the edges.
+	Nearest
+	// Wrap repeats the array periodically.
+	Wrap
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Reflect:
+		return "Reflect"
+	case Constant:
+		return "Constant"
+	case Nearest:
+		return "Nearest"
+	case Wrap:
+		return "Wrap"
+	}
+	return "Mode(?)"
+}
+
+// index maps an index of the extended array to an index of an array of
+// length n, or returns false when it falls on the constant extension.
+func (m Mode) index(i, n int) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+	switch m {
+	case Reflect:
+		i %= 2 * n
+		if i < 0 {
+			i += 2 * n
+		}
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+		return i, true
+	case Nearest:
+		if i < 0 {
+			return 0, true
+		}
+		return n - 1, true
+	case Wrap:
+		i %= n
+		if i < 0 {
+			i += n
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// Options controls how filters extend arrays and how geometric
+// transforms interpolate them.
+type Options struct {
+	// Mode is the extension of arrays beyond their borders.
+	Mode Mode
+	// Cval is the value of the constant extension.
+	Cval float64
+	// Order is the order of the spline interpolation of geometric
+	// transforms, from 0 (nearest item) and 1 (linear) to 5.
+	Order int
+}
+
+// DefaultOptions reflects arrays about their edges and interpolates with
+// cubic splines.
+var DefaultOptions = Options{
+	Mode:  Reflect,
+	Order: 3,
+}
+
+// options returns opts, or DefaultOptions if opts is nil.
+func options(opts *Options) *Options {
+	if opts == nil {
+		return &DefaultOptions
+	}
+	return opts
+}
+
+// rowMajor returns the strides, counted in items, of a row-major array
+// with the given shape.
+func rowMajor(shape array.Shape) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for k := len(shape) - 1; k >= 0; k-- {
+		strides[k] = stride
+		stride *= shape[k]
+	}
+	return strides
+}
+
+// unravel sets index to the multi-dimensional index of the row-major
+// position pos.
+func unravel(pos int, shape array.Shape, index []int) {
+	for k := len(shape) - 1; k >= 0; k-- {
+		index[k] = pos % shape[k]
+		pos /= shape[k]
+	}
+}
+
+// axisLength returns the length of an axis of d, counting negative axes
+// from the last dimension.
+func axisLength(d *array.Dense, axis int) (int, error) {
+	ndim := len(d.Shape)
+	if axis < -ndim || axis >= ndim {
+		return 0, &array.AxisError{Axis: axis, NDim: ndim}
+	}
+	if axis < 0 {
+		axis += ndim
+	}
+	return d.Shape[axis], nil
+}
+
+// footprint returns the offsets from the centre of every item of an
+// array for which keep is true.  The centre of an axis of length n is at
+// n/2.
+func footprint(shape array.Shape, keep func(pos int) bool) [][]int {
+	var offsets [][]int
+	index := make([]int, len(shape))
+	n := 1
+	for _, s := range shape {
+		n *= s
+	}
+	for pos := 0; pos < n; pos++ {
+		if !keep(pos) {
+			continue
+		}
+		unravel(pos, shape, index)
+		offset := make([]int, len(shape))
+		for k, i := range index {
+			offset[k] = i - shape[k]/2
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+// box returns the offsets of a box of the given size along every one of
+// ndim axes.
+func box(ndim, size int) [][]int {
+	shape := make(array.Shape, ndim)
+	for k := range shape {
+		shape[k] = size
+	}
+	return footprint(shape, func(int) bool { return true })
+}
+
+// neighborhood returns an array whose items are given by fn applied to
+// the neighbours of every item of d at the given offsets, in order.
+// Neighbours beyond the borders are taken from the extension of d.
+func neighborhood(
+	d *array.Dense, offsets [][]int, opts *Options, fn func(window []float64) float64,
+) (*array.Dense, error) {
+	values := d.Values()
+	shape := append(array.Shape{}, d.Shape...)
+	strides := rowMajor(shape)
+	out := make([]float64, len(values))
+	window := make([]float64, len(offsets))
+	index := make([]int, len(shape))
+	for pos := range out {
+		unravel(pos, shape, index)
+		for w, offset := range offsets {
+			at, inside := 0, true
+			for k, o := range offset {
+				i, ok := opts.Mode.index(index[k]+o, shape[k])
+				if !ok {
+					inside = false
+					break
+				}
+				at += i * strides[k]
+			}
+			if inside {
+				window[w] = values[at]
+			} else {
+				window[w] = opts.Cval
+			}
+		}
+		out[pos] = fn(window)
+	}
+	return array.NewDenseWithValues(shape, out, array.DefaultAttributes)
+}
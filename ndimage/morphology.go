@@ -0,0 +1,209 @@
+package ndimage
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// GenerateBinaryStructure returns a structuring element of rank
+// dimensions and length three along every axis, whose items are one
+// for the neighbours of the centre reached by at most connectivity
+// steps along distinct axes.  Connectivity one gives the cross of the
+// direct neighbours, and connectivity rank the full box.
+func GenerateBinaryStructure(rank, connectivity int) (*array.Dense, error) {
+	if rank < 0 {
+		return nil, ErrDimensionMismatch
+	}
+	shape := make(array.Shape, rank)
+	for k := range shape {
+		shape[k] = 3
+	}
+	values := make([]float64, int(math.Pow(3, float64(rank))))
+	index := make([]int, rank)
+	for pos := range values {
+		unravel(pos, shape, index)
+		steps := 0
+		for _, i := range index {
+			if i != 1 {
+				steps++
+			}
+		}
+		if steps <= connectivity {
+			values[pos] = 1
+		}
+	}
+	return array.NewDenseWithValues(shape, values, array.DefaultAttributes)
+}
+
+// structureOffsets returns the offsets of the non-zero items of a
+// structuring element, or of the cross of direct neighbours if structure
+// is nil.
+func structureOffsets(d, structure *array.Dense) ([][]int, error) {
+	if structure == nil {
+		var err error
+		if structure, err = GenerateBinaryStructure(len(d.Shape), 1); err != nil {
+			return nil, err
+		}
+	}
+	if len(structure.Shape) != len(d.Shape) {
+		return nil, ErrDimensionMismatch
+	}
+	s := structure.Values()
+	return footprint(structure.Shape, func(pos int) bool {
+		return s[pos] != 0
+	}), nil
+}
+
+// binary repeats a binary operation the given number of times, or until
+// the result does not change if iterations is not positive.
+func binary(
+	d, structure *array.Dense, iterations int, dilate bool,
+) (*array.Dense, error) {
+	offsets, err := structureOffsets(d, structure)
+	if err != nil {
+		return nil, err
+	}
+	if dilate {
+		// The structuring element is reflected.
+		for _, offset := range offsets {
+			for k := range offset {
+				offset[k] = -offset[k]
+			}
+		}
+	}
+	// An erosion keeps the items whose window is all non-zero, and a
+	// dilation those whose window is not all zero.
+	hit, miss := 0.0, 1.0
+	if dilate {
+		hit, miss = 1, 0
+	}
+	opts := &Options{Mode: Constant}
+	out := d
+	for i := 0; iterations <= 0 || i < iterations; i++ {
+		prev := out
+		out, err = neighborhood(prev, offsets, opts, func(window []float64) float64 {
+			for _, v := range window {
+				if (v != 0) == dilate {
+					return hit
+				}
+			}
+			return miss
+		})
+		if err != nil {
+			return nil, err
+		}
+		if iterations <= 0 && equalValues(prev, out) {
+			break
+		}
+	}
+	return out, nil
+}
+
+// equalValues reports whether two arrays of the same shape are non-zero
+// at the same items.
+func equalValues(a, b *array.Dense) bool {
+	x, y := a.Values(), b.Values()
+	for i := range x {
+		if (x[i] != 0) != (y[i] != 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// BinaryErosion returns the erosion of the non-zero items of d by a
+// structuring element: the result is one where the structuring element
+// centred on an item fits within the non-zero items, and zero elsewhere.
+// Items beyond the borders are taken as zero.  The erosion is repeated
+// iterations times, or until the result does not change if iterations
+// is not positive.  If structure is nil, the cross of direct neighbours
+// is used.
+func BinaryErosion(d, structure *array.Dense, iterations int) (*array.Dense, error) {
+	return binary(d, structure, iterations, false)
+}
+
+// BinaryDilation returns the dilation of the non-zero items of d by a
+// structuring element: the result is one where the structuring element,
+// reflected and centred on an item, meets a non-zero item, and zero
+// elsewhere.  The dilation is repeated iterations times, or until the
+// result does not change if iterations is not positive.  If structure
+// is nil, the cross of direct neighbours is used.
+func BinaryDilation(d, structure *array.Dense, iterations int) (*array.Dense, error) {
+	return binary(d, structure, iterations, true)
+}
+
+// GreyErosion returns the minimum of the box of the given size around
+// every item of d.  If opts is nil, DefaultOptions is used.
+func GreyErosion(d *array.Dense, size int, opts *Options) (*array.Dense, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return rankFilter(d, size, opts, func(sorted []float64) float64 {
+		return sorted[0]
+	})
+}
+
+// GreyDilation returns the maximum of the box of the given size around
+// every item of d.  If opts is nil, DefaultOptions is used.
+func GreyDilation(d *array.Dense, size int, opts *Options) (*array.Dense, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return rankFilter(d, size, opts, func(sorted []float64) float64 {
+		return sorted[len(sorted)-1]
+	})
+}
+
+// Label returns an array in which the items of every connected
+// component of the non-zero items of d are numbered from one, in the
+// row-major order of their first item, along with the number of
+// components.  Zero items are labelled zero.  Items are connected when
+// their offset is a non-zero item of the structuring element, or of the
+// cross of direct neighbours if structure is nil.
+func Label(d, structure *array.Dense) (*array.Dense, int, error) {
+	offsets, err := structureOffsets(d, structure)
+	if err != nil {
+		return nil, 0, err
+	}
+	values := d.Values()
+	shape := append(array.Shape{}, d.Shape...)
+	strides := rowMajor(shape)
+	labels := make([]float64, len(values))
+	index := make([]int, len(shape))
+	var stack []int
+	count := 0
+	for start, v := range values {
+		if v == 0 || labels[start] != 0 {
+			continue
+		}
+		count++
+		labels[start] = float64(count)
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			pos := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			unravel(pos, shape, index)
+		neighbours:
+			for _, offset := range offsets {
+				at := 0
+				for k, o := range offset {
+					i := index[k] + o
+					if i < 0 || i >= shape[k] {
+						continue neighbours
+					}
+					at += i * strides[k]
+				}
+				if values[at] != 0 && labels[at] == 0 {
+					labels[at] = float64(count)
+					stack = append(stack, at)
+				}
+			}
+		}
+	}
+	out, err := array.NewDenseWithValues(shape, labels, array.DefaultAttributes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, count, nil
+}
@@ -0,0 +1,108 @@
+package ndimage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/ndimage"
+)
+
+func TestGenerateBinaryStructure(t *testing.T) {
+	s, err := ndimage.GenerateBinaryStructure(2, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, 1, 0, 1, 1, 1, 0, 1, 0}, s.Values())
+	}
+	s, err = ndimage.GenerateBinaryStructure(3, 3)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{3, 3, 3}, s.Shape)
+		assert.NotContains(t, s.Values(), 0.0)
+	}
+}
+
+func TestBinaryMorphology(t *testing.T) {
+	square := arraytest.Dense(t, array.Shape{5, 5},
+		0, 0, 0, 0, 0,
+		0, 1, 1, 1, 0,
+		0, 1, 1, 1, 0,
+		0, 1, 1, 1, 0,
+		0, 0, 0, 0, 0)
+	e, err := ndimage.BinaryErosion(square, nil, 1)
+	if assert.Nil(t, err) {
+		want := make([]float64, 25)
+		want[12] = 1
+		assert.Equal(t, want, e.Values())
+	}
+	// Items beyond the borders are zero.
+	e, err = ndimage.BinaryErosion(arraytest.Dense(t, array.Shape{3}, 1, 1, 1), nil, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, 1, 0}, e.Values())
+	}
+
+	point := arraytest.Dense(t, array.Shape{3, 3}, 0, 0, 0, 0, 5, 0, 0, 0, 0)
+	d, err := ndimage.BinaryDilation(point, nil, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, 1, 0, 1, 1, 1, 0, 1, 0}, d.Values())
+	}
+	// Repeated until the result is stable.
+	d, err = ndimage.BinaryDilation(point, nil, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 1, 1, 1, 1, 1, 1, 1, 1}, d.Values())
+	}
+	// A dilation by an asymmetric structuring element, whose centre is
+	// its second item, extends items toward the first one.
+	left := arraytest.Dense(t, array.Shape{1, 2}, 1, 1)
+	d, err = ndimage.BinaryDilation(arraytest.Dense(t, array.Shape{1, 3}, 0, 1, 0), left, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 1, 0}, d.Values())
+	}
+	e, err = ndimage.BinaryErosion(arraytest.Dense(t, array.Shape{1, 3}, 1, 1, 0), left, 1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, 1, 0}, e.Values())
+	}
+	_, err = ndimage.BinaryErosion(point, arraytest.Dense(t, array.Shape{3}, 1, 1, 1), 1)
+	assert.Equal(t, ndimage.ErrDimensionMismatch, err)
+}
+
+func TestGreyMorphology(t *testing.T) {
+	d := arraytest.Dense(t, array.Shape{5}, 3, 1, 4, 1, 5)
+	e, err := ndimage.GreyErosion(d, 3, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 1, 1, 1, 1}, e.Values())
+	}
+	g, err := ndimage.GreyDilation(d, 3, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{3, 4, 4, 5, 5}, g.Values())
+	}
+}
+
+func TestLabel(t *testing.T) {
+	d := arraytest.Dense(t, array.Shape{4, 4},
+		1, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 1, 0,
+		1, 0, 0, 1)
+	labels, n, err := ndimage.Label(d, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 4, n)
+		assert.Equal(t, []float64{
+			1, 1, 0, 0,
+			0, 0, 2, 0,
+			0, 0, 2, 0,
+			3, 0, 0, 4,
+		}, labels.Values())
+	}
+	full, _ := ndimage.GenerateBinaryStructure(2, 2)
+	labels, n, err = ndimage.Label(d, full)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []float64{
+			1, 1, 0, 0,
+			0, 0, 1, 0,
+			0, 0, 1, 0,
+			2, 0, 0, 1,
+		}, labels.Values())
+	}
+}
@@ -0,0 +1,25 @@
+package ode
+
+import "errors"
+
+var (
+	// ErrInvalidStep is returned when a fixed-step method is used
+	// without a positive step size.
+	ErrInvalidStep = errors.New("step size must be positive")
+
+	// ErrStepTooSmall is returned when an adaptive method cannot meet
+	// the tolerances without reducing the step below the resolution of
+	// the time variable.
+	ErrStepTooSmall = errors.New("required step size is too small")
+
+	// ErrTooManySteps is returned when the integration does not finish
+	// within the maximum number of steps.
+	ErrTooManySteps = errors.New("maximum number of steps reached")
+
+	// ErrUnknownMethod is returned for an unsupported integration method.
+	ErrUnknownMethod = errors.New("unknown integration method")
+
+	// ErrOutOfRange is returned when the dense output is evaluated
+	// outside the integrated interval.
+	ErrOutOfRange = errors.New("time is outside the integrated interval")
+)
@@ -0,0 +1,251 @@
+package ode
+
+import (
+	"math"
+	"sort"
+)
+
+const epsilon = 2.220446049250313e-16
+
+// stepper advances a state by one step of a given method.
+type stepper interface {
+	// step attempts a step of signed size h from the state y with
+	// derivative fy at time t.  It returns the new state, its
+	// derivative and the local error relative to the tolerances, which
+	// must not exceed one for the step to be accepted.
+	step(t, h float64, y, fy []float64) (ynew, fnew []float64, errNorm float64, err error)
+
+	// order returns the order of the local error estimate.
+	order() int
+}
+
+// integrate advances the state from t0 to t1, appending every accepted
+// step to the solution.
+func integrate(
+	sys *system, st stepper, sol *Solution,
+	t0, t1 float64, y, fy []float64, opts *Options,
+) error {
+	if t0 == t1 {
+		return nil
+	}
+	dir := 1.0
+	if t1 < t0 {
+		dir = -1
+	}
+	adaptive := opts.Method != RK4
+	h := opts.Step
+	if adaptive && h <= 0 {
+		var err error
+		h, err = initialStep(sys, t0, t1, y, fy, st.order(), opts)
+		if err != nil {
+			return err
+		}
+	}
+	maxStep := opts.MaxStep
+	if maxStep <= 0 {
+		maxStep = math.Inf(1)
+	}
+	exponent := -1 / float64(st.order()+1)
+	g := make([]float64, len(opts.Events))
+	for k, e := range opts.Events {
+		g[k] = e.Func(t0, sys.dense(y))
+	}
+	t := t0
+	for steps := 0; t != t1; steps++ {
+		if steps >= opts.MaxSteps {
+			return ErrTooManySteps
+		}
+		h = math.Min(h, maxStep)
+		if h < 10*epsilon*math.Abs(t) {
+			return ErrStepTooSmall
+		}
+		tnew := t + dir*h
+		if dir*(tnew-t1) >= 0 {
+			tnew = t1
+		}
+		ynew, fnew, errNorm, err := st.step(t, tnew-t, y, fy)
+		if err != nil {
+			return err
+		}
+		next := h
+		if adaptive {
+			if !(errNorm <= 1) {
+				h = math.Abs(tnew-t) *
+					math.Max(0.2, 0.9*math.Pow(errNorm, exponent))
+				continue
+			}
+			factor := 10.0
+			if errNorm > 0 {
+				factor = math.Min(10, 0.9*math.Pow(errNorm, exponent))
+			}
+			next = math.Abs(tnew-t) * factor
+		}
+		stop, tstop, err := sol.detectEvents(
+			sys, opts.Events, g, t, tnew, y, ynew, fy, fnew)
+		if err != nil {
+			return err
+		}
+		if stop {
+			ystop := make([]float64, len(y))
+			hermite(ystop, tstop, t, tnew, y, ynew, fy, fnew)
+			fstop := make([]float64, len(y))
+			if err := sys.eval(tstop, ystop, fstop); err != nil {
+				return err
+			}
+			sol.append(tstop, ystop, fstop)
+			sol.Terminated = true
+			return nil
+		}
+		t, y, fy = tnew, ynew, fnew
+		sol.append(t, y, fy)
+		h = next
+	}
+	return nil
+}
+
+// eventHit is an event located within a step.
+type eventHit struct {
+	event int
+	t     float64
+}
+
+// detectEvents locates the events whose functions change sign within
+// the step from t to tnew, recording them in the solution in
+// chronological order.  It reports whether a terminal event happened
+// and its time.  The values of the event functions at the end of the
+// step are stored in g.
+func (s *Solution) detectEvents(
+	sys *system, events []Event, g []float64,
+	t, tnew float64, y, ynew, fy, fnew []float64,
+) (bool, float64, error) {
+	if len(events) == 0 {
+		return false, 0, nil
+	}
+	state := make([]float64, len(y))
+	at := func(e Event, tau float64) float64 {
+		hermite(state, tau, t, tnew, y, ynew, fy, fnew)
+		return e.Func(tau, sys.dense(state))
+	}
+	var hits []eventHit
+	for k, e := range events {
+		gPrev := g[k]
+		g[k] = e.Func(tnew, sys.dense(ynew))
+		if !crosses(gPrev, g[k], e.Direction) {
+			continue
+		}
+		// Bisect the dense output until the root is bracketed within
+		// the resolution of the time variable.
+		lo, hi := t, tnew
+		for i := 0; i < 200; i++ {
+			mid := lo + (hi-lo)/2
+			if mid == lo || mid == hi {
+				break
+			}
+			gm := at(e, mid)
+			if gm != 0 && math.Signbit(gm) == math.Signbit(gPrev) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		hits = append(hits, eventHit{event: k, t: hi})
+	}
+	forward := tnew > t
+	sort.SliceStable(hits, func(i, j int) bool {
+		return (hits[i].t < hits[j].t) == forward && hits[i].t != hits[j].t
+	})
+	for _, hit := range hits {
+		hermite(state, hit.t, t, tnew, y, ynew, fy, fnew)
+		yevent, err := s.state(state)
+		if err != nil {
+			return false, 0, err
+		}
+		s.TEvents[hit.event] = append(s.TEvents[hit.event], hit.t)
+		s.YEvents[hit.event] = append(s.YEvents[hit.event], yevent)
+		if events[hit.event].Terminal {
+			return true, hit.t, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// crosses reports whether an event function changing from g0 to g1
+// crossed zero in the requested direction.
+func crosses(g0, g1 float64, direction int) bool {
+	rising := g0 < 0 && g1 >= 0
+	falling := g0 > 0 && g1 <= 0
+	switch {
+	case direction > 0:
+		return rising
+	case direction < 0:
+		return falling
+	}
+	return rising || falling
+}
+
+// rmsNorm returns the root mean square of v[i]/scale[i].
+func rmsNorm(v, scale []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for i := range v {
+		r := v[i] / scale[i]
+		sum += r * r
+	}
+	return math.Sqrt(sum / float64(len(v)))
+}
+
+// errorScale returns the tolerance of each item of a step from y0
+// to y1.
+func errorScale(y0, y1 []float64, opts *Options) []float64 {
+	scale := make([]float64, len(y0))
+	for i := range scale {
+		scale[i] = opts.AbsTol +
+			opts.RelTol*math.Max(math.Abs(y0[i]), math.Abs(y1[i]))
+	}
+	return scale
+}
+
+// initialStep estimates a suitable first step size, following Hairer,
+// Norsett and Wanner, "Solving Ordinary Differential Equations I",
+// section II.4.
+func initialStep(
+	sys *system, t0, t1 float64, y0, f0 []float64, order int, opts *Options,
+) (float64, error) {
+	span := math.Abs(t1 - t0)
+	if len(y0) == 0 {
+		return span, nil
+	}
+	scale := errorScale(y0, y0, opts)
+	d0 := rmsNorm(y0, scale)
+	d1 := rmsNorm(f0, scale)
+	h0 := 1e-6
+	if d0 >= 1e-5 && d1 >= 1e-5 {
+		h0 = 0.01 * d0 / d1
+	}
+	h0 = math.Min(h0, span)
+	dir := 1.0
+	if t1 < t0 {
+		dir = -1
+	}
+	y1 := make([]float64, len(y0))
+	for i := range y1 {
+		y1[i] = y0[i] + dir*h0*f0[i]
+	}
+	f1 := make([]float64, len(y0))
+	if err := sys.eval(t0+dir*h0, y1, f1); err != nil {
+		return 0, err
+	}
+	for i := range f1 {
+		f1[i] -= f0[i]
+	}
+	d2 := rmsNorm(f1, scale) / h0
+	var h1 float64
+	if d1 <= 1e-15 && d2 <= 1e-15 {
+		h1 = math.Max(1e-6, h0*1e-3)
+	} else {
+		h1 = math.Pow(0.01/math.Max(d1, d2), 1/float64(order+1))
+	}
+	return math.Min(math.Min(100*h0, h1), span), nil
+}
@@ -0,0 +1,281 @@
+// Package ode solves initial value problems for systems of ordinary
+// differential equations whose state is stored in a Dense array.
+package ode
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Func computes the derivative dy/dt = f(t, y) of a system, storing it
+// in dydt, which has the same shape as y.  The arrays are only valid
+// during the call and must not be retained.
+type Func func(t float64, y, dydt *array.Dense) error
+
+// JacobianFunc computes the Jacobian matrix of a system at (t, y),
+// storing in jac[i, j] the derivative of the item i of dy/dt with
+// respect to the item j of y, with items taken in row-major order.
+type JacobianFunc func(t float64, y, jac *array.Dense) error
+
+// Method is an integration method.
+type Method int
+
+const (
+	// RK45 is the adaptive explicit Runge-Kutta method of order 5(4) of
+	// Dormand and Prince.
+	RK45 Method = iota
+
+	// RK4 is the classical fixed-step Runge-Kutta method of order 4.
+	RK4
+
+	// Radau is the implicit, L-stable, 2-stage Radau IIA method of
+	// order 3 with error control by step doubling.  It is suited to
+	// stiff systems.
+	Radau
+)
+
+// Event is a function of the state whose zero crossings are located
+// during integration.
+type Event struct {
+	// Func returns a value whose sign changes when the event happens.
+	Func func(t float64, y *array.Dense) float64
+
+	// Terminal stops the integration at the first occurrence.
+	Terminal bool
+
+	// Direction restricts the crossings to rising (positive) or
+	// falling (negative) values.  Zero matches both.
+	Direction int
+}
+
+// Options controls the integration.  Zero values of RelTol, AbsTol
+// and MaxSteps are replaced by those in DefaultOptions.
+type Options struct {
+	// Method is the integration method.
+	Method Method
+
+	// Step is the step size of fixed-step methods and the initial step
+	// size of adaptive methods.  Adaptive methods choose the initial
+	// step when Step is zero.
+	Step float64
+
+	// RelTol and AbsTol are the relative and absolute tolerances of the
+	// local error of adaptive methods.
+	RelTol, AbsTol float64
+
+	// MaxStep limits the step size of adaptive methods.  Zero means no
+	// limit.
+	MaxStep float64
+
+	// MaxSteps limits the number of steps.
+	MaxSteps int
+
+	// Jacobian optionally computes the Jacobian for implicit methods.
+	// It is approximated by finite differences when nil.
+	Jacobian JacobianFunc
+
+	// Events are located during the integration.
+	Events []Event
+}
+
+// DefaultOptions are used by Solve when no options are given.
+var DefaultOptions = Options{
+	Method:   RK45,
+	RelTol:   1e-3,
+	AbsTol:   1e-6,
+	MaxSteps: 100000,
+}
+
+// Solution is the result of an integration.
+type Solution struct {
+	// T holds the time of each step, including the initial time.
+	T *array.Dense
+
+	// Y stacks the state at each time in T along its first axis.
+	Y *array.Dense
+
+	// TEvents and YEvents hold, for each event, the times and states at
+	// which it happened.
+	TEvents [][]float64
+	YEvents [][]*array.Dense
+
+	// Terminated is set when a terminal event stopped the integration.
+	Terminated bool
+
+	// Evaluations counts the calls to the system function.
+	Evaluations int
+
+	shape array.Shape
+	times []float64
+	ys    [][]float64
+	fs    [][]float64
+}
+
+// At evaluates the dense output of the solution at t, a cubic Hermite
+// interpolant of the steps and their derivatives.
+func (s *Solution) At(t float64) (*array.Dense, error) {
+	n := len(s.times)
+	first, last := s.times[0], s.times[n-1]
+	if math.IsNaN(t) || t < math.Min(first, last) || t > math.Max(first, last) {
+		return nil, ErrOutOfRange
+	}
+	if n == 1 {
+		return s.state(s.ys[0])
+	}
+	var i int
+	if last > first {
+		i = sort.SearchFloat64s(s.times, t) - 1
+	} else {
+		i = sort.Search(n, func(k int) bool { return s.times[k] <= t }) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	if i > n-2 {
+		i = n - 2
+	}
+	y := make([]float64, len(s.ys[i]))
+	hermite(y, t, s.times[i], s.times[i+1],
+		s.ys[i], s.ys[i+1], s.fs[i], s.fs[i+1])
+	return s.state(y)
+}
+
+func (s *Solution) state(y []float64) (*array.Dense, error) {
+	v := append([]float64(nil), y...)
+	return array.NewDenseWithValues(s.shape, v, array.DefaultAttributes)
+}
+
+func (s *Solution) append(t float64, y, f []float64) {
+	s.times = append(s.times, t)
+	s.ys = append(s.ys, append([]float64(nil), y...))
+	s.fs = append(s.fs, append([]float64(nil), f...))
+}
+
+// stack builds the T and Y arrays from the recorded steps.
+func (s *Solution) stack() error {
+	var err error
+	n := len(s.times)
+	s.T, err = array.NewDenseWithValues(
+		array.Shape{n}, s.times, array.DefaultAttributes)
+	if err != nil {
+		return err
+	}
+	values := make([]float64, 0, n*len(s.ys[0]))
+	for _, y := range s.ys {
+		values = append(values, y...)
+	}
+	shape := append(array.Shape{n}, s.shape...)
+	s.Y, err = array.NewDenseWithValues(shape, values, array.RowMajorLayout)
+	return err
+}
+
+// hermite evaluates at t the cubic Hermite interpolant between the
+// states y0 and y1 with derivatives f0 and f1 at times t0 and t1.
+func hermite(y []float64, t, t0, t1 float64, y0, y1, f0, f1 []float64) {
+	h := t1 - t0
+	s := (t - t0) / h
+	s2 := s * s
+	s3 := s2 * s
+	h00 := 2*s3 - 3*s2 + 1
+	h10 := (s3 - 2*s2 + s) * h
+	h01 := -2*s3 + 3*s2
+	h11 := (s3 - s2) * h
+	for i := range y {
+		y[i] = h00*y0[i] + h10*f0[i] + h01*y1[i] + h11*f1[i]
+	}
+}
+
+// system evaluates the user function on flat state vectors.
+type system struct {
+	f           Func
+	jacobian    JacobianFunc
+	y, dydt     *array.Dense
+	jac         *array.Dense
+	evaluations int
+}
+
+func newSystem(f Func, jacobian JacobianFunc, shape array.Shape) (*system, error) {
+	attrs := array.Contiguous | array.Writeable | array.RowMajorLayout
+	y, err := array.NewDense(shape, attrs)
+	if err != nil {
+		return nil, err
+	}
+	dydt, err := array.NewDense(shape, attrs)
+	if err != nil {
+		return nil, err
+	}
+	return &system{f: f, jacobian: jacobian, y: y, dydt: dydt}, nil
+}
+
+// eval stores in dydt the derivative at (t, y).
+func (s *system) eval(t float64, y, dydt []float64) error {
+	s.y.Data = y
+	s.dydt.Data = dydt
+	s.evaluations++
+	return s.f(t, s.y, s.dydt)
+}
+
+// dense wraps a state vector in an array for user callbacks.
+func (s *system) dense(y []float64) *array.Dense {
+	s.y.Data = y
+	return s.y
+}
+
+// Solve integrates the system dy/dt = f(t, y) from t0 to t1 with the
+// initial state y0, which may have any shape.  The integration runs
+// backwards when t1 is less than t0.  When opts is nil, DefaultOptions
+// is used.  On failure, the steps computed so far are returned along
+// with the error.
+func Solve(f Func, t0, t1 float64, y0 *array.Dense, opts *Options) (*Solution, error) {
+	if opts == nil {
+		opts = &DefaultOptions
+	}
+	o := *opts
+	if o.RelTol == 0 {
+		o.RelTol = DefaultOptions.RelTol
+	}
+	if o.AbsTol == 0 {
+		o.AbsTol = DefaultOptions.AbsTol
+	}
+	if o.MaxSteps == 0 {
+		o.MaxSteps = DefaultOptions.MaxSteps
+	}
+	opts = &o
+	sys, err := newSystem(f, opts.Jacobian, y0.Shape)
+	if err != nil {
+		return nil, err
+	}
+	y := y0.Values()
+	fy := make([]float64, len(y))
+	if err := sys.eval(t0, y, fy); err != nil {
+		return nil, err
+	}
+	sol := &Solution{
+		shape:   append(array.Shape{}, y0.Shape...),
+		TEvents: make([][]float64, len(opts.Events)),
+		YEvents: make([][]*array.Dense, len(opts.Events)),
+	}
+	sol.append(t0, y, fy)
+	var step stepper
+	switch opts.Method {
+	case RK4:
+		if !(opts.Step > 0) {
+			return nil, ErrInvalidStep
+		}
+		step = &rk4{sys: sys}
+	case RK45:
+		step = &dopri{sys: sys, opts: opts}
+	case Radau:
+		step = newRadau(sys, opts)
+	default:
+		return nil, ErrUnknownMethod
+	}
+	err = integrate(sys, step, sol, t0, t1, y, fy, opts)
+	sol.Evaluations = sys.evaluations
+	if serr := sol.stack(); err == nil {
+		err = serr
+	}
+	return sol, err
+}
@@ -0,0 +1,142 @@
+package ode_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/ode"
+)
+
+// oscillator is the harmonic oscillator with unit frequency.
+func oscillator(t float64, y, dydt *array.Dense) error {
+	dydt.Data[0] = y.Data[1]
+	dydt.Data[1] = -y.Data[0]
+	return nil
+}
+
+func TestRK4(t *testing.T) {
+	decay := func(t float64, y, dydt *array.Dense) error {
+		dydt.Data[0] = -2 * y.Data[0]
+		return nil
+	}
+	opts := ode.Options{Method: ode.RK4, Step: 0.01}
+	sol, err := ode.Solve(decay, 0, 1, arraytest.Dense(t, array.Shape{1}, 1), &opts)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{101}, sol.T.Shape)
+		assert.Equal(t, array.Shape{101, 1}, sol.Y.Shape)
+		v, _ := sol.Y.Get(array.Indices{-1, 0})
+		assert.InDelta(t, math.Exp(-2), v, 1e-9)
+		assert.Equal(t, 1.0, sol.T.Data[100])
+	}
+	_, err = ode.Solve(decay, 0, 1, arraytest.Dense(t, array.Shape{1}, 1),
+		&ode.Options{Method: ode.RK4})
+	assert.ErrorIs(t, err, ode.ErrInvalidStep)
+}
+
+func TestRK45(t *testing.T) {
+	opts := ode.DefaultOptions
+	opts.RelTol, opts.AbsTol = 1e-9, 1e-12
+	y0 := arraytest.Dense(t, array.Shape{2}, 1, 0)
+	sol, err := ode.Solve(oscillator, 0, 10, y0, &opts)
+	if assert.Nil(t, err) {
+		n := sol.T.Shape[0]
+		assert.Equal(t, array.Shape{n, 2}, sol.Y.Shape)
+		v, _ := sol.Y.Get(array.Indices{-1, 0})
+		assert.InDelta(t, math.Cos(10), v, 1e-7)
+		v, _ = sol.Y.Get(array.Indices{-1, 1})
+		assert.InDelta(t, -math.Sin(10), v, 1e-7)
+		// Dense output between steps.
+		y, err := sol.At(2.5)
+		if assert.Nil(t, err) {
+			assert.Equal(t, array.Shape{2}, y.Shape)
+			assert.InDelta(t, math.Cos(2.5), y.Data[0], 1e-5)
+		}
+		_, err = sol.At(11)
+		assert.ErrorIs(t, err, ode.ErrOutOfRange)
+	}
+	// Integrating backwards.
+	sol, err = ode.Solve(oscillator, 0, -2, y0, &opts)
+	if assert.Nil(t, err) {
+		v, _ := sol.Y.Get(array.Indices{-1, 1})
+		assert.InDelta(t, math.Sin(2), v, 1e-7)
+		y, err := sol.At(-1)
+		if assert.Nil(t, err) {
+			assert.InDelta(t, math.Cos(1), y.Data[0], 1e-5)
+		}
+	}
+	// The state keeps its shape.
+	sol, err = ode.Solve(oscillator, 0, 1, arraytest.Dense(t, array.Shape{2, 1}, 1, 0), nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{sol.T.Shape[0], 2, 1}, sol.Y.Shape)
+	}
+}
+
+func TestRadauStiff(t *testing.T) {
+	// Robertson's chemical kinetics problem.
+	robertson := func(t float64, y, dydt *array.Dense) error {
+		y1, y2, y3 := y.Data[0], y.Data[1], y.Data[2]
+		dydt.Data[0] = -0.04*y1 + 1e4*y2*y3
+		dydt.Data[1] = 0.04*y1 - 1e4*y2*y3 - 3e7*y2*y2
+		dydt.Data[2] = 3e7 * y2 * y2
+		return nil
+	}
+	opts := ode.Options{Method: ode.Radau, RelTol: 1e-6, AbsTol: 1e-10}
+	sol, err := ode.Solve(robertson, 0, 40, arraytest.Dense(t, array.Shape{3}, 1, 0, 0), &opts)
+	if assert.Nil(t, err) {
+		y, _ := sol.At(40)
+		assert.InDelta(t, 0.7158, y.Data[0], 1e-3)
+		assert.InDelta(t, 9.185e-6, y.Data[1], 1e-7)
+		assert.InDelta(t, 1.0, y.Data[0]+y.Data[1]+y.Data[2], 1e-6)
+		assert.Less(t, sol.T.Shape[0], 1000)
+	}
+	// Analytic Jacobian of a stiff linear problem.
+	stiff := func(t float64, y, dydt *array.Dense) error {
+		dydt.Data[0] = -1000 * (y.Data[0] - math.Cos(t))
+		return nil
+	}
+	opts.Jacobian = func(t float64, y, jac *array.Dense) error {
+		jac.Data[0] = -1000
+		return nil
+	}
+	sol, err = ode.Solve(stiff, 0, 2, arraytest.Dense(t, array.Shape{1}, 0), &opts)
+	if assert.Nil(t, err) {
+		v, _ := sol.Y.Get(array.Indices{-1, 0})
+		expected := (1e6*math.Cos(2) + 1e3*math.Sin(2)) / (1e6 + 1)
+		assert.InDelta(t, expected, v, 1e-5)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	// A ball thrown upwards falls back to the ground at t = 2.
+	ball := func(t float64, y, dydt *array.Dense) error {
+		dydt.Data[0] = y.Data[1]
+		dydt.Data[1] = -9.8
+		return nil
+	}
+	ground := ode.Event{
+		Func:      func(t float64, y *array.Dense) float64 { return y.Data[0] },
+		Terminal:  true,
+		Direction: -1,
+	}
+	apex := ode.Event{
+		Func: func(t float64, y *array.Dense) float64 { return y.Data[1] },
+	}
+	opts := ode.DefaultOptions
+	opts.Events = []ode.Event{ground, apex}
+	sol, err := ode.Solve(ball, 0, 10, arraytest.Dense(t, array.Shape{2}, 0, 9.8), &opts)
+	if assert.Nil(t, err) {
+		assert.True(t, sol.Terminated)
+		if assert.Len(t, sol.TEvents[0], 1) {
+			assert.InDelta(t, 2.0, sol.TEvents[0][0], 1e-9)
+			assert.InDelta(t, 0.0, sol.YEvents[0][0].Data[0], 1e-9)
+		}
+		if assert.Len(t, sol.TEvents[1], 1) {
+			assert.InDelta(t, 1.0, sol.TEvents[1][0], 1e-9)
+		}
+		assert.InDelta(t, 2.0, sol.T.Data[sol.T.Shape[0]-1], 1e-9)
+	}
+}
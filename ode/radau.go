@@ -0,0 +1,181 @@
+package ode
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/linalg"
+)
+
+// Radau IIA coefficients with two stages.  The method is stiffly
+// accurate: the new state is the last stage.
+var (
+	radauC = [2]float64{1. / 3, 1}
+	radauA = [2][2]float64{
+		{5. / 12, -1. / 12},
+		{3. / 4, 1. / 4},
+	}
+)
+
+// radau is the 2-stage Radau IIA method.  The stage equations are
+// solved by simplified Newton iterations and the local error is
+// estimated by comparing a step with two steps of half the size.
+type radau struct {
+	sys  *system
+	opts *Options
+	jac  []float64
+}
+
+func newRadau(sys *system, opts *Options) *radau {
+	return &radau{sys: sys, opts: opts}
+}
+
+func (r *radau) order() int { return 3 }
+
+func (r *radau) step(t, h float64, y, fy []float64) ([]float64, []float64, float64, error) {
+	if err := r.jacobian(t, y, fy); err != nil {
+		return nil, nil, 0, err
+	}
+	n := len(y)
+	full, ok := r.newtonMatrix(h, n)
+	if !ok {
+		return nil, nil, math.Inf(1), nil
+	}
+	half, ok := r.newtonMatrix(h/2, n)
+	if !ok {
+		return nil, nil, math.Inf(1), nil
+	}
+	y1, ok, err := r.solve(t, h, y, full)
+	if err != nil || !ok {
+		return nil, nil, math.Inf(1), err
+	}
+	ymid, ok, err := r.solve(t, h/2, y, half)
+	if err != nil || !ok {
+		return nil, nil, math.Inf(1), err
+	}
+	y2, ok, err := r.solve(t+h/2, h/2, ymid, half)
+	if err != nil || !ok {
+		return nil, nil, math.Inf(1), err
+	}
+	// Richardson error estimate for a method of order 3.
+	errEst := make([]float64, n)
+	for i := range errEst {
+		errEst[i] = (y2[i] - y1[i]) / 7
+	}
+	errNorm := rmsNorm(errEst, errorScale(y, y2, r.opts))
+	fnew := make([]float64, n)
+	if err := r.sys.eval(t+h, y2, fnew); err != nil {
+		return nil, nil, 0, err
+	}
+	return y2, fnew, errNorm, nil
+}
+
+// jacobian evaluates the Jacobian at (t, y), either with the user
+// function or by forward differences.
+func (r *radau) jacobian(t float64, y, fy []float64) error {
+	n := len(y)
+	if r.opts.Jacobian != nil {
+		jac, err := array.NewDense(array.Shape{n, n},
+			array.Contiguous|array.Writeable|array.RowMajorLayout)
+		if err != nil {
+			return err
+		}
+		if err := r.opts.Jacobian(t, r.sys.dense(y), jac); err != nil {
+			return err
+		}
+		r.jac = jac.Data
+		return nil
+	}
+	r.jac = make([]float64, n*n)
+	tmp := append([]float64(nil), y...)
+	f := make([]float64, n)
+	for j := 0; j < n; j++ {
+		delta := math.Sqrt(epsilon) * math.Max(1, math.Abs(y[j]))
+		tmp[j] = y[j] + delta
+		if err := r.sys.eval(t, tmp, f); err != nil {
+			return err
+		}
+		tmp[j] = y[j]
+		for i := 0; i < n; i++ {
+			r.jac[i*n+j] = (f[i] - fy[i]) / delta
+		}
+	}
+	return nil
+}
+
+// newtonMatrix factorizes the iteration matrix I - h (A x J) of the
+// stage equations.
+func (r *radau) newtonMatrix(h float64, n int) (*linalg.LU, bool) {
+	m := 2 * n
+	a := make([]float64, m*m)
+	for k := 0; k < 2; k++ {
+		for j := 0; j < 2; j++ {
+			for i := 0; i < n; i++ {
+				row := (k*n + i) * m
+				for l := 0; l < n; l++ {
+					a[row+j*n+l] = -h * radauA[k][j] * r.jac[i*n+l]
+				}
+				if k == j {
+					a[row+j*n+i]++
+				}
+			}
+		}
+	}
+	return linalg.Factorize(a, m)
+}
+
+// solve computes one step of size h from (t, y), returning false if
+// the Newton iterations do not converge.
+func (r *radau) solve(t, h float64, y []float64, lu *linalg.LU) ([]float64, bool, error) {
+	n := len(y)
+	z := make([]float64, 2*n)
+	f := make([]float64, 2*n)
+	dz := make([]float64, 2*n)
+	tmp := make([]float64, n)
+	scale := errorScale(y, y, r.opts)
+	scale = append(scale, scale...)
+	tol := math.Max(10*epsilon/r.opts.RelTol, math.Min(0.03, math.Sqrt(r.opts.RelTol)))
+	prev := math.NaN()
+	for iter := 0; iter < 10; iter++ {
+		for k := 0; k < 2; k++ {
+			for i := range tmp {
+				tmp[i] = y[i] + z[k*n+i]
+			}
+			if err := r.sys.eval(t+radauC[k]*h, tmp, f[k*n:(k+1)*n]); err != nil {
+				return nil, false, err
+			}
+		}
+		for k := 0; k < 2; k++ {
+			for i := 0; i < n; i++ {
+				sum := radauA[k][0]*f[i] + radauA[k][1]*f[n+i]
+				dz[k*n+i] = h*sum - z[k*n+i]
+			}
+		}
+		lu.Solve(dz)
+		for i := range z {
+			z[i] += dz[i]
+		}
+		norm := rmsNorm(dz, scale)
+		if norm == 0 {
+			break
+		}
+		if iter > 0 {
+			rate := norm / prev
+			if rate >= 1 {
+				return nil, false, nil
+			}
+			if norm*rate/(1-rate) < tol {
+				break
+			}
+		}
+		if iter == 9 {
+			return nil, false, nil
+		}
+		prev = norm
+	}
+	ynew := make([]float64, n)
+	for i := range ynew {
+		ynew[i] = y[i] + z[n+i]
+	}
+	return ynew, true, nil
+}
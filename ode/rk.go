@@ -0,0 +1,104 @@
+package ode
+
+// rk4 is the classical Runge-Kutta method of order 4.
+type rk4 struct {
+	sys *system
+}
+
+func (r *rk4) order() int { return 4 }
+
+func (r *rk4) step(t, h float64, y, fy []float64) ([]float64, []float64, float64, error) {
+	n := len(y)
+	k2 := make([]float64, n)
+	k3 := make([]float64, n)
+	k4 := make([]float64, n)
+	tmp := make([]float64, n)
+	for i := range tmp {
+		tmp[i] = y[i] + h/2*fy[i]
+	}
+	if err := r.sys.eval(t+h/2, tmp, k2); err != nil {
+		return nil, nil, 0, err
+	}
+	for i := range tmp {
+		tmp[i] = y[i] + h/2*k2[i]
+	}
+	if err := r.sys.eval(t+h/2, tmp, k3); err != nil {
+		return nil, nil, 0, err
+	}
+	for i := range tmp {
+		tmp[i] = y[i] + h*k3[i]
+	}
+	if err := r.sys.eval(t+h, tmp, k4); err != nil {
+		return nil, nil, 0, err
+	}
+	ynew := make([]float64, n)
+	for i := range ynew {
+		ynew[i] = y[i] + h/6*(fy[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+	fnew := make([]float64, n)
+	if err := r.sys.eval(t+h, ynew, fnew); err != nil {
+		return nil, nil, 0, err
+	}
+	return ynew, fnew, 0, nil
+}
+
+// Dormand-Prince 5(4) coefficients.
+var (
+	dopriC = [7]float64{0, 1. / 5, 3. / 10, 4. / 5, 8. / 9, 1, 1}
+	dopriA = [7][6]float64{
+		{},
+		{1. / 5},
+		{3. / 40, 9. / 40},
+		{44. / 45, -56. / 15, 32. / 9},
+		{19372. / 6561, -25360. / 2187, 64448. / 6561, -212. / 729},
+		{9017. / 3168, -355. / 33, 46732. / 5247, 49. / 176, -5103. / 18656},
+		{35. / 384, 0, 500. / 1113, 125. / 192, -2187. / 6784, 11. / 84},
+	}
+	// dopriE is the difference between the weights of the 5th and the
+	// embedded 4th order solutions.
+	dopriE = [7]float64{
+		71. / 57600, 0, -71. / 16695, 71. / 1920,
+		-17253. / 339200, 22. / 525, -1. / 40,
+	}
+)
+
+// dopri is the adaptive Runge-Kutta method of order 5(4) of Dormand
+// and Prince.  The 5th order solution is propagated.
+type dopri struct {
+	sys  *system
+	opts *Options
+}
+
+func (d *dopri) order() int { return 4 }
+
+func (d *dopri) step(t, h float64, y, fy []float64) ([]float64, []float64, float64, error) {
+	n := len(y)
+	var k [7][]float64
+	k[0] = fy
+	tmp := make([]float64, n)
+	for s := 1; s < 7; s++ {
+		for i := range tmp {
+			sum := 0.0
+			for j := 0; j < s; j++ {
+				sum += dopriA[s][j] * k[j][i]
+			}
+			tmp[i] = y[i] + h*sum
+		}
+		k[s] = make([]float64, n)
+		if err := d.sys.eval(t+dopriC[s]*h, tmp, k[s]); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	// The last stage is evaluated at the new state.
+	ynew := tmp
+	errEst := make([]float64, n)
+	for i := range errEst {
+		sum := 0.0
+		for s := 0; s < 7; s++ {
+			sum += dopriE[s] * k[s][i]
+		}
+		errEst[i] = h * sum
+	}
+	errNorm := rmsNorm(errEst, errorScale(y, ynew, d.opts))
+	return ynew, k[6], errNorm, nil
+}
@@ -0,0 +1,188 @@
+package optimize
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// BFGS minimizes a function with the quasi-Newton method of Broyden,
+// Fletcher, Goldfarb and Shanno, starting from x0.  Steps are chosen by
+// a line search satisfying the strong Wolfe conditions.
+func BFGS(p Problem, x0 *array.Dense, opts *Options) (*Result, error) {
+	obj, err := newObjective(p, x0.Shape)
+	if err != nil {
+		return nil, err
+	}
+	x := x0.Values()
+	n := len(x)
+	opts = options(opts, n)
+	f := obj.value(x)
+	g := make([]float64, n)
+	obj.gradient(x, g)
+	// Inverse Hessian approximation, row-major.
+	h := identity(n)
+	dir := make([]float64, n)
+	s := make([]float64, n)
+	y := make([]float64, n)
+	hy := make([]float64, n)
+	iter := 0
+	converged := false
+	var failure error
+	for ; iter < opts.MaxIter; iter++ {
+		if maxAbs(g) <= opts.GradTol {
+			converged = true
+			break
+		}
+		for i := 0; i < n; i++ {
+			dir[i] = -dot(h[i*n:(i+1)*n], g)
+		}
+		alpha, xnew, fnew, gnew, ok := lineSearch(obj, x, f, g, dir, 1)
+		if !ok {
+			failure = ErrLineSearch
+			break
+		}
+		for i := range s {
+			s[i] = alpha * dir[i]
+			y[i] = gnew[i] - g[i]
+		}
+		sy := dot(s, y)
+		if sy > 0 {
+			if iter == 0 {
+				// Scale the initial approximation (Nocedal and Wright,
+				// equation 6.20).
+				scale := sy / dot(y, y)
+				for i := range h {
+					h[i] *= scale
+				}
+			}
+			rho := 1 / sy
+			for i := 0; i < n; i++ {
+				hy[i] = dot(h[i*n:(i+1)*n], y)
+			}
+			yhy := dot(y, hy)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					h[i*n+j] += rho * ((1+rho*yhy)*s[i]*s[j] - hy[i]*s[j] - s[i]*hy[j])
+				}
+			}
+		}
+		x, f, g = xnew, fnew, gnew
+	}
+	r, err := obj.result(x, f, g)
+	if err != nil {
+		return nil, err
+	}
+	r.Iterations = iter
+	r.Converged = converged
+	switch {
+	case failure != nil:
+		return r, failure
+	case !converged:
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
+
+func identity(n int) []float64 {
+	m := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		m[i*n+i] = 1
+	}
+	return m
+}
+
+// lineSearch finds a step along dir from x satisfying the strong Wolfe
+// conditions, following Nocedal and Wright, "Numerical Optimization",
+// algorithms 3.5 and 3.6.  It returns the step, the new point with its
+// value and gradient, and whether the search succeeded.
+func lineSearch(
+	obj *objective, x []float64, f float64, g, dir []float64, alpha float64,
+) (float64, []float64, float64, []float64, bool) {
+	const (
+		c1       = 1e-4
+		c2       = 0.9
+		maxIters = 40
+	)
+	n := len(x)
+	slope := dot(g, dir)
+	if !(slope < 0) {
+		return 0, nil, 0, nil, false
+	}
+	type trial struct {
+		alpha, f, slope float64
+		x, g            []float64
+	}
+	eval := func(a float64) trial {
+		t := trial{alpha: a, x: make([]float64, n), g: make([]float64, n)}
+		for i := range t.x {
+			t.x[i] = x[i] + a*dir[i]
+		}
+		t.f = obj.value(t.x)
+		obj.gradient(t.x, t.g)
+		t.slope = dot(t.g, dir)
+		return t
+	}
+	sufficient := func(t trial) bool {
+		return t.f <= f+c1*t.alpha*slope
+	}
+	curvature := func(t trial) bool {
+		return math.Abs(t.slope) <= -c2*slope
+	}
+	zoom := func(lo, hi trial) (trial, bool) {
+		for i := 0; i < maxIters; i++ {
+			// Minimizer of the quadratic interpolating the values at both
+			// ends and the slope at lo, safeguarded towards bisection.
+			d := hi.alpha - lo.alpha
+			a := lo.alpha + d/2
+			denom := 2 * (hi.f - lo.f - lo.slope*d)
+			if denom > 0 {
+				q := lo.alpha - lo.slope*d*d/denom
+				low := math.Min(lo.alpha, hi.alpha) + 0.1*math.Abs(d)
+				high := math.Max(lo.alpha, hi.alpha) - 0.1*math.Abs(d)
+				if q > low && q < high {
+					a = q
+				}
+			}
+			if a == lo.alpha || a == hi.alpha {
+				break
+			}
+			t := eval(a)
+			if !sufficient(t) || t.f >= lo.f {
+				hi = t
+				continue
+			}
+			if curvature(t) {
+				return t, true
+			}
+			if t.slope*(hi.alpha-lo.alpha) >= 0 {
+				hi = lo
+			}
+			lo = t
+		}
+		return lo, lo.alpha > 0 && sufficient(lo) && lo.f < f
+	}
+	prev := trial{alpha: 0, f: f, slope: slope, x: x, g: g}
+	for i := 0; i < maxIters; i++ {
+		t := eval(alpha)
+		if math.IsNaN(t.f) || math.IsInf(t.f, 1) {
+			// Step into an undefined region: shrink it.
+			alpha /= 10
+			continue
+		}
+		if !sufficient(t) || (i > 0 && t.f >= prev.f) {
+			best, ok := zoom(prev, t)
+			return best.alpha, best.x, best.f, best.g, ok
+		}
+		if curvature(t) {
+			return t.alpha, t.x, t.f, t.g, true
+		}
+		if t.slope >= 0 {
+			best, ok := zoom(t, prev)
+			return best.alpha, best.x, best.f, best.g, ok
+		}
+		prev = t
+		alpha *= 2
+	}
+	return 0, nil, 0, nil, false
+}
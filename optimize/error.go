@@ -0,0 +1,30 @@
+package optimize
+
+import "errors"
+
+var (
+	// ErrNotBracketed is returned when the function values at the ends
+	// of an interval do not have opposite signs.
+	ErrNotBracketed = errors.New("root is not bracketed by the interval")
+
+	// ErrNotConverged is returned when the tolerances are not met within
+	// the maximum number of iterations.  The best estimate is returned
+	// along with it.
+	ErrNotConverged = errors.New("failed to converge")
+
+	// ErrZeroDerivative is returned when Newton's method reaches a point
+	// with zero derivative.
+	ErrZeroDerivative = errors.New("derivative is zero")
+
+	// ErrLineSearch is returned when no step satisfying the line search
+	// conditions is found, usually due to loss of precision near the
+	// minimum.
+	ErrLineSearch = errors.New("line search failed")
+
+	// ErrInvalidBounds is returned when bounds do not match the
+	// parameters or a lower bound exceeds its upper bound.
+	ErrInvalidBounds = errors.New("invalid bounds")
+
+	// ErrInvalidInput is returned when arrays have unexpected sizes.
+	ErrInvalidInput = errors.New("arrays have incompatible sizes")
+)
@@ -0,0 +1,210 @@
+package optimize
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Bounds holds the lower and upper limits of the parameters.  Either
+// array may be nil, meaning that the parameters are unbounded in that
+// direction, and infinite items leave single parameters unbounded.
+type Bounds struct {
+	Lower, Upper *array.Dense
+}
+
+// limits returns the bounds as flat vectors of n items.
+func (b *Bounds) limits(n int) ([]float64, []float64, error) {
+	lower := make([]float64, n)
+	upper := make([]float64, n)
+	for i := 0; i < n; i++ {
+		lower[i] = math.Inf(-1)
+		upper[i] = math.Inf(1)
+	}
+	if b == nil {
+		return lower, upper, nil
+	}
+	for _, bound := range []struct {
+		d      *array.Dense
+		values []float64
+	}{{b.Lower, lower}, {b.Upper, upper}} {
+		if bound.d == nil {
+			continue
+		}
+		if bound.d.Size() != n {
+			return nil, nil, ErrInvalidBounds
+		}
+		copy(bound.values, bound.d.Values())
+	}
+	for i := range lower {
+		if lower[i] > upper[i] {
+			return nil, nil, ErrInvalidBounds
+		}
+	}
+	return lower, upper, nil
+}
+
+// LBFGSB minimizes a function subject to bound constraints, starting
+// from x0, with a projected limited-memory BFGS method in the spirit of
+// L-BFGS-B.  Parameters held at an active bound are fixed while the
+// quasi-Newton step is computed on the free parameters, and steps are
+// projected back into the feasible box.
+func LBFGSB(p Problem, x0 *array.Dense, bounds *Bounds, opts *Options) (*Result, error) {
+	obj, err := newObjective(p, x0.Shape)
+	if err != nil {
+		return nil, err
+	}
+	x := x0.Values()
+	n := len(x)
+	opts = options(opts, n)
+	lower, upper, err := bounds.limits(n)
+	if err != nil {
+		return nil, err
+	}
+	project := func(v []float64) {
+		for i := range v {
+			v[i] = math.Min(math.Max(v[i], lower[i]), upper[i])
+		}
+	}
+	project(x)
+	f := obj.value(x)
+	g := make([]float64, n)
+	obj.gradient(x, g)
+	var ss, ys [][]float64
+	var rhos []float64
+	free := make([]bool, n)
+	dir := make([]float64, n)
+	pg := make([]float64, n)
+	iter := 0
+	converged := false
+	var failure error
+	for ; iter < opts.MaxIter; iter++ {
+		// Projected gradient.
+		for i := range pg {
+			pg[i] = math.Min(math.Max(x[i]-g[i], lower[i]), upper[i]) - x[i]
+		}
+		if maxAbs(pg) <= opts.GradTol {
+			converged = true
+			break
+		}
+		for i := range free {
+			free[i] = !(x[i] <= lower[i] && g[i] > 0) &&
+				!(x[i] >= upper[i] && g[i] < 0)
+		}
+		twoLoop(dir, g, free, ss, ys, rhos)
+		if !(dot(dir, g) < 0) {
+			// Not a descent direction: restart from steepest descent.
+			ss, ys, rhos = nil, nil, nil
+			twoLoop(dir, g, free, nil, nil, nil)
+		}
+		alpha := 1.0
+		if len(ss) == 0 {
+			alpha = math.Min(1, 1/maxAbs(dir))
+		}
+		xnew := make([]float64, n)
+		var fnew float64
+		accepted := false
+		for k := 0; k < 60; k++ {
+			for i := range xnew {
+				xnew[i] = x[i] + alpha*dir[i]
+			}
+			project(xnew)
+			decrease := 0.0
+			for i := range xnew {
+				decrease += g[i] * (xnew[i] - x[i])
+			}
+			fnew = obj.value(xnew)
+			if fnew <= f+1e-4*decrease {
+				accepted = true
+				break
+			}
+			alpha /= 2
+		}
+		if !accepted {
+			failure = ErrLineSearch
+			break
+		}
+		gnew := make([]float64, n)
+		obj.gradient(xnew, gnew)
+		s := make([]float64, n)
+		y := make([]float64, n)
+		for i := range s {
+			s[i] = xnew[i] - x[i]
+			y[i] = gnew[i] - g[i]
+		}
+		if sy := dot(s, y); sy > epsilon*dot(y, y) {
+			ss = append(ss, s)
+			ys = append(ys, y)
+			rhos = append(rhos, 1/sy)
+			if len(ss) > opts.Memory {
+				ss, ys, rhos = ss[1:], ys[1:], rhos[1:]
+			}
+		}
+		reduction := (f - fnew) / math.Max(math.Max(math.Abs(f), math.Abs(fnew)), 1)
+		x, f, g = xnew, fnew, gnew
+		if reduction <= opts.FuncTol {
+			converged = true
+			iter++
+			break
+		}
+	}
+	r, err := obj.result(x, f, g)
+	if err != nil {
+		return nil, err
+	}
+	r.Iterations = iter
+	r.Converged = converged
+	switch {
+	case failure != nil:
+		return r, failure
+	case !converged:
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
+
+// twoLoop stores in dir the limited-memory BFGS direction -H g
+// restricted to the free parameters.
+func twoLoop(dir, g []float64, free []bool, ss, ys [][]float64, rhos []float64) {
+	for i := range dir {
+		if free[i] {
+			dir[i] = -g[i]
+		} else {
+			dir[i] = 0
+		}
+	}
+	m := len(ss)
+	alphas := make([]float64, m)
+	for k := m - 1; k >= 0; k-- {
+		alphas[k] = rhos[k] * maskedDot(ss[k], dir, free)
+		for i := range dir {
+			if free[i] {
+				dir[i] -= alphas[k] * ys[k][i]
+			}
+		}
+	}
+	if m > 0 {
+		gamma := dot(ss[m-1], ys[m-1]) / dot(ys[m-1], ys[m-1])
+		for i := range dir {
+			dir[i] *= gamma
+		}
+	}
+	for k := 0; k < m; k++ {
+		beta := rhos[k] * maskedDot(ys[k], dir, free)
+		for i := range dir {
+			if free[i] {
+				dir[i] += (alphas[k] - beta) * ss[k][i]
+			}
+		}
+	}
+}
+
+func maskedDot(a, b []float64, mask []bool) float64 {
+	sum := 0.0
+	for i := range a {
+		if mask[i] {
+			sum += a[i] * b[i]
+		}
+	}
+	return sum
+}
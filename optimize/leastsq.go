@@ -0,0 +1,325 @@
+package optimize
+
+import (
+	"context"
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/ctxio"
+	"github.com/jimmyskull/math/internal/linalg"
+)
+
+// LeastSquaresProblem is a vector of residuals whose sum of squares is
+// to be minimized.
+type LeastSquaresProblem struct {
+	// Residuals stores in r, a vector of M items, the residuals at x.
+	Residuals func(x, r *array.Dense)
+
+	// Jacobian optionally stores in jac, an M-by-N matrix for N
+	// parameters, the derivatives of the residuals with respect to the
+	// row-major items of x.  It is approximated by forward differences
+	// when nil.
+	Jacobian func(x, jac *array.Dense)
+
+	// M is the number of residuals.
+	M int
+}
+
+// LeastSquaresResult is the outcome of a nonlinear least squares fit.
+type LeastSquaresResult struct {
+	// X is the solution, with the shape of the initial guess.
+	X *array.Dense
+
+	// Cost is half the sum of the squared residuals at X.
+	Cost float64
+
+	// Residuals and Jacobian are evaluated at X.
+	Residuals *array.Dense
+	Jacobian  *array.Dense
+
+	// Iterations and Evaluations count the iterations and the calls to
+	// the residual function.
+	Iterations  int
+	Evaluations int
+
+	// Converged is set when the tolerances were met.
+	Converged bool
+}
+
+// leastSquares evaluates a problem on flat vectors.
+type leastSquares struct {
+	problem     LeastSquaresProblem
+	x, r, jac   *array.Dense
+	evaluations int
+}
+
+func (l *leastSquares) residuals(x, r []float64) {
+	l.x.Data = x
+	l.r.Data = r
+	l.evaluations++
+	l.problem.Residuals(l.x, l.r)
+}
+
+// jacobian stores in jac the M-by-N Jacobian at x, whose residuals
+// are r.
+func (l *leastSquares) jacobian(x, r, jac []float64) {
+	m, n := len(r), len(x)
+	if l.problem.Jacobian != nil {
+		l.x.Data = x
+		l.jac.Data = jac
+		l.problem.Jacobian(l.x, l.jac)
+		return
+	}
+	tmp := append([]float64(nil), x...)
+	rh := make([]float64, m)
+	for j := 0; j < n; j++ {
+		h := math.Sqrt(epsilon) * math.Max(1, math.Abs(x[j]))
+		tmp[j] = x[j] + h
+		l.residuals(tmp, rh)
+		tmp[j] = x[j]
+		for i := 0; i < m; i++ {
+			jac[i*n+j] = (rh[i] - r[i]) / h
+		}
+	}
+}
+
+// LevenbergMarquardt solves a nonlinear least squares problem with the
+// Levenberg-Marquardt method, starting from x0.
+func LevenbergMarquardt(
+	p LeastSquaresProblem, x0 *array.Dense, opts *Options,
+) (*LeastSquaresResult, error) {
+	return LevenbergMarquardtContext(context.Background(), p, x0, opts)
+}
+
+// LevenbergMarquardtContext is like LevenbergMarquardt, but stops early
+// and returns an error wrapping the error of ctx once it is done.  The
+// context is checked at every iteration and during the factorization of
+// the normal equations.
+func LevenbergMarquardtContext(
+	ctx context.Context, p LeastSquaresProblem, x0 *array.Dense, opts *Options,
+) (*LeastSquaresResult, error) {
+	x := x0.Values()
+	m, n := p.M, len(x)
+	if m < 1 {
+		return nil, ErrInvalidInput
+	}
+	opts = options(opts, n)
+	attrs := array.Contiguous | array.Writeable | array.RowMajorLayout
+	xd, err := view(x0.Shape, nil)
+	if err != nil {
+		return nil, err
+	}
+	rd, err := array.NewDense(array.Shape{m}, attrs)
+	if err != nil {
+		return nil, err
+	}
+	jd, err := array.NewDense(array.Shape{m, n}, attrs)
+	if err != nil {
+		return nil, err
+	}
+	ls := &leastSquares{problem: p, x: xd, r: rd, jac: jd}
+	r := make([]float64, m)
+	jac := make([]float64, m*n)
+	ls.residuals(x, r)
+	ls.jacobian(x, r, jac)
+	cost := dot(r, r) / 2
+	jtj := make([]float64, n*n)
+	g := make([]float64, n)
+	normal := func() {
+		for a := 0; a < n; a++ {
+			g[a] = 0
+			for i := 0; i < m; i++ {
+				g[a] += jac[i*n+a] * r[i]
+			}
+			for b := 0; b < n; b++ {
+				sum := 0.0
+				for i := 0; i < m; i++ {
+					sum += jac[i*n+a] * jac[i*n+b]
+				}
+				jtj[a*n+b] = sum
+			}
+		}
+	}
+	normal()
+	lambda := 0.0
+	for a := 0; a < n; a++ {
+		lambda = math.Max(lambda, jtj[a*n+a])
+	}
+	lambda *= 1e-3
+	nu := 2.0
+	a := make([]float64, n*n)
+	step := make([]float64, n)
+	xnew := make([]float64, n)
+	rnew := make([]float64, m)
+	iter := 0
+	converged := false
+	for ; iter < opts.MaxIter; iter++ {
+		if ctx.Err() != nil {
+			return nil, ctxio.Canceled(ctx, "least squares", nil)
+		}
+		if cost == 0 || maxAbs(g) == 0 {
+			converged = true
+			break
+		}
+		copy(a, jtj)
+		for k := 0; k < n; k++ {
+			// Marquardt scaling by the diagonal of the normal matrix.
+			a[k*n+k] += lambda * math.Max(jtj[k*n+k], 1e-12)
+		}
+		lu, ok, err := linalg.FactorizeContext(ctx, a, n)
+		if err != nil {
+			return nil, ctxio.Canceled(ctx, "least squares", err)
+		}
+		if !ok {
+			lambda *= nu
+			nu *= 2
+			continue
+		}
+		for k := range step {
+			step[k] = -g[k]
+		}
+		lu.Solve(step)
+		for k := range xnew {
+			xnew[k] = x[k] + step[k]
+		}
+		ls.residuals(xnew, rnew)
+		costNew := dot(rnew, rnew) / 2
+		// Reduction predicted by the linearized model, simplified with
+		// (JᵀJ + λD) step = -g.
+		predicted := 0.0
+		for k := range step {
+			predicted += step[k] * ((a[k*n+k]-jtj[k*n+k])*step[k] - g[k])
+		}
+		predicted /= 2
+		if rho := (cost - costNew) / predicted; costNew < cost && rho > 0 {
+			small := math.Sqrt(dot(step, step)) <=
+				opts.XTol*(math.Sqrt(dot(x, x))+opts.XTol)
+			flat := cost-costNew <= opts.FuncTol*cost
+			x, xnew = xnew, x
+			r, rnew = rnew, r
+			cost = costNew
+			ls.jacobian(x, r, jac)
+			normal()
+			lambda *= math.Max(1./3, 1-math.Pow(2*rho-1, 3))
+			nu = 2
+			if small || flat {
+				converged = true
+				iter++
+				break
+			}
+		} else {
+			lambda *= nu
+			nu *= 2
+		}
+	}
+	result := &LeastSquaresResult{
+		Cost:        cost,
+		Iterations:  iter,
+		Evaluations: ls.evaluations,
+		Converged:   converged,
+	}
+	if result.X, err = array.NewDenseWithValues(
+		x0.Shape, x, array.DefaultAttributes); err != nil {
+		return nil, err
+	}
+	if result.Residuals, err = array.NewDenseWithValues(
+		array.Shape{m}, r, array.DefaultAttributes); err != nil {
+		return nil, err
+	}
+	if result.Jacobian, err = array.NewDenseWithValues(
+		array.Shape{m, n}, jac, array.DefaultAttributes); err != nil {
+		return nil, err
+	}
+	if !converged {
+		return result, ErrNotConverged
+	}
+	return result, nil
+}
+
+// CurveFit fits the parameters of a model to data by nonlinear least
+// squares, starting from the guess p0.  The model stores in y its
+// predictions at xdata, given as x, for the parameters p; y has the
+// shape of ydata.  Residuals are weighted by the inverse of sigma, the
+// standard deviations of ydata, unless sigma is nil.  It returns the
+// optimal parameters and their estimated covariance matrix, scaled by
+// the reduced chi-square of the fit.  The covariance is filled with
+// infinities when it cannot be estimated.
+func CurveFit(
+	model func(x, p, y *array.Dense),
+	xdata, ydata, p0, sigma *array.Dense,
+	opts *Options,
+) (*array.Dense, *array.Dense, error) {
+	return CurveFitContext(context.Background(), model, xdata, ydata, p0, sigma, opts)
+}
+
+// CurveFitContext is like CurveFit, but stops early and returns an
+// error wrapping the error of ctx once it is done.
+func CurveFitContext(
+	ctx context.Context,
+	model func(x, p, y *array.Dense),
+	xdata, ydata, p0, sigma *array.Dense,
+	opts *Options,
+) (*array.Dense, *array.Dense, error) {
+	observed := ydata.Values()
+	m := len(observed)
+	var weights []float64
+	if sigma != nil {
+		if sigma.Size() != m {
+			return nil, nil, ErrInvalidInput
+		}
+		weights = sigma.Values()
+	}
+	predicted, err := view(ydata.Shape, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	problem := LeastSquaresProblem{
+		M: m,
+		Residuals: func(p, r *array.Dense) {
+			predicted.Data = r.Data
+			model(xdata, p, predicted)
+			for i := range r.Data {
+				r.Data[i] -= observed[i]
+				if weights != nil {
+					r.Data[i] /= weights[i]
+				}
+			}
+		},
+	}
+	fit, err := LevenbergMarquardtContext(ctx, problem, p0, opts)
+	if fit == nil {
+		return nil, nil, err
+	}
+	n := fit.X.Size()
+	jac := fit.Jacobian.Values()
+	jtj := make([]float64, n*n)
+	for a := 0; a < n; a++ {
+		for b := 0; b < n; b++ {
+			for i := 0; i < m; i++ {
+				jtj[a*n+b] += jac[i*n+a] * jac[i*n+b]
+			}
+		}
+	}
+	cov := make([]float64, n*n)
+	lu, ok, ferr := linalg.FactorizeContext(ctx, jtj, n)
+	if ferr != nil {
+		return nil, nil, ctxio.Canceled(ctx, "curve fit", ferr)
+	}
+	if ok && m > n {
+		cov = lu.Inverse()
+		chi2 := 2 * fit.Cost / float64(m-n)
+		for i := range cov {
+			cov[i] *= chi2
+		}
+	} else {
+		for i := range cov {
+			cov[i] = math.Inf(1)
+		}
+	}
+	pcov, cerr := array.NewDenseWithValues(
+		array.Shape{n, n}, cov, array.DefaultAttributes)
+	if cerr != nil {
+		return nil, nil, cerr
+	}
+	return fit.X, pcov, err
+}
@@ -0,0 +1,121 @@
+package optimize_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/optimize"
+)
+
+func exponential(x, p, y *array.Dense) {
+	for i, v := range x.Data {
+		y.Data[i] = p.Data[0]*math.Exp(-p.Data[1]*v) + p.Data[2]
+	}
+}
+
+func TestLevenbergMarquardt(t *testing.T) {
+	// Rosenbrock's function as a sum of squares.
+	p := optimize.LeastSquaresProblem{
+		M: 2,
+		Residuals: func(x, r *array.Dense) {
+			r.Data[0] = 10 * (x.Data[1] - x.Data[0]*x.Data[0])
+			r.Data[1] = 1 - x.Data[0]
+		},
+	}
+	r, err := optimize.LevenbergMarquardt(p, arraytest.Vector(t, -1.2, 1), nil)
+	if assert.Nil(t, err) {
+		assert.True(t, r.Converged)
+		assert.InDeltaSlice(t, []float64{1, 1}, r.X.Data, 1e-6)
+		assert.Equal(t, array.Shape{2, 2}, r.Jacobian.Shape)
+	}
+	p.Jacobian = func(x, jac *array.Dense) {
+		jac.Data[0], jac.Data[1] = -20*x.Data[0], 10
+		jac.Data[2], jac.Data[3] = -1, 0
+	}
+	r, err = optimize.LevenbergMarquardt(p, arraytest.Vector(t, -1.2, 1), nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{1, 1}, r.X.Data, 1e-6)
+	}
+}
+
+func line(x, p, y *array.Dense) {
+	for i, v := range x.Data {
+		y.Data[i] = p.Data[0] + p.Data[1]*v
+	}
+}
+
+func TestCurveFit(t *testing.T) {
+	xdata, _ := array.Linspace(0, 4, 50, true)
+	ydata, _ := array.NewDense(array.Shape{50}, array.DefaultAttributes)
+	line(xdata, arraytest.Vector(t, 0.5, 2), ydata)
+	// Deterministic noise.
+	for i := range ydata.Data {
+		ydata.Data[i] += 0.1 * math.Sin(float64(7*i))
+	}
+	popt, pcov, err := optimize.CurveFit(
+		line, xdata, ydata, arraytest.Vector(t, 1, 1), nil, nil)
+	if assert.Nil(t, err) {
+		// The covariance of a linear model is s² (XᵀX)⁻¹, where the rows
+		// of X are {1, x} and s² is the residual variance.
+		m := float64(len(xdata.Data))
+		var sx, sxx, rss float64
+		for i, x := range xdata.Data {
+			r := ydata.Data[i] - popt.Data[0] - popt.Data[1]*x
+			sx += x
+			sxx += x * x
+			rss += r * r
+		}
+		s2 := rss / (m - 2)
+		det := m*sxx - sx*sx
+		expected := [][]float64{
+			{s2 * sxx / det, -s2 * sx / det},
+			{-s2 * sx / det, s2 * m / det},
+		}
+		assert.Equal(t, array.Shape{2, 2}, pcov.Shape)
+		for i := range expected {
+			for j, want := range expected[i] {
+				v, _ := pcov.Get(array.Indices{i, j})
+				assert.InDelta(t, want, v, 1e-6*math.Abs(want))
+			}
+		}
+	}
+	truth := arraytest.Vector(t, 2.5, 1.3, 0.5)
+	// Exact data gives an exact fit and a zero covariance.
+	exponential(xdata, truth, ydata)
+	sigma, _ := array.NewDense(array.Shape{50}, array.DefaultAttributes)
+	sigma.Fill(0.1, 0)
+	popt, pcov, err = optimize.CurveFit(
+		exponential, xdata, ydata, arraytest.Vector(t, 1, 1, 1), sigma, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, truth.Data, popt.Data, 1e-8)
+		assert.InDelta(t, 0.0, pcov.Data[0], 1e-12)
+	}
+	_, _, err = optimize.CurveFit(
+		exponential, xdata, ydata, arraytest.Vector(t, 1, 1, 1), arraytest.Vector(t, 1), nil)
+	assert.ErrorIs(t, err, optimize.ErrInvalidInput)
+}
+
+func TestLeastSquaresContext(t *testing.T) {
+	xdata, _ := array.Linspace(0, 4, 50, true)
+	ydata, _ := array.NewDense(array.Shape{50}, array.DefaultAttributes)
+	line(xdata, arraytest.Vector(t, 0.5, 2), ydata)
+	ctx, cancel := context.WithCancel(context.Background())
+	popt, _, err := optimize.CurveFitContext(
+		ctx, line, xdata, ydata, arraytest.Vector(t, 1, 1), nil, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{0.5, 2}, popt.Data, 1e-8)
+	}
+	// The model cancels the fit after its first evaluation.
+	stop := func(x, p, y *array.Dense) {
+		line(x, p, y)
+		cancel()
+	}
+	_, _, err = optimize.CurveFitContext(
+		ctx, stop, xdata, ydata, arraytest.Vector(t, 1, 1), nil, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
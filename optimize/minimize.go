@@ -0,0 +1,319 @@
+package optimize
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Problem is a function to be minimized.
+type Problem struct {
+	// Func returns the value of the objective at x.
+	Func func(x *array.Dense) float64
+
+	// Grad optionally stores in grad the gradient of the objective at
+	// x.  It is approximated by central differences when nil.
+	Grad func(x, grad *array.Dense)
+}
+
+// Options controls the minimizers.  Zero values are replaced by those
+// in DefaultOptions.
+type Options struct {
+	// GradTol stops gradient-based methods once the largest component
+	// of the (projected) gradient falls below it.
+	GradTol float64
+
+	// FuncTol stops Nelder-Mead once the function values at the simplex
+	// vertices differ by less than it, and stops other methods once the
+	// relative reduction of the objective falls below it.
+	FuncTol float64
+
+	// XTol stops Nelder-Mead once the simplex vertices differ by less
+	// than it, and stops Levenberg-Marquardt once the relative step
+	// size falls below it.
+	XTol float64
+
+	// MaxIter limits the number of iterations.  Zero means 200 times the
+	// number of parameters.
+	MaxIter int
+
+	// Memory is the number of correction pairs kept by LBFGSB.
+	Memory int
+}
+
+// DefaultOptions holds the default tolerances of the minimizers.
+var DefaultOptions = Options{
+	GradTol: 1e-5,
+	FuncTol: 1e-8,
+	XTol:    1e-8,
+	Memory:  10,
+}
+
+func options(opts *Options, n int) *Options {
+	o := DefaultOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.GradTol == 0 {
+		o.GradTol = DefaultOptions.GradTol
+	}
+	if o.FuncTol == 0 {
+		o.FuncTol = DefaultOptions.FuncTol
+	}
+	if o.XTol == 0 {
+		o.XTol = DefaultOptions.XTol
+	}
+	if o.Memory == 0 {
+		o.Memory = DefaultOptions.Memory
+	}
+	if o.MaxIter == 0 {
+		o.MaxIter = 200 * n
+		if o.MaxIter == 0 {
+			o.MaxIter = 200
+		}
+	}
+	return &o
+}
+
+// Result is the outcome of a minimization.
+type Result struct {
+	// X is the location of the minimum, with the shape of the initial
+	// guess.
+	X *array.Dense
+
+	// F is the value of the objective at X.
+	F float64
+
+	// Grad is the gradient at X, or nil for derivative-free methods.
+	Grad *array.Dense
+
+	// Iterations and Evaluations count the iterations and the calls to
+	// the objective function.
+	Iterations  int
+	Evaluations int
+
+	// Converged is set when the tolerances were met.
+	Converged bool
+}
+
+// objective evaluates a problem on flat parameter vectors.
+type objective struct {
+	problem     Problem
+	shape       array.Shape
+	x, grad     *array.Dense
+	evaluations int
+}
+
+func newObjective(p Problem, shape array.Shape) (*objective, error) {
+	x, err := view(shape, nil)
+	if err != nil {
+		return nil, err
+	}
+	grad, err := view(shape, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &objective{problem: p, shape: shape, x: x, grad: grad}, nil
+}
+
+// view returns an array with the given shape whose row-major items are
+// stored in data.
+func view(shape array.Shape, data []float64) (*array.Dense, error) {
+	d, err := array.NewDense(shape,
+		array.Contiguous|array.Writeable|array.RowMajorLayout)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		d.Data = data
+	}
+	return d, nil
+}
+
+func (o *objective) value(x []float64) float64 {
+	o.x.Data = x
+	o.evaluations++
+	return o.problem.Func(o.x)
+}
+
+// gradient stores in g the gradient at x.
+func (o *objective) gradient(x, g []float64) {
+	if o.problem.Grad != nil {
+		o.x.Data = x
+		o.grad.Data = g
+		o.problem.Grad(o.x, o.grad)
+		return
+	}
+	tmp := append([]float64(nil), x...)
+	for i := range x {
+		h := math.Cbrt(epsilon) * math.Max(1, math.Abs(x[i]))
+		tmp[i] = x[i] + h
+		fp := o.value(tmp)
+		tmp[i] = x[i] - h
+		fm := o.value(tmp)
+		tmp[i] = x[i]
+		g[i] = (fp - fm) / (2 * h)
+	}
+}
+
+// result builds a Result from flat vectors.
+func (o *objective) result(x []float64, f float64, g []float64) (*Result, error) {
+	r := &Result{F: f, Evaluations: o.evaluations}
+	var err error
+	r.X, err = array.NewDenseWithValues(o.shape, x, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	if g != nil {
+		r.Grad, err = array.NewDenseWithValues(o.shape, g, array.DefaultAttributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// NelderMead minimizes a function with the derivative-free downhill
+// simplex method of Nelder and Mead, starting from x0.
+func NelderMead(p Problem, x0 *array.Dense, opts *Options) (*Result, error) {
+	obj, err := newObjective(p, x0.Shape)
+	if err != nil {
+		return nil, err
+	}
+	start := x0.Values()
+	n := len(start)
+	opts = options(opts, n)
+	const (
+		reflection  = 1.0
+		expansion   = 2.0
+		contraction = 0.5
+		shrinkage   = 0.5
+	)
+	// The initial simplex perturbs each parameter by 5%.
+	simplex := make([][]float64, n+1)
+	values := make([]float64, n+1)
+	for i := range simplex {
+		simplex[i] = append([]float64(nil), start...)
+		if i > 0 {
+			if simplex[i][i-1] != 0 {
+				simplex[i][i-1] *= 1.05
+			} else {
+				simplex[i][i-1] = 0.00025
+			}
+		}
+		values[i] = obj.value(simplex[i])
+	}
+	order := func() {
+		sort.Sort(byValue{simplex, values})
+	}
+	order()
+	centroid := make([]float64, n)
+	point := func(coef float64) []float64 {
+		x := make([]float64, n)
+		for j := range x {
+			x[j] = centroid[j] + coef*(simplex[n][j]-centroid[j])
+		}
+		return x
+	}
+	iter := 0
+	converged := false
+	for ; iter < opts.MaxIter; iter++ {
+		xSpread, fSpread := 0.0, 0.0
+		for i := 1; i <= n; i++ {
+			fSpread = math.Max(fSpread, math.Abs(values[i]-values[0]))
+			for j := 0; j < n; j++ {
+				xSpread = math.Max(xSpread, math.Abs(simplex[i][j]-simplex[0][j]))
+			}
+		}
+		if xSpread <= opts.XTol && fSpread <= opts.FuncTol {
+			converged = true
+			break
+		}
+		for j := range centroid {
+			centroid[j] = 0
+			for i := 0; i < n; i++ {
+				centroid[j] += simplex[i][j] / float64(n)
+			}
+		}
+		xr := point(-reflection)
+		fr := obj.value(xr)
+		shrink := false
+		switch {
+		case fr < values[0]:
+			xe := point(-reflection * expansion)
+			if fe := obj.value(xe); fe < fr {
+				simplex[n], values[n] = xe, fe
+			} else {
+				simplex[n], values[n] = xr, fr
+			}
+		case fr < values[n-1]:
+			simplex[n], values[n] = xr, fr
+		case fr < values[n]:
+			// Outside contraction.
+			xc := point(-reflection * contraction)
+			if fc := obj.value(xc); fc <= fr {
+				simplex[n], values[n] = xc, fc
+			} else {
+				shrink = true
+			}
+		default:
+			// Inside contraction.
+			xc := point(contraction)
+			if fc := obj.value(xc); fc < values[n] {
+				simplex[n], values[n] = xc, fc
+			} else {
+				shrink = true
+			}
+		}
+		if shrink {
+			for i := 1; i <= n; i++ {
+				for j := 0; j < n; j++ {
+					simplex[i][j] = simplex[0][j] +
+						shrinkage*(simplex[i][j]-simplex[0][j])
+				}
+				values[i] = obj.value(simplex[i])
+			}
+		}
+		order()
+	}
+	r, err := obj.result(simplex[0], values[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Iterations = iter
+	r.Converged = converged
+	if !converged {
+		return r, ErrNotConverged
+	}
+	return r, nil
+}
+
+// byValue sorts simplex vertices by their function values.
+type byValue struct {
+	points [][]float64
+	values []float64
+}
+
+func (s byValue) Len() int           { return len(s.values) }
+func (s byValue) Less(i, j int) bool { return s.values[i] < s.values[j] }
+func (s byValue) Swap(i, j int) {
+	s.points[i], s.points[j] = s.points[j], s.points[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func maxAbs(v []float64) float64 {
+	m := 0.0
+	for _, x := range v {
+		m = math.Max(m, math.Abs(x))
+	}
+	return m
+}
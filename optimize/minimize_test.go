@@ -0,0 +1,108 @@
+package optimize_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/optimize"
+)
+
+func rosenbrock(x *array.Dense) float64 {
+	sum := 0.0
+	for i := 0; i+1 < len(x.Data); i++ {
+		a, b := x.Data[i], x.Data[i+1]
+		sum += 100*(b-a*a)*(b-a*a) + (1-a)*(1-a)
+	}
+	return sum
+}
+
+func rosenbrockGrad(x, grad *array.Dense) {
+	n := len(x.Data)
+	for i := range grad.Data {
+		grad.Data[i] = 0
+	}
+	for i := 0; i+1 < n; i++ {
+		a, b := x.Data[i], x.Data[i+1]
+		grad.Data[i] += -400*a*(b-a*a) - 2*(1-a)
+		grad.Data[i+1] += 200 * (b - a*a)
+	}
+}
+
+func TestNelderMead(t *testing.T) {
+	p := optimize.Problem{Func: rosenbrock}
+	r, err := optimize.NelderMead(p, arraytest.Vector(t, -1.2, 1), nil)
+	if assert.Nil(t, err) {
+		assert.True(t, r.Converged)
+		assert.InDeltaSlice(t, []float64{1, 1}, r.X.Data, 1e-6)
+		assert.Nil(t, r.Grad)
+	}
+	opts := optimize.Options{MaxIter: 5}
+	r, err = optimize.NelderMead(p, arraytest.Vector(t, -1.2, 1), &opts)
+	assert.ErrorIs(t, err, optimize.ErrNotConverged)
+	assert.Equal(t, 5, r.Iterations)
+}
+
+func TestBFGS(t *testing.T) {
+	x0 := arraytest.Vector(t, -1.2, 1, -0.5, 0.8)
+	for _, p := range []optimize.Problem{
+		{Func: rosenbrock, Grad: rosenbrockGrad},
+		{Func: rosenbrock},
+	} {
+		r, err := optimize.BFGS(p, x0, nil)
+		if assert.Nil(t, err) {
+			assert.True(t, r.Converged)
+			assert.InDeltaSlice(t, []float64{1, 1, 1, 1}, r.X.Data, 1e-4)
+			assert.Less(t, math.Abs(r.Grad.Data[0]), 1e-5)
+			assert.Equal(t, x0.Shape, r.X.Shape)
+		}
+	}
+	// Parameters may have any shape.
+	quadratic := func(x *array.Dense) float64 {
+		sum := 0.0
+		for i, v := range x.Values() {
+			sum += float64(i+1) * (v - 1) * (v - 1)
+		}
+		return sum
+	}
+	x0, _ = array.NewDense(array.Shape{2, 2}, array.DefaultAttributes)
+	r, err := optimize.BFGS(optimize.Problem{Func: quadratic}, x0, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, array.Shape{2, 2}, r.X.Shape)
+		assert.InDeltaSlice(t, []float64{1, 1, 1, 1}, r.X.Data, 1e-6)
+	}
+}
+
+func TestLBFGSB(t *testing.T) {
+	p := optimize.Problem{Func: rosenbrock, Grad: rosenbrockGrad}
+	r, err := optimize.LBFGSB(p, arraytest.Vector(t, -1.2, 1), nil, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{1, 1}, r.X.Data, 1e-3)
+	}
+	// The unconstrained minimum lies outside the box.
+	bounds := &optimize.Bounds{
+		Lower: arraytest.Vector(t, -2, -2),
+		Upper: arraytest.Vector(t, 0.5, 2),
+	}
+	r, err = optimize.LBFGSB(p, arraytest.Vector(t, -1.2, 1), bounds, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.5, r.X.Data[0], 1e-9)
+		assert.InDelta(t, 0.25, r.X.Data[1], 1e-4)
+	}
+	// Only a lower bound.
+	quadratic := func(x *array.Dense) float64 {
+		return (x.Data[0]+3)*(x.Data[0]+3) + (x.Data[1]-2)*(x.Data[1]-2)
+	}
+	bounds = &optimize.Bounds{Lower: arraytest.Vector(t, 0, math.Inf(-1))}
+	r, err = optimize.LBFGSB(
+		optimize.Problem{Func: quadratic}, arraytest.Vector(t, 1, 1), bounds, nil)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{0, 2}, r.X.Data, 1e-5)
+	}
+	bounds = &optimize.Bounds{Lower: arraytest.Vector(t, 1, 1), Upper: arraytest.Vector(t, 0, 2)}
+	_, err = optimize.LBFGSB(p, arraytest.Vector(t, -1.2, 1), bounds, nil)
+	assert.ErrorIs(t, err, optimize.ErrInvalidBounds)
+}
@@ -0,0 +1,182 @@
+// Package optimize provides root finding, minimization and nonlinear
+// least squares for functions of parameters stored in Dense arrays.
+package optimize
+
+import (
+	"math"
+)
+
+const epsilon = 2.220446049250313e-16
+
+// RootOptions controls the accuracy of scalar root finders.  A root x
+// is accepted once it is known within XTol + RTol*|x|.
+type RootOptions struct {
+	XTol    float64
+	RTol    float64
+	MaxIter int
+}
+
+// DefaultRootOptions is used when no options are given.
+var DefaultRootOptions = RootOptions{
+	XTol:    2e-12,
+	RTol:    8.881784197001252e-16,
+	MaxIter: 100,
+}
+
+func rootOptions(opts *RootOptions) *RootOptions {
+	if opts == nil {
+		return &DefaultRootOptions
+	}
+	return opts
+}
+
+// Bisect finds a root of f within [a, b] by bisection.  The values of f
+// at a and b must have opposite signs.
+func Bisect(f func(float64) float64, a, b float64, opts *RootOptions) (float64, error) {
+	opts = rootOptions(opts)
+	fa, fb := f(a), f(b)
+	switch {
+	case fa == 0:
+		return a, nil
+	case fb == 0:
+		return b, nil
+	case math.Signbit(fa) == math.Signbit(fb):
+		return math.NaN(), ErrNotBracketed
+	}
+	mid := a
+	for i := 0; i < opts.MaxIter; i++ {
+		mid = a + (b-a)/2
+		fm := f(mid)
+		if fm == 0 || math.Abs(b-a)/2 < opts.XTol+opts.RTol*math.Abs(mid) {
+			return mid, nil
+		}
+		if math.Signbit(fm) == math.Signbit(fa) {
+			a, fa = mid, fm
+		} else {
+			b = mid
+		}
+	}
+	return mid, ErrNotConverged
+}
+
+// Brent finds a root of f within [a, b] with Brent's method, which
+// combines bisection, the secant method and inverse quadratic
+// interpolation.  The values of f at a and b must have opposite signs.
+func Brent(f func(float64) float64, a, b float64, opts *RootOptions) (float64, error) {
+	opts = rootOptions(opts)
+	fa, fb := f(a), f(b)
+	switch {
+	case fa == 0:
+		return a, nil
+	case fb == 0:
+		return b, nil
+	case math.Signbit(fa) == math.Signbit(fb):
+		return math.NaN(), ErrNotBracketed
+	}
+	c, fc := b, fb
+	var d, e float64
+	for i := 0; i < opts.MaxIter; i++ {
+		if (fb > 0) == (fc > 0) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol := 2*epsilon*math.Abs(b) + (opts.XTol+opts.RTol*math.Abs(b))/2
+		xm := (c - b) / 2
+		if math.Abs(xm) <= tol || fb == 0 {
+			return b, nil
+		}
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			// Attempt inverse quadratic interpolation, or the secant
+			// method when only two points are distinct.
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2 * xm * s
+				q = 1 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*xm*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*xm*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = xm
+				e = d
+			}
+		} else {
+			d = xm
+			e = d
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, xm)
+		}
+		fb = f(b)
+	}
+	return b, ErrNotConverged
+}
+
+// Newton finds a root of f near x0 with Newton's method, using the
+// derivative fprime.  When fprime is nil, the secant method is used.
+func Newton(
+	f, fprime func(float64) float64, x0 float64, opts *RootOptions,
+) (float64, error) {
+	opts = rootOptions(opts)
+	if fprime == nil {
+		return secant(f, x0, opts)
+	}
+	x := x0
+	for i := 0; i < opts.MaxIter; i++ {
+		fx := f(x)
+		if fx == 0 {
+			return x, nil
+		}
+		dfx := fprime(x)
+		if dfx == 0 {
+			return x, ErrZeroDerivative
+		}
+		step := fx / dfx
+		x -= step
+		if math.Abs(step) <= opts.XTol+opts.RTol*math.Abs(x) {
+			return x, nil
+		}
+	}
+	return x, ErrNotConverged
+}
+
+func secant(f func(float64) float64, x0 float64, opts *RootOptions) (float64, error) {
+	x1 := x0*(1+1e-4) + math.Copysign(1e-4, x0)
+	f0, f1 := f(x0), f(x1)
+	if math.Abs(f1) < math.Abs(f0) {
+		x0, x1, f0, f1 = x1, x0, f1, f0
+	}
+	for i := 0; i < opts.MaxIter; i++ {
+		if f1 == 0 {
+			return x1, nil
+		}
+		if f1 == f0 {
+			return x1, ErrZeroDerivative
+		}
+		x := x1 - f1*(x1-x0)/(f1-f0)
+		if math.Abs(x-x1) <= opts.XTol+opts.RTol*math.Abs(x) {
+			return x, nil
+		}
+		x0, f0 = x1, f1
+		x1, f1 = x, f(x)
+	}
+	return x1, ErrNotConverged
+}
@@ -0,0 +1,65 @@
+package optimize_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/optimize"
+)
+
+func cubic(x float64) float64 { return x*x*x - 2*x - 5 }
+
+const cubicRoot = 2.0945514815423265
+
+func TestBisect(t *testing.T) {
+	x, err := optimize.Bisect(cubic, 2, 3, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, cubicRoot, x, 1e-11)
+	}
+	_, err = optimize.Bisect(cubic, 3, 4, nil)
+	assert.ErrorIs(t, err, optimize.ErrNotBracketed)
+	opts := optimize.DefaultRootOptions
+	opts.MaxIter = 3
+	_, err = optimize.Bisect(cubic, 2, 3, &opts)
+	assert.ErrorIs(t, err, optimize.ErrNotConverged)
+}
+
+func TestBrent(t *testing.T) {
+	evaluations := 0
+	f := func(x float64) float64 {
+		evaluations++
+		return cubic(x)
+	}
+	x, err := optimize.Brent(f, 2, 3, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, cubicRoot, x, 1e-11)
+		assert.Less(t, evaluations, 15)
+	}
+	x, err = optimize.Brent(math.Cos, 0, 3, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.Pi/2, x, 1e-11)
+	}
+	x, err = optimize.Brent(cubic, cubicRoot, 3, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, cubicRoot, x, 1e-11)
+	}
+	_, err = optimize.Brent(cubic, -1, 1, nil)
+	assert.ErrorIs(t, err, optimize.ErrNotBracketed)
+}
+
+func TestNewton(t *testing.T) {
+	fprime := func(x float64) float64 { return 3*x*x - 2 }
+	x, err := optimize.Newton(cubic, fprime, 2, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, cubicRoot, x, 1e-11)
+	}
+	x, err = optimize.Newton(cubic, nil, 2, nil)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, cubicRoot, x, 1e-11)
+	}
+	_, err = optimize.Newton(func(x float64) float64 { return x*x + 1 },
+		func(x float64) float64 { return 2 * x }, 0, nil)
+	assert.ErrorIs(t, err, optimize.ErrZeroDerivative)
+}
@@ -0,0 +1,54 @@
+package signal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/signal"
+)
+
+func TestContext(t *testing.T) {
+	x := arraytest.Dense(t, array.Shape{4, 64}, make([]float64, 4*64)...)
+	kernel := arraytest.Vector(t, 1, 2, 1)
+	opts := signal.DefaultSpectralOptions
+	opts.SegmentLength = 16
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := []func() error{
+		func() error {
+			_, err := signal.ConvolveContext(ctx, x, kernel, -1, signal.Full, signal.FFT)
+			return err
+		},
+		func() error {
+			_, err := signal.CorrelateContext(ctx, x, kernel, -1, signal.Same, signal.Direct)
+			return err
+		},
+		func() error {
+			_, err := signal.ResampleContext(ctx, x, 32, -1)
+			return err
+		},
+		func() error {
+			_, _, err := signal.WelchContext(ctx, x, 1, &opts)
+			return err
+		},
+		func() error {
+			_, _, _, err := signal.SpectrogramContext(ctx, x, 1, &opts)
+			return err
+		},
+		func() error {
+			_, err := signal.STFTContext(ctx, x, 1, &opts)
+			return err
+		},
+	}
+	for i, call := range calls {
+		err := call()
+		assert.True(t, errors.Is(err, context.Canceled), "call %d: %v", i, err)
+		var e *array.Error
+		assert.True(t, errors.As(err, &e), "call %d", i)
+	}
+}
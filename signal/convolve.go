@@ -0,0 +1,157 @@
+package signal
+
+import (
+	"context"
+	"math/bits"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// ConvolveMode selects the part of a full convolution returned.
+type ConvolveMode int
+
+// Modes of convolution.
+const (
+	// Full returns the whole convolution, of length n + m - 1 for
+	// signals of lengths n and m.
+	Full ConvolveMode = iota
+	// Same returns the centre of the convolution, of the length of the
+	// signal.
+	Same
+	// Valid returns the part of the convolution that does not depend on
+	// zero padding, of length |n - m| + 1.
+	Valid
+)
+
+// Method is the algorithm computing a convolution.
+type Method int
+
+// Methods of convolution.
+const (
+	// Auto chooses the faster method from the lengths of the signals.
+	Auto Method = iota
+	// Direct sums the products of the items.
+	Direct
+	// FFT multiplies the Fourier transforms of the signals, which is
+	// faster for long signals at the price of rounding errors.
+	FFT
+)
+
+// Convolve returns the convolution of x along axis with a
+// one-dimensional kernel, restricted as selected by mode and computed
+// by the given method.
+func Convolve(x, kernel *array.Dense, axis int, mode ConvolveMode, method Method) (*array.Dense, error) {
+	return ConvolveContext(context.Background(), x, kernel, axis, mode, method)
+}
+
+// ConvolveContext is like Convolve, but stops early and returns an error
+// wrapping the error of ctx once it is done.
+func ConvolveContext(
+	ctx context.Context, x, kernel *array.Dense, axis int, mode ConvolveMode, method Method,
+) (*array.Dense, error) {
+	k, err := vector(kernel)
+	if err != nil {
+		return nil, err
+	}
+	return convolve(ctx, x, k, axis, mode, method)
+}
+
+// Correlate returns the cross-correlation of x along axis with a
+// one-dimensional kernel, that is, the convolution with the reversed
+// kernel, restricted as selected by mode and computed by the given
+// method.
+func Correlate(x, kernel *array.Dense, axis int, mode ConvolveMode, method Method) (*array.Dense, error) {
+	return CorrelateContext(context.Background(), x, kernel, axis, mode, method)
+}
+
+// CorrelateContext is like Correlate, but stops early and returns an
+// error wrapping the error of ctx once it is done.
+func CorrelateContext(
+	ctx context.Context, x, kernel *array.Dense, axis int, mode ConvolveMode, method Method,
+) (*array.Dense, error) {
+	k, err := vector(kernel)
+	if err != nil {
+		return nil, err
+	}
+	reverse(k)
+	return convolve(ctx, x, k, axis, mode, method)
+}
+
+func convolve(
+	ctx context.Context, x *array.Dense, k []float64, axis int, mode ConvolveMode, method Method,
+) (*array.Dense, error) {
+	n, err := axisLength(x, axis)
+	if err != nil {
+		return nil, err
+	}
+	m := len(k)
+	if n == 0 || m == 0 {
+		return nil, ErrInvalidLength
+	}
+	full := n + m - 1
+	var start, length int
+	switch mode {
+	case Full:
+		start, length = 0, full
+	case Same:
+		start, length = (full-n)/2, n
+	case Valid:
+		short := n
+		if m < n {
+			short = m
+		}
+		start, length = short-1, full-2*(short-1)
+	default:
+		return nil, ErrInvalidParameter
+	}
+	if method == Auto {
+		// The cost of a transform of length L is about L log L.
+		size := nextPow2(full)
+		method = Direct
+		if n > 1 && m > 1 && 4*size*bits.Len(uint(size)) < n*m {
+			method = FFT
+		}
+	}
+	var kernelFFT []complex128
+	if method == FFT {
+		padded := make([]complex128, nextPow2(full))
+		for i, v := range k {
+			padded[i] = complex(v, 0)
+		}
+		kernelFFT = fft(padded, false)
+	}
+	return array.ApplyAlongAxisContext(ctx, x, axis, length, func(dst, src []float64) {
+		if method == FFT {
+			padded := make([]complex128, len(kernelFFT))
+			for i, v := range src {
+				padded[i] = complex(v, 0)
+			}
+			padded = fft(padded, false)
+			for i := range padded {
+				padded[i] *= kernelFFT[i]
+			}
+			padded = fft(padded, true)
+			scale := float64(len(padded))
+			for i := range dst {
+				dst[i] = real(padded[start+i]) / scale
+			}
+			return
+		}
+		for i := range dst {
+			// Output item j sums src[p] k[j-p] over the overlap.
+			j := start + i
+			lo, hi := j-m+1, j
+			if lo < 0 {
+				lo = 0
+			}
+			if hi > n-1 {
+				hi = n - 1
+			}
+			sum := 0.0
+			for p := lo; p <= hi; p++ {
+				sum += src[p] * k[j-p]
+			}
+			dst[i] = sum
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/signal"
+)
+
+func TestConvolve(t *testing.T) {
+	x := arraytest.Vector(t, 1, 2, 3, 4)
+	k := arraytest.Vector(t, 1, 0, -1)
+	cases := []struct {
+		mode signal.ConvolveMode
+		want []float64
+	}{
+		{signal.Full, []float64{1, 2, 2, 2, -3, -4}},
+		{signal.Same, []float64{2, 2, 2, -3}},
+		{signal.Valid, []float64{2, 2}},
+	}
+	for _, c := range cases {
+		for _, method := range []signal.Method{signal.Auto, signal.Direct, signal.FFT} {
+			y, err := signal.Convolve(x, k, 0, c.mode, method)
+			if assert.NoError(t, err) {
+				assertValues(t, c.want, y, 1e-12)
+			}
+		}
+	}
+
+	y, err := signal.Correlate(x, arraytest.Vector(t, 1, 2), 0, signal.Valid, signal.Direct)
+	if assert.NoError(t, err) {
+		assertValues(t, []float64{5, 8, 11}, y, 1e-12)
+	}
+
+	_, err = signal.Convolve(x, arraytest.Vector(t), 0, signal.Full, signal.Direct)
+	assert.Equal(t, signal.ErrInvalidLength, err)
+}
+
+func TestConvolveMethods(t *testing.T) {
+	// Long signals along the last axis of a matrix, whose odd lengths
+	// need a transform of another length.
+	values := make([]float64, 2*301)
+	for i := range values {
+		values[i] = math.Sin(0.1*float64(i)) + float64(i%7)
+	}
+	x, _ := array.NewDenseWithValues(array.Shape{2, 301}, values, array.DefaultAttributes)
+	kernel := make([]float64, 57)
+	for i := range kernel {
+		kernel[i] = math.Cos(float64(i))
+	}
+	k := arraytest.Vector(t, kernel...)
+	for _, mode := range []signal.ConvolveMode{signal.Full, signal.Same, signal.Valid} {
+		direct, err := signal.Convolve(x, k, -1, mode, signal.Direct)
+		if !assert.NoError(t, err) {
+			return
+		}
+		fast, err := signal.Convolve(x, k, -1, mode, signal.FFT)
+		if assert.NoError(t, err) {
+			assert.Equal(t, direct.Shape, fast.Shape)
+			assertValues(t, direct.Values(), fast, 1e-9)
+		}
+	}
+}
+
+func TestResample(t *testing.T) {
+	n := 32
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.Cos(2 * math.Pi * 3 * float64(i) / float64(n))
+	}
+	for _, num := range []int{16, 45, 64} {
+		y, err := signal.Resample(arraytest.Vector(t, values...), num, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		want := make([]float64, num)
+		for i := range want {
+			want[i] = math.Cos(2 * math.Pi * 3 * float64(i) / float64(num))
+		}
+		assertValues(t, want, y, 1e-9)
+	}
+	_, err := signal.Resample(arraytest.Vector(t, values...), 0, 0)
+	assert.Equal(t, signal.ErrInvalidLength, err)
+}
+
+func TestDecimate(t *testing.T) {
+	n := 200
+	slow, mixed := make([]float64, n), make([]float64, n)
+	for i := range slow {
+		slow[i] = math.Sin(2 * math.Pi * 0.01 * float64(i))
+		mixed[i] = slow[i] + 0.5*math.Sin(2*math.Pi*0.45*float64(i))
+	}
+	y, err := signal.Decimate(arraytest.Vector(t, mixed...), 4, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	got := y.Values()
+	assert.Len(t, got, 50)
+	for i := 5; i < 45; i++ {
+		assert.InDelta(t, slow[4*i], got[i], 0.02)
+	}
+	_, err = signal.Decimate(arraytest.Vector(t, mixed...), 0, 0)
+	assert.Equal(t, signal.ErrInvalidParameter, err)
+}
@@ -0,0 +1,36 @@
+package signal
+
+import "errors"
+
+var (
+	// ErrInvalidLength is returned when the length of a window, a filter
+	// or a resampled signal is not positive.
+	ErrInvalidLength = errors.New("length must be positive")
+
+	// ErrInvalidParameter is returned when the order, ripple or shape
+	// parameter of a filter or window is outside its domain.
+	ErrInvalidParameter = errors.New("invalid filter parameter")
+
+	// ErrInvalidFrequency is returned when cutoff frequencies are not
+	// increasing between zero and the Nyquist frequency, or do not
+	// match the type of filter.
+	ErrInvalidFrequency = errors.New(
+		"cutoff frequencies must increase between zero and the Nyquist frequency")
+
+	// ErrNotVector is returned when a one-dimensional array is expected.
+	ErrNotVector = errors.New("array must be one-dimensional")
+
+	// ErrInvalidCoefficients is returned when the leading denominator
+	// coefficient of a filter is zero, or second-order sections do not
+	// have six coefficients.
+	ErrInvalidCoefficients = errors.New("invalid filter coefficients")
+
+	// ErrTooShort is returned when a signal is too short for an
+	// operation, such as the padding of Filtfilt.
+	ErrTooShort = errors.New("signal is too short")
+
+	// ErrInvalidSegments is returned when spectral segments overlap by
+	// as many items as they have, or a window does not match their
+	// length.
+	ErrInvalidSegments = errors.New("invalid segment length or overlap")
+)
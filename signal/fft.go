@@ -0,0 +1,114 @@
+package signal
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// nextPow2 returns the least power of two greater than or equal to n.
+func nextPow2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// fft returns the discrete Fourier transform of x, or its inverse
+// without the normalization by 1/n.  Lengths other than powers of two
+// use Bluestein's algorithm.
+func fft(x []complex128, inverse bool) []complex128 {
+	out := append([]complex128(nil), x...)
+	n := len(out)
+	if n <= 1 {
+		return out
+	}
+	if n&(n-1) == 0 {
+		radix2(out, inverse)
+		return out
+	}
+	return bluestein(out, inverse)
+}
+
+// radix2 transforms x in place, whose length is a power of two.
+func radix2(x []complex128, inverse bool) {
+	n := len(x)
+	// Bit-reversal permutation.
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < half; k++ {
+				if k%64 == 0 {
+					// Exact twiddles now and then limit rounding errors.
+					w = cmplx.Rect(1, sign*2*math.Pi*float64(k)/float64(size))
+				}
+				u, v := x[start+k], x[start+k+half]*w
+				x[start+k], x[start+k+half] = u+v, u-v
+				w *= step
+			}
+		}
+	}
+}
+
+// bluestein transforms x of any length as a convolution of length a
+// power of two.
+func bluestein(x []complex128, inverse bool) []complex128 {
+	n := len(x)
+	m := nextPow2(2*n - 1)
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		// k² is reduced modulo 2n to keep the angle accurate.
+		k2 := (int64(k) * int64(k)) % int64(2*n)
+		chirp[k] = cmplx.Rect(1, sign*math.Pi*float64(k2)/float64(n))
+	}
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for k := 0; k < n; k++ {
+		a[k] = x[k] * chirp[k]
+		b[k] = cmplx.Conj(chirp[k])
+		if k > 0 {
+			b[m-k] = b[k]
+		}
+	}
+	radix2(a, false)
+	radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	radix2(a, true)
+	out := make([]complex128, n)
+	for k := range out {
+		out[k] = a[k] * chirp[k] / complex(float64(m), 0)
+	}
+	return out
+}
+
+// rfft returns the first n/2 + 1 coefficients of the discrete Fourier
+// transform of x padded with zeros, or truncated, to n items.
+func rfft(x []float64, n int) []complex128 {
+	c := make([]complex128, n)
+	for i := 0; i < n && i < len(x); i++ {
+		c[i] = complex(x[i], 0)
+	}
+	return fft(c, false)[:n/2+1]
+}
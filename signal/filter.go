@@ -0,0 +1,248 @@
+package signal
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// vector returns the items of a one-dimensional array.
+func vector(d *array.Dense) ([]float64, error) {
+	if len(d.Shape) != 1 {
+		return nil, ErrNotVector
+	}
+	return d.Values(), nil
+}
+
+// axisLength returns the length of an axis of d, counting negative axes
+// from the last dimension.
+func axisLength(d *array.Dense, axis int) (int, error) {
+	ndim := len(d.Shape)
+	if axis < -ndim || axis >= ndim {
+		return 0, &array.AxisError{Axis: axis, NDim: ndim}
+	}
+	if axis < 0 {
+		axis += ndim
+	}
+	return d.Shape[axis], nil
+}
+
+// normalize returns the coefficients of a filter of the same length,
+// scaled so that the leading denominator coefficient is one.
+func normalize(b, a []float64) ([]float64, []float64, error) {
+	if len(b) == 0 || len(a) == 0 || a[0] == 0 {
+		return nil, nil, ErrInvalidCoefficients
+	}
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	nb, na := make([]float64, n), make([]float64, n)
+	for i, v := range b {
+		nb[i] = v / a[0]
+	}
+	for i, v := range a {
+		na[i] = v / a[0]
+	}
+	return nb, na, nil
+}
+
+// lfilter filters x into y, which may be the same slice, by the
+// transposed direct form II with normalized coefficients.  The state z
+// has len(b) - 1 items, updated in place.
+func lfilter(b, a, x, y, z []float64) {
+	n := len(z)
+	for i, xi := range x {
+		yi := b[0] * xi
+		if n > 0 {
+			yi += z[0]
+			for k := 0; k < n-1; k++ {
+				z[k] = b[k+1]*xi + z[k+1] - a[k+1]*yi
+			}
+			z[n-1] = b[n]*xi - a[n]*yi
+		}
+		y[i] = yi
+	}
+}
+
+// Lfilter returns x filtered along axis by the rational transfer
+// function with numerator b and denominator a, one-dimensional arrays of
+// coefficients in decreasing powers of z:
+//
+//	a[0] y[n] = Σ b[k] x[n-k] - Σ_{k≥1} a[k] y[n-k]
+//
+// starting from rest.
+func Lfilter(b, a, x *array.Dense, axis int) (*array.Dense, error) {
+	bv, err := vector(b)
+	if err != nil {
+		return nil, err
+	}
+	av, err := vector(a)
+	if err != nil {
+		return nil, err
+	}
+	bv, av, err = normalize(bv, av)
+	if err != nil {
+		return nil, err
+	}
+	n, err := axisLength(x, axis)
+	if err != nil {
+		return nil, err
+	}
+	return array.ApplyAlongAxis(x, axis, n, func(dst, src []float64) {
+		lfilter(bv, av, src, dst, make([]float64, len(bv)-1))
+	})
+}
+
+// lfilterZI returns the state of a filter with normalized coefficients
+// in the steady state of a unit step input.
+func lfilterZI(b, a []float64) []float64 {
+	n := len(a) - 1
+	if n == 0 {
+		return nil
+	}
+	// Solves (I - Aᵀ) zi = b[1:] - a[1:] b[0], where A is the companion
+	// matrix of a.
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n+1)
+		m[i][i] = 1
+		m[i][0] += a[i+1]
+		if i+1 < n {
+			m[i][i+1] -= 1
+		}
+		m[i][n] = b[i+1] - a[i+1]*b[0]
+	}
+	// Gaussian elimination with partial pivoting.
+	for c := 0; c < n; c++ {
+		pivot := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[pivot][c]) {
+				pivot = r
+			}
+		}
+		m[c], m[pivot] = m[pivot], m[c]
+		for r := c + 1; r < n; r++ {
+			f := m[r][c] / m[c][c]
+			for k := c; k <= n; k++ {
+				m[r][k] -= f * m[c][k]
+			}
+		}
+	}
+	zi := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		s := m[r][n]
+		for k := r + 1; k < n; k++ {
+			s -= m[r][k] * zi[k]
+		}
+		zi[r] = s / m[r][r]
+	}
+	return zi
+}
+
+// filtfilt filters x forward and backward into y, extending it at both
+// ends by padlen items of odd symmetry, with the states of the filter
+// starting in the steady state of the edges.  The filter is given by a
+// function taking the state scale as its initial value.
+func filtfilt(x, y []float64, padlen int, filter func(x []float64, x0 float64)) {
+	n := len(x)
+	ext := make([]float64, n+2*padlen)
+	for i := 0; i < padlen; i++ {
+		ext[i] = 2*x[0] - x[padlen-i]
+		ext[n+padlen+i] = 2*x[n-1] - x[n-2-i]
+	}
+	copy(ext[padlen:], x)
+	filter(ext, ext[0])
+	reverse(ext)
+	filter(ext, ext[0])
+	reverse(ext)
+	copy(y, ext[padlen:padlen+n])
+}
+
+func reverse(x []float64) {
+	for i, j := 0, len(x)-1; i < j; i, j = i+1, j-1 {
+		x[i], x[j] = x[j], x[i]
+	}
+}
+
+// Filtfilt returns x filtered along axis forward and then backward by
+// the transfer function with numerator b and denominator a, as in
+// Lfilter, so that the result has no phase shift and the gain of the
+// filter squared.  Edge effects are reduced by extending the signal
+// with odd symmetry by three times the number of coefficients, and by
+// starting in the steady state of its edges.  The signal must be longer
+// than this extension.
+func Filtfilt(b, a, x *array.Dense, axis int) (*array.Dense, error) {
+	bv, err := vector(b)
+	if err != nil {
+		return nil, err
+	}
+	av, err := vector(a)
+	if err != nil {
+		return nil, err
+	}
+	padlen := 3 * len(av)
+	if len(bv) > len(av) {
+		padlen = 3 * len(bv)
+	}
+	bv, av, err = normalize(bv, av)
+	if err != nil {
+		return nil, err
+	}
+	n, err := axisLength(x, axis)
+	if err != nil {
+		return nil, err
+	}
+	if n <= padlen {
+		return nil, ErrTooShort
+	}
+	zi := lfilterZI(bv, av)
+	return array.ApplyAlongAxis(x, axis, n, func(dst, src []float64) {
+		z := make([]float64, len(zi))
+		filtfilt(src, dst, padlen, func(x []float64, x0 float64) {
+			for i, v := range zi {
+				z[i] = v * x0
+			}
+			lfilter(bv, av, x, x, z)
+		})
+	})
+}
+
+// sections returns the normalized coefficients of second-order
+// sections.
+func sections(sos *array.Dense) ([][2][]float64, error) {
+	if len(sos.Shape) != 2 || sos.Shape[1] != 6 || sos.Shape[0] == 0 {
+		return nil, ErrInvalidCoefficients
+	}
+	values := sos.Values()
+	out := make([][2][]float64, sos.Shape[0])
+	for i := range out {
+		row := values[6*i : 6*i+6]
+		b, a, err := normalize(row[:3], row[3:])
+		if err != nil {
+			return nil, err
+		}
+		out[i] = [2][]float64{b, a}
+	}
+	return out, nil
+}
+
+// Sosfilt returns x filtered along axis by a cascade of second-order
+// sections, an array of shape n×6 as returned by ZPK.SOS, starting from
+// rest.
+func Sosfilt(sos, x *array.Dense, axis int) (*array.Dense, error) {
+	s, err := sections(sos)
+	if err != nil {
+		return nil, err
+	}
+	n, err := axisLength(x, axis)
+	if err != nil {
+		return nil, err
+	}
+	return array.ApplyAlongAxis(x, axis, n, func(dst, src []float64) {
+		copy(dst, src)
+		for _, section := range s {
+			lfilter(section[0], section[1], dst, dst, make([]float64, 2))
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package signal_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/signal"
+)
+
+// response returns the gain of a transfer function at a frequency
+// relative to the Nyquist frequency.
+func response(b, a *array.Dense, f float64) float64 {
+	z := cmplx.Exp(complex(0, -math.Pi*f))
+	eval := func(c []float64) complex128 {
+		sum, p := complex(0, 0), complex(1, 0)
+		for _, v := range c {
+			sum += complex(v, 0) * p
+			p *= z
+		}
+		return sum
+	}
+	return cmplx.Abs(eval(b.Values()) / eval(a.Values()))
+}
+
+func TestFirwin(t *testing.T) {
+	h, err := signal.Firwin(31, []float64{0.3}, signal.Lowpass, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	one := arraytest.Vector(t, 1)
+	assert.InDelta(t, 1, response(h, one, 0), 1e-12)
+	assert.InDelta(t, 0.5, response(h, one, 0.3), 0.05)
+	assert.InDelta(t, 0, response(h, one, 0.8), 0.01)
+	v := h.Values()
+	for i := range v {
+		assert.InDelta(t, v[i], v[len(v)-1-i], 1e-15)
+	}
+
+	h, err = signal.Firwin(31, []float64{10, 20}, signal.Bandpass, 100)
+	if assert.NoError(t, err) {
+		assert.InDelta(t, 1, response(h, one, 0.3), 1e-2)
+		assert.InDelta(t, 0, response(h, one, 0), 1e-2)
+	}
+
+	_, err = signal.Firwin(30, []float64{0.3}, signal.Highpass, 2)
+	assert.Equal(t, signal.ErrInvalidParameter, err)
+	_, err = signal.Firwin(31, []float64{1.5}, signal.Lowpass, 2)
+	assert.Equal(t, signal.ErrInvalidFrequency, err)
+}
+
+func TestIIRDesign(t *testing.T) {
+	cases := []struct {
+		name   string
+		design func() (*signal.ZPK, error)
+		// Frequencies relative to Nyquist and the gains there.
+		freqs, gains []float64
+	}{
+		{"butter lowpass", func() (*signal.ZPK, error) {
+			return signal.Butter(4, []float64{0.2}, signal.Lowpass, 2)
+		}, []float64{0, 0.2, 1}, []float64{1, 1 / math.Sqrt2, 0}},
+		{"butter highpass", func() (*signal.ZPK, error) {
+			return signal.Butter(3, []float64{100}, signal.Highpass, 1000)
+		}, []float64{0, 0.2, 1}, []float64{0, 1 / math.Sqrt2, 1}},
+		{"butter bandpass", func() (*signal.ZPK, error) {
+			return signal.Butter(2, []float64{0.2, 0.4}, signal.Bandpass, 2)
+		}, []float64{0, 0.2, 0.4, 1}, []float64{0, 1 / math.Sqrt2, 1 / math.Sqrt2, 0}},
+		{"butter bandstop", func() (*signal.ZPK, error) {
+			return signal.Butter(2, []float64{0.2, 0.4}, signal.Bandstop, 2)
+		}, []float64{0, 0.2, 0.4, 1}, []float64{1, 1 / math.Sqrt2, 1 / math.Sqrt2, 1}},
+		{"cheby1", func() (*signal.ZPK, error) {
+			return signal.Cheby1(4, 1, []float64{0.3}, signal.Lowpass, 2)
+		}, []float64{0, 0.3, 1}, []float64{math.Pow(10, -0.05), math.Pow(10, -0.05), 0}},
+		{"cheby2", func() (*signal.ZPK, error) {
+			return signal.Cheby2(4, 40, []float64{0.3}, signal.Lowpass, 2)
+		}, []float64{0, 0.3}, []float64{1, 0.01}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := c.design()
+			if !assert.NoError(t, err) {
+				return
+			}
+			b, a := f.TF()
+			for i, freq := range c.freqs {
+				assert.InDelta(t, c.gains[i], response(b, a, freq), 1e-6, "at %v", freq)
+			}
+		})
+	}
+	_, err := signal.Butter(0, []float64{0.2}, signal.Lowpass, 2)
+	assert.Equal(t, signal.ErrInvalidParameter, err)
+	_, err = signal.Butter(2, []float64{0.2}, signal.Bandpass, 2)
+	assert.Equal(t, signal.ErrInvalidFrequency, err)
+}
+
+func TestLfilter(t *testing.T) {
+	// Moving sum and an exponential average.
+	x := arraytest.Vector(t, 1, 2, 3, 4)
+	y, err := signal.Lfilter(arraytest.Vector(t, 1, 1), arraytest.Vector(t, 1), x, 0)
+	if assert.NoError(t, err) {
+		assertValues(t, []float64{1, 3, 5, 7}, y, 1e-12)
+	}
+	y, err = signal.Lfilter(arraytest.Vector(t, 1), arraytest.Vector(t, 2, -1), x, -1)
+	if assert.NoError(t, err) {
+		assertValues(t, []float64{0.5, 1.25, 2.125, 3.0625}, y, 1e-12)
+	}
+
+	// Lanes along the first axis of a matrix.
+	m, _ := array.NewDenseWithValues(array.Shape{3, 2}, []float64{1, 10, 2, 20, 3, 30}, array.DefaultAttributes)
+	y, err = signal.Lfilter(arraytest.Vector(t, 1, 1), arraytest.Vector(t, 1), m, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, array.Shape{3, 2}, y.Shape)
+		assertValues(t, []float64{1, 10, 3, 30, 5, 50}, y, 1e-12)
+	}
+
+	_, err = signal.Lfilter(arraytest.Vector(t, 1), arraytest.Vector(t, 0, 1), x, 0)
+	assert.Equal(t, signal.ErrInvalidCoefficients, err)
+	_, err = signal.Lfilter(arraytest.Vector(t, 1), arraytest.Vector(t, 1), x, 1)
+	assert.Error(t, err)
+}
+
+func TestSosfilt(t *testing.T) {
+	f, err := signal.Butter(6, []float64{0.1, 0.3}, signal.Bandpass, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	x := make([]float64, 100)
+	for i := range x {
+		x[i] = math.Sin(float64(i)) + math.Cos(0.3*float64(i))
+	}
+	b, a := f.TF()
+	want, err := signal.Lfilter(b, a, arraytest.Vector(t, x...), 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	sos := f.SOS()
+	assert.Equal(t, array.Shape{6, 6}, sos.Shape)
+	got, err := signal.Sosfilt(sos, arraytest.Vector(t, x...), 0)
+	if assert.NoError(t, err) {
+		assertValues(t, want.Values(), got, 1e-8)
+	}
+}
+
+func TestFiltfilt(t *testing.T) {
+	f, err := signal.Butter(3, []float64{0.2}, signal.Lowpass, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b, a := f.TF()
+
+	// Constant signals are kept, without transients at the edges.
+	x := make([]float64, 40)
+	for i := range x {
+		x[i] = 3
+	}
+	y, err := signal.Filtfilt(b, a, arraytest.Vector(t, x...), 0)
+	if assert.NoError(t, err) {
+		assertValues(t, x, y, 1e-9)
+	}
+
+	// Slow sines pass without phase shift, fast ones are removed.
+	n := 400
+	slow, mixed := make([]float64, n), make([]float64, n)
+	for i := range slow {
+		slow[i] = math.Sin(2 * math.Pi * 0.01 * float64(i))
+		mixed[i] = slow[i] + math.Sin(2*math.Pi*0.4*float64(i))
+	}
+	y, err = signal.Filtfilt(b, a, arraytest.Vector(t, mixed...), 0)
+	if assert.NoError(t, err) {
+		got := y.Values()
+		for i := 50; i < n-50; i++ {
+			assert.InDelta(t, slow[i], got[i], 0.01)
+		}
+	}
+
+	_, err = signal.Filtfilt(b, a, arraytest.Vector(t, 1, 2, 3), 0)
+	assert.Equal(t, signal.ErrTooShort, err)
+}
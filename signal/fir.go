@@ -0,0 +1,126 @@
+package signal
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// FilterType is the band of frequencies passed by a filter.
+type FilterType int
+
+// Types of filters.
+const (
+	// Lowpass passes frequencies below one cutoff.
+	Lowpass FilterType = iota
+	// Highpass passes frequencies above one cutoff.
+	Highpass
+	// Bandpass passes frequencies between two cutoffs.
+	Bandpass
+	// Bandstop stops frequencies between two cutoffs.
+	Bandstop
+)
+
+func (t FilterType) String() string {
+	switch t {
+	case Lowpass:
+		return "Lowpass"
+	case Highpass:
+		return "Highpass"
+	case Bandpass:
+		return "Bandpass"
+	case Bandstop:
+		return "Bandstop"
+	}
+	return "FilterType(?)"
+}
+
+// normalizedCutoff returns the cutoff frequencies as fractions of the
+// Nyquist frequency, checking them against the type of filter.
+func normalizedCutoff(cutoff []float64, typ FilterType, fs float64) ([]float64, error) {
+	want := 1
+	if typ == Bandpass || typ == Bandstop {
+		want = 2
+	}
+	if len(cutoff) != want || typ < Lowpass || typ > Bandstop || !(fs > 0) {
+		return nil, ErrInvalidFrequency
+	}
+	normalized := make([]float64, len(cutoff))
+	prev := 0.0
+	for i, c := range cutoff {
+		normalized[i] = c / (fs / 2)
+		if !(normalized[i] > prev && normalized[i] < 1) {
+			return nil, ErrInvalidFrequency
+		}
+		prev = normalized[i]
+	}
+	return normalized, nil
+}
+
+// sinc returns the normalized sinc function sin(πx) / (πx).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// Firwin returns the coefficients of a linear-phase FIR filter of
+// numtaps taps designed by the window method with a Hamming window.
+// Lowpass and highpass filters take one cutoff frequency, and bandpass
+// and bandstop filters two.  The gain is one at zero frequency for
+// lowpass and bandstop filters, at the Nyquist frequency for highpass
+// filters, and at the centre of the band for bandpass filters.
+// Filters passing the Nyquist frequency need an odd number of taps.
+func Firwin(numtaps int, cutoff []float64, typ FilterType, fs float64) (*array.Dense, error) {
+	if numtaps <= 0 {
+		return nil, ErrInvalidLength
+	}
+	cut, err := normalizedCutoff(cutoff, typ, fs)
+	if err != nil {
+		return nil, err
+	}
+	passZero := typ == Lowpass || typ == Bandstop
+	passNyquist := typ == Highpass || typ == Bandstop
+	if passNyquist && numtaps%2 == 0 {
+		return nil, ErrInvalidParameter
+	}
+	var edges []float64
+	if passZero {
+		edges = append(edges, 0)
+	}
+	edges = append(edges, cut...)
+	if passNyquist {
+		edges = append(edges, 1)
+	}
+
+	win, _ := Hamming(numtaps, true)
+	w := win.Values()
+	h := make([]float64, numtaps)
+	alpha := float64(numtaps-1) / 2
+	for i := range h {
+		m := float64(i) - alpha
+		for b := 0; b < len(edges); b += 2 {
+			left, right := edges[b], edges[b+1]
+			h[i] += right*sinc(right*m) - left*sinc(left*m)
+		}
+		h[i] *= w[i]
+	}
+	// Unit gain at the frequency of reference of the first band.
+	left, right := edges[0], edges[1]
+	ref := 0.5 * (left + right)
+	switch {
+	case left == 0:
+		ref = 0
+	case right == 1:
+		ref = 1
+	}
+	gain := 0.0
+	for i, v := range h {
+		gain += v * math.Cos(math.Pi*(float64(i)-alpha)*ref)
+	}
+	for i := range h {
+		h[i] /= gain
+	}
+	return array.NewDenseWithValues(array.Shape{numtaps}, h, array.DefaultAttributes)
+}
@@ -0,0 +1,334 @@
+package signal
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// ZPK is a digital filter given by its zeros, poles and gain, whose
+// transfer function is K Π(z - Z[i]) / Π(z - P[i]).  Complex zeros and
+// poles come in conjugate pairs, so that the filter has real
+// coefficients.
+type ZPK struct {
+	Z, P []complex128
+	K    float64
+}
+
+// Butter returns a digital Butterworth filter of the given order, whose
+// response is maximally flat in the passband.  Cutoff frequencies, one
+// for lowpass and highpass filters and two for the others, are those
+// where the gain falls to 1/√2.
+func Butter(order int, cutoff []float64, typ FilterType, fs float64) (*ZPK, error) {
+	if order <= 0 {
+		return nil, ErrInvalidParameter
+	}
+	var p []complex128
+	for m := -order + 1; m < order; m += 2 {
+		p = append(p, -cmplx.Exp(complex(0, math.Pi*float64(m)/float64(2*order))))
+	}
+	return digital(nil, p, 1, cutoff, typ, fs)
+}
+
+// Cheby1 returns a digital Chebyshev type I filter of the given order,
+// with ripple of at most ripple decibels in the passband.  Cutoff
+// frequencies, one for lowpass and highpass filters and two for the
+// others, are those where the gain first falls below the ripple.
+func Cheby1(order int, ripple float64, cutoff []float64, typ FilterType, fs float64) (*ZPK, error) {
+	if order <= 0 || !(ripple > 0) || math.IsInf(ripple, 0) {
+		return nil, ErrInvalidParameter
+	}
+	eps := math.Sqrt(math.Pow(10, 0.1*ripple) - 1)
+	mu := math.Asinh(1/eps) / float64(order)
+	var p []complex128
+	k := complex(1, 0)
+	for m := -order + 1; m < order; m += 2 {
+		theta := math.Pi * float64(m) / float64(2*order)
+		pole := -cmplx.Sinh(complex(mu, theta))
+		p = append(p, pole)
+		k *= -pole
+	}
+	gain := real(k)
+	if order%2 == 0 {
+		gain /= math.Sqrt(1 + eps*eps)
+	}
+	return digital(nil, p, gain, cutoff, typ, fs)
+}
+
+// Cheby2 returns a digital Chebyshev type II filter of the given order,
+// with attenuation of at least attenuation decibels in the stopband.
+// Cutoff frequencies, one for lowpass and highpass filters and two for
+// the others, are those where the gain first reaches the attenuation.
+func Cheby2(order int, attenuation float64, cutoff []float64, typ FilterType, fs float64) (*ZPK, error) {
+	if order <= 0 || !(attenuation > 0) || math.IsInf(attenuation, 0) {
+		return nil, ErrInvalidParameter
+	}
+	de := 1 / math.Sqrt(math.Pow(10, 0.1*attenuation)-1)
+	mu := math.Asinh(1/de) / float64(order)
+	var z, p []complex128
+	for m := -order + 1; m < order; m += 2 {
+		if m != 0 {
+			z = append(z, -cmplx.Conj(complex(0, 1/math.Sin(float64(m)*math.Pi/float64(2*order)))))
+		}
+		q := -cmplx.Exp(complex(0, math.Pi*float64(m)/float64(2*order)))
+		p = append(p, 1/complex(math.Sinh(mu)*real(q), math.Cosh(mu)*imag(q)))
+	}
+	k := prod(neg(p)) / prod(neg(z))
+	return digital(z, p, real(k), cutoff, typ, fs)
+}
+
+// prod returns the product of values, one if there are none.
+func prod(values []complex128) complex128 {
+	p := complex(1, 0)
+	for _, v := range values {
+		p *= v
+	}
+	return p
+}
+
+// neg returns the opposites of values.
+func neg(values []complex128) []complex128 {
+	out := make([]complex128, len(values))
+	for i, v := range values {
+		out[i] = -v
+	}
+	return out
+}
+
+// digital turns an analog lowpass prototype with cutoff at one radian
+// per second into a digital filter, transforming its frequencies and
+// then applying the bilinear transform with prewarped cutoffs.
+func digital(
+	z, p []complex128, k float64, cutoff []float64, typ FilterType, fs float64,
+) (*ZPK, error) {
+	cut, err := normalizedCutoff(cutoff, typ, fs)
+	if err != nil {
+		return nil, err
+	}
+	// Prewarped analog frequencies, with a sampling frequency of two.
+	const sampling = 2.0
+	warped := make([]float64, len(cut))
+	for i, c := range cut {
+		warped[i] = 2 * sampling * math.Tan(math.Pi*c/sampling)
+	}
+	degree := len(p) - len(z)
+	switch typ {
+	case Lowpass:
+		wo := complex(warped[0], 0)
+		for i := range z {
+			z[i] *= wo
+		}
+		for i := range p {
+			p[i] *= wo
+		}
+		k *= math.Pow(warped[0], float64(degree))
+	case Highpass:
+		wo := complex(warped[0], 0)
+		k *= real(prod(neg(z)) / prod(neg(p)))
+		for i := range z {
+			z[i] = wo / z[i]
+		}
+		for i := range p {
+			p[i] = wo / p[i]
+		}
+		for i := 0; i < degree; i++ {
+			z = append(z, 0)
+		}
+	case Bandpass, Bandstop:
+		wo := math.Sqrt(warped[0] * warped[1])
+		bw := warped[1] - warped[0]
+		half := complex(bw/2, 0)
+		scale := func(v complex128) complex128 { return v * half }
+		if typ == Bandstop {
+			k *= real(prod(neg(z)) / prod(neg(p)))
+			scale = func(v complex128) complex128 { return half / v }
+		} else {
+			k *= math.Pow(bw, float64(degree))
+		}
+		split := func(values []complex128) []complex128 {
+			var out []complex128
+			for _, v := range values {
+				v = scale(v)
+				r := cmplx.Sqrt(v*v - complex(wo*wo, 0))
+				out = append(out, v+r, v-r)
+			}
+			return out
+		}
+		z, p = split(z), split(p)
+		for i := 0; i < degree; i++ {
+			if typ == Bandstop {
+				z = append(z, complex(0, wo), complex(0, -wo))
+			} else {
+				z = append(z, 0)
+			}
+		}
+	}
+	// Bilinear transform.
+	degree = len(p) - len(z)
+	fs2 := complex(2*sampling, 0)
+	k *= real(prod(sub(fs2, z)) / prod(sub(fs2, p)))
+	for i := range z {
+		z[i] = (fs2 + z[i]) / (fs2 - z[i])
+	}
+	for i := range p {
+		p[i] = (fs2 + p[i]) / (fs2 - p[i])
+	}
+	for i := 0; i < degree; i++ {
+		z = append(z, -1)
+	}
+	return &ZPK{Z: z, P: p, K: k}, nil
+}
+
+// sub returns c - v for every value v.
+func sub(c complex128, values []complex128) []complex128 {
+	out := make([]complex128, len(values))
+	for i, v := range values {
+		out[i] = c - v
+	}
+	return out
+}
+
+// poly returns the real coefficients of the monic polynomial with the
+// given roots, in decreasing powers.
+func poly(roots []complex128) []float64 {
+	c := []complex128{1}
+	for _, r := range roots {
+		next := make([]complex128, len(c)+1)
+		for i, v := range c {
+			next[i] += v
+			next[i+1] -= v * r
+		}
+		c = next
+	}
+	out := make([]float64, len(c))
+	for i, v := range c {
+		out[i] = real(v)
+	}
+	return out
+}
+
+// TF returns the coefficients of the numerator b and denominator a of
+// the transfer function of the filter, in decreasing powers of z, as
+// taken by Lfilter.
+func (f *ZPK) TF() (b, a *array.Dense) {
+	num := poly(f.Z)
+	for i := range num {
+		num[i] *= f.K
+	}
+	den := poly(f.P)
+	b, _ = array.NewDenseWithValues(array.Shape{len(num)}, num, array.DefaultAttributes)
+	a, _ = array.NewDenseWithValues(array.Shape{len(den)}, den, array.DefaultAttributes)
+	return b, a
+}
+
+// factor is a polynomial of degree one or two with real coefficients,
+// given by its roots.
+type factor struct {
+	roots []complex128
+}
+
+// coefficients returns the three coefficients of the factor in
+// decreasing powers, padded with zeros.
+func (f factor) coefficients() []float64 {
+	c := poly(f.roots)
+	for len(c) < 3 {
+		c = append(c, 0)
+	}
+	return c
+}
+
+// factors groups roots into factors of degree two, made of conjugate
+// pairs or of two real roots, and possibly one of degree one.
+func factors(roots []complex128) []factor {
+	const tol = 1e-10
+	var out []factor
+	var reals []float64
+	for _, r := range roots {
+		switch {
+		case math.Abs(imag(r)) <= tol*cmplx.Abs(r):
+			reals = append(reals, real(r))
+		case imag(r) > 0:
+			out = append(out, factor{[]complex128{r, cmplx.Conj(r)}})
+		}
+	}
+	sort.Float64s(reals)
+	for i := 0; i < len(reals); i += 2 {
+		f := factor{[]complex128{complex(reals[i], 0)}}
+		if i+1 < len(reals) {
+			f.roots = append(f.roots, complex(reals[i+1], 0))
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// SOS returns the filter as a cascade of second-order sections, an
+// array of shape n×6 whose rows hold the coefficients b0, b1, b2, a0,
+// a1, a2 of every section, as taken by Sosfilt.  Poles closest to the
+// unit circle are in the last sections, each paired with the nearest
+// zeros, which is less sensitive to rounding errors than the transfer
+// function of high-order filters.
+func (f *ZPK) SOS() *array.Dense {
+	poles, zeros := factors(f.P), factors(f.Z)
+	sort.SliceStable(poles, func(i, j int) bool {
+		return cmplx.Abs(poles[i].roots[0]) < cmplx.Abs(poles[j].roots[0])
+	})
+	n := len(poles)
+	if len(zeros) > n {
+		n = len(zeros)
+	}
+	sections := make([][2]factor, n)
+	for i, p := range poles {
+		sections[i][1] = p
+	}
+	// Sections from the closest to the unit circle take the nearest
+	// zeros, preferring factors of the same degree.
+	used := make([]bool, len(zeros))
+	for i := n - 1; i >= 0; i-- {
+		best := -1
+		for j, z := range zeros {
+			if used[j] {
+				continue
+			}
+			if best < 0 || closer(z, zeros[best], sections[i][1]) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			used[best] = true
+			sections[i][0] = zeros[best]
+		}
+	}
+	values := make([]float64, 0, 6*n)
+	for i, s := range sections {
+		b, a := s[0].coefficients(), s[1].coefficients()
+		if i == 0 {
+			for j := range b {
+				b[j] *= f.K
+			}
+		}
+		values = append(values, b...)
+		values = append(values, a...)
+	}
+	if n == 0 {
+		// A pure gain.
+		values = []float64{f.K, 0, 0, 1, 0, 0}
+		n = 1
+	}
+	sos, _ := array.NewDenseWithValues(array.Shape{n, 6}, values, array.DefaultAttributes)
+	return sos
+}
+
+// closer reports whether the zeros of a are a better match than those
+// of b for the poles of p.
+func closer(a, b, p factor) bool {
+	if len(p.roots) > 0 && (len(a.roots) == len(p.roots)) != (len(b.roots) == len(p.roots)) {
+		return len(a.roots) == len(p.roots)
+	}
+	if len(p.roots) == 0 {
+		return false
+	}
+	return cmplx.Abs(a.roots[0]-p.roots[0]) < cmplx.Abs(b.roots[0]-p.roots[0])
+}
@@ -0,0 +1,97 @@
+package signal
+
+import (
+	"context"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Resample returns x resampled along axis to num items by the Fourier
+// method: the spectrum of every lane is truncated or padded with zeros,
+// which assumes that the signal is periodic.
+func Resample(x *array.Dense, num, axis int) (*array.Dense, error) {
+	return ResampleContext(context.Background(), x, num, axis)
+}
+
+// ResampleContext is like Resample, but stops early and returns an
+// error wrapping the error of ctx once it is done.
+func ResampleContext(ctx context.Context, x *array.Dense, num, axis int) (*array.Dense, error) {
+	if num <= 0 {
+		return nil, ErrInvalidLength
+	}
+	n, err := axisLength(x, axis)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrTooShort
+	}
+	short := n
+	if num < n {
+		short = num
+	}
+	return array.ApplyAlongAxisContext(ctx, x, axis, num, func(dst, src []float64) {
+		in := make([]complex128, n)
+		for i, v := range src {
+			in[i] = complex(v, 0)
+		}
+		spectrum := fft(in, false)
+		out := make([]complex128, num)
+		// Frequencies common to both lengths.
+		for k := 0; k < (short+1)/2; k++ {
+			out[k] = spectrum[k]
+		}
+		for k := 1; k < (short+1)/2; k++ {
+			out[num-k] = spectrum[n-k]
+		}
+		if short%2 == 0 {
+			// The Nyquist frequency of the shorter length gathers, or is
+			// split between, positive and negative frequencies.
+			h := short / 2
+			switch {
+			case num < n:
+				out[h] = spectrum[h] + spectrum[n-h]
+			case num > n:
+				out[h] = spectrum[h] / 2
+				out[num-h] = spectrum[h] / 2
+			default:
+				out[h] = spectrum[h]
+			}
+		}
+		out = fft(out, true)
+		for i := range dst {
+			dst[i] = real(out[i]) / float64(n)
+		}
+	})
+}
+
+// Decimate returns x along axis filtered to prevent aliasing and then
+// downsampled by keeping one item in q.  The filter is a Chebyshev type
+// I filter of order 8 with ripple 0.05 dB and cutoff at 0.8 times the
+// new Nyquist frequency, applied forward and backward as in Filtfilt so
+// that it shifts no phase.
+func Decimate(x *array.Dense, q, axis int) (*array.Dense, error) {
+	if q <= 0 {
+		return nil, ErrInvalidParameter
+	}
+	n, err := axisLength(x, axis)
+	if err != nil {
+		return nil, err
+	}
+	y := x
+	if q > 1 {
+		f, err := Cheby1(8, 0.05, []float64{0.8 / float64(q)}, Lowpass, 2)
+		if err != nil {
+			return nil, err
+		}
+		b, a := f.TF()
+		if y, err = Filtfilt(b, a, x, axis); err != nil {
+			return nil, err
+		}
+	}
+	return array.ApplyAlongAxis(y, axis, (n+q-1)/q, func(dst, src []float64) {
+		for i := range dst {
+			dst[i] = src[i*q]
+		}
+	})
+}
@@ -0,0 +1,392 @@
+package signal
+
+import (
+	"context"
+	"math/cmplx"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Detrend is the trend removed from every segment before its transform.
+type Detrend int
+
+// Trends removed from segments.
+const (
+	// DetrendConstant removes the mean.
+	DetrendConstant Detrend = iota
+	// DetrendLinear removes the least-squares line.
+	DetrendLinear
+	// DetrendNone keeps segments as they are.
+	DetrendNone
+)
+
+// Scaling is the unit of power spectra.
+type Scaling int
+
+// Scalings of power spectra.
+const (
+	// Density gives a power spectral density, in units squared per unit
+	// of frequency.
+	Density Scaling = iota
+	// Spectrum gives a power spectrum, in units squared.
+	Spectrum
+)
+
+// SpectralOptions controls how Welch, Spectrogram and STFT split
+// signals into segments.
+type SpectralOptions struct {
+	// Window weights the items of every segment.  When nil, a periodic
+	// Hann window is used.
+	Window *array.Dense
+	// SegmentLength is the number of items of every segment, at most the
+	// length of the signal.  When zero, it is the length of Window.
+	SegmentLength int
+	// Overlap is the number of items shared by consecutive segments.
+	// When negative, it is half the segment length.
+	Overlap int
+	// NFFT is the length of the transform of every segment, padded with
+	// zeros.  When zero, it is the segment length.
+	NFFT int
+	// Detrend is the trend removed from every segment.
+	Detrend Detrend
+	// Scaling is the unit of power spectra.
+	Scaling Scaling
+	// Axis is the axis of time of the signal, negative axes counting from
+	// the last dimension.
+	Axis int
+}
+
+// DefaultSpectralOptions splits signals along their last axis into
+// segments of 256 items overlapping by half, weighted by a Hann window
+// after removing their mean, and scales power spectra as densities.
+var DefaultSpectralOptions = SpectralOptions{
+	SegmentLength: 256,
+	Overlap:       -1,
+	Axis:          -1,
+}
+
+// segments describes how the lanes of a signal are split.
+type segments struct {
+	axis    int
+	length  int
+	step    int
+	count   int
+	nfft    int
+	window  []float64
+	detrend Detrend
+	fs      float64
+}
+
+// newSegments returns the segments of the lanes of x.
+func newSegments(x *array.Dense, fs float64, opts *SpectralOptions) (*segments, error) {
+	if opts == nil {
+		opts = &DefaultSpectralOptions
+	}
+	if !(fs > 0) {
+		return nil, ErrInvalidFrequency
+	}
+	n, err := axisLength(x, opts.Axis)
+	if err != nil {
+		return nil, err
+	}
+	axis := opts.Axis
+	if axis < 0 {
+		axis += len(x.Shape)
+	}
+	s := &segments{axis: axis, detrend: opts.Detrend, fs: fs}
+	s.length = opts.SegmentLength
+	if opts.Window != nil {
+		if s.window, err = vector(opts.Window); err != nil {
+			return nil, err
+		}
+		if s.length == 0 {
+			s.length = len(s.window)
+		}
+	}
+	if s.length > n {
+		s.length = n
+		if s.window != nil && len(s.window) != n {
+			return nil, ErrTooShort
+		}
+	}
+	if s.length <= 0 {
+		return nil, ErrInvalidSegments
+	}
+	if s.window == nil {
+		w, _ := Hann(s.length, false)
+		s.window = w.Values()
+	}
+	overlap := opts.Overlap
+	if overlap < 0 {
+		overlap = s.length / 2
+	}
+	s.step = s.length - overlap
+	if s.step <= 0 || len(s.window) != s.length {
+		return nil, ErrInvalidSegments
+	}
+	s.count = (n-s.length)/s.step + 1
+	s.nfft = opts.NFFT
+	if s.nfft == 0 {
+		s.nfft = s.length
+	}
+	if s.nfft < s.length {
+		return nil, ErrInvalidSegments
+	}
+	return s, nil
+}
+
+// frequencies returns the frequencies of one-sided spectra.
+func (s *segments) frequencies() *array.Dense {
+	f := make([]float64, s.nfft/2+1)
+	for k := range f {
+		f[k] = float64(k) * s.fs / float64(s.nfft)
+	}
+	d, _ := array.NewDenseWithValues(array.Shape{len(f)}, f, array.DefaultAttributes)
+	return d
+}
+
+// times returns the times of the centres of the segments.
+func (s *segments) times() *array.Dense {
+	t := make([]float64, s.count)
+	for i := range t {
+		t[i] = (float64(i*s.step) + float64(s.length)/2) / s.fs
+	}
+	d, _ := array.NewDenseWithValues(array.Shape{len(t)}, t, array.DefaultAttributes)
+	return d
+}
+
+// transform returns the one-sided spectrum of the segment starting at
+// an item of a lane.
+func (s *segments) transform(lane []float64, start int) []complex128 {
+	seg := append([]float64(nil), lane[start:start+s.length]...)
+	switch s.detrend {
+	case DetrendConstant:
+		mean := 0.0
+		for _, v := range seg {
+			mean += v
+		}
+		mean /= float64(len(seg))
+		for i := range seg {
+			seg[i] -= mean
+		}
+	case DetrendLinear:
+		// Least-squares line through the items, centred on the middle.
+		c := float64(len(seg)-1) / 2
+		var mean, sxy, sxx float64
+		for i, v := range seg {
+			mean += v
+			sxy += (float64(i) - c) * v
+			sxx += (float64(i) - c) * (float64(i) - c)
+		}
+		mean /= float64(len(seg))
+		slope := 0.0
+		if sxx > 0 {
+			slope = sxy / sxx
+		}
+		for i := range seg {
+			seg[i] -= mean + slope*(float64(i)-c)
+		}
+	}
+	for i := range seg {
+		seg[i] *= s.window[i]
+	}
+	return rfft(seg, s.nfft)
+}
+
+// power returns the scaled one-sided power of a spectrum.
+func (s *segments) power(spectrum []complex128, scaling Scaling, dst []float64) {
+	var scale float64
+	switch scaling {
+	case Spectrum:
+		sum := 0.0
+		for _, w := range s.window {
+			sum += w
+		}
+		scale = 1 / (sum * sum)
+	default:
+		sum := 0.0
+		for _, w := range s.window {
+			sum += w * w
+		}
+		scale = 1 / (s.fs * sum)
+	}
+	for k, c := range spectrum {
+		p := real(c)*real(c) + imag(c)*imag(c)
+		p *= scale
+		// Negative frequencies are folded onto positive ones.
+		if k > 0 && !(s.nfft%2 == 0 && k == s.nfft/2) {
+			p *= 2
+		}
+		dst[k] = p
+	}
+}
+
+// Welch estimates the power spectral density, or the power spectrum, of
+// x along the axis of the options by Welch's method, averaging the
+// periodograms of overlapping segments.  It returns the frequencies and
+// an array with the shape of x whose axis holds the one-sided spectrum
+// at these frequencies.  If opts is nil, DefaultSpectralOptions is used.
+func Welch(x *array.Dense, fs float64, opts *SpectralOptions) (freqs, power *array.Dense, err error) {
+	return WelchContext(context.Background(), x, fs, opts)
+}
+
+// WelchContext is like Welch, but stops early and returns an error
+// wrapping the error of ctx once it is done.
+func WelchContext(
+	ctx context.Context, x *array.Dense, fs float64, opts *SpectralOptions,
+) (freqs, power *array.Dense, err error) {
+	s, err := newSegments(x, fs, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	scaling := Density
+	if opts != nil {
+		scaling = opts.Scaling
+	}
+	nf := s.nfft/2 + 1
+	power, err = array.ApplyAlongAxisContext(ctx, x, s.axis, nf, func(dst, src []float64) {
+		p := make([]float64, nf)
+		for k := range dst {
+			dst[k] = 0
+		}
+		for i := 0; i < s.count; i++ {
+			s.power(s.transform(src, i*s.step), scaling, p)
+			for k, v := range p {
+				dst[k] += v / float64(s.count)
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.frequencies(), power, nil
+}
+
+// timeFrequency returns an array with the shape of x whose axis is
+// replaced by the frequencies, followed by a last axis of segments,
+// from the values computed for every segment of every lane.
+func (s *segments) timeFrequency(
+	ctx context.Context, x *array.Dense, fn func(spectrum []complex128, dst []float64),
+) (*array.Dense, error) {
+	nf := s.nfft/2 + 1
+	packed, err := array.ApplyAlongAxisContext(ctx, x, s.axis, nf*s.count, func(dst, src []float64) {
+		values := make([]float64, nf)
+		for i := 0; i < s.count; i++ {
+			fn(s.transform(src, i*s.step), values)
+			for k, v := range values {
+				dst[k*s.count+i] = v
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Moves the segments of every lane to the last axis.
+	outer, inner := 1, 1
+	for k, n := range x.Shape {
+		switch {
+		case k < s.axis:
+			outer *= n
+		case k > s.axis:
+			inner *= n
+		}
+	}
+	values := packed.Values()
+	moved := make([]float64, len(values))
+	for o := 0; o < outer; o++ {
+		for f := 0; f < nf; f++ {
+			for i := 0; i < s.count; i++ {
+				for c := 0; c < inner; c++ {
+					from := ((o*nf+f)*s.count+i)*inner + c
+					to := ((o*nf+f)*inner+c)*s.count + i
+					moved[to] = values[from]
+				}
+			}
+		}
+	}
+	shape := append(array.Shape{}, x.Shape...)
+	shape[s.axis] = nf
+	shape = append(shape, s.count)
+	return array.NewDenseWithValues(shape, moved, array.DefaultAttributes)
+}
+
+// Spectrogram returns the power spectral density, or the power
+// spectrum, of consecutive segments of x along the axis of the options.
+// It returns the frequencies, the times of the centres of the segments,
+// and an array with the shape of x whose axis holds the frequencies,
+// followed by a last axis of segments.  If opts is nil,
+// DefaultSpectralOptions is used.
+func Spectrogram(x *array.Dense, fs float64, opts *SpectralOptions) (freqs, times, power *array.Dense, err error) {
+	return SpectrogramContext(context.Background(), x, fs, opts)
+}
+
+// SpectrogramContext is like Spectrogram, but stops early and returns
+// an error wrapping the error of ctx once it is done.
+func SpectrogramContext(
+	ctx context.Context, x *array.Dense, fs float64, opts *SpectralOptions,
+) (freqs, times, power *array.Dense, err error) {
+	s, err := newSegments(x, fs, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	scaling := Density
+	if opts != nil {
+		scaling = opts.Scaling
+	}
+	power, err = s.timeFrequency(ctx, x, func(spectrum []complex128, dst []float64) {
+		s.power(spectrum, scaling, dst)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s.frequencies(), s.times(), power, nil
+}
+
+// ShortTimeSpectrum is the short-time Fourier transform of a signal,
+// whose complex items are split into their magnitudes and phases.
+type ShortTimeSpectrum struct {
+	// Freqs and Times are the frequencies and the times of the centres
+	// of the segments.
+	Freqs, Times *array.Dense
+	// Magnitude and Phase, in radians, have the shape of the signal
+	// whose axis holds the frequencies, followed by a last axis of
+	// segments.
+	Magnitude, Phase *array.Dense
+}
+
+// STFT returns the short-time Fourier transform of x along the axis of
+// the options: the one-sided transforms of consecutive segments, scaled
+// by the inverse of the sum of the window.  If opts is nil,
+// DefaultSpectralOptions is used.
+func STFT(x *array.Dense, fs float64, opts *SpectralOptions) (*ShortTimeSpectrum, error) {
+	return STFTContext(context.Background(), x, fs, opts)
+}
+
+// STFTContext is like STFT, but stops early and returns an error
+// wrapping the error of ctx once it is done.
+func STFTContext(
+	ctx context.Context, x *array.Dense, fs float64, opts *SpectralOptions,
+) (*ShortTimeSpectrum, error) {
+	s, err := newSegments(x, fs, opts)
+	if err != nil {
+		return nil, err
+	}
+	sum := 0.0
+	for _, w := range s.window {
+		sum += w
+	}
+	out := &ShortTimeSpectrum{Freqs: s.frequencies(), Times: s.times()}
+	parts := []func(complex128) float64{cmplx.Abs, cmplx.Phase}
+	results := []**array.Dense{&out.Magnitude, &out.Phase}
+	for i, part := range parts {
+		*results[i], err = s.timeFrequency(ctx, x, func(spectrum []complex128, dst []float64) {
+			for k, c := range spectrum {
+				dst[k] = part(c / complex(sum, 0))
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
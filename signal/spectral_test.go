@@ -0,0 +1,152 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/signal"
+)
+
+// sine returns n items of a sine of amplitude a and frequency f sampled
+// at fs.
+func sine(n int, a, f, fs float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = a * math.Sin(2*math.Pi*f*float64(i)/fs)
+	}
+	return values
+}
+
+func argmax(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+func TestWelch(t *testing.T) {
+	fs := 1000.0
+	x := arraytest.Vector(t, sine(4096, 2, 125, fs)...)
+	freqs, psd, err := signal.Welch(x, fs, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, array.Shape{129}, psd.Shape)
+	f := freqs.Values()
+	assert.InDelta(t, 500, f[len(f)-1], 1e-12)
+	p := psd.Values()
+	assert.InDelta(t, 125, f[argmax(p)], 1e-12)
+
+	// The density integrates to the power of the sine, a²/2.
+	total := 0.0
+	for _, v := range p {
+		total += v * (f[1] - f[0])
+	}
+	assert.InDelta(t, 2, total, 0.01)
+
+	// The spectrum at the peak is the power of the sine.
+	opts := signal.DefaultSpectralOptions
+	opts.Scaling = signal.Spectrum
+	_, ps, err := signal.Welch(x, fs, &opts)
+	if assert.NoError(t, err) {
+		assert.InDelta(t, 2, ps.Values()[32], 1e-9)
+	}
+}
+
+func TestWelchAxis(t *testing.T) {
+	fs := 100.0
+	a, b := sine(64, 1, 12.5, fs), sine(64, 1, 25, fs)
+	values := make([]float64, 2*64)
+	for i := 0; i < 64; i++ {
+		values[2*i], values[2*i+1] = a[i], b[i]
+	}
+	x, _ := array.NewDenseWithValues(array.Shape{64, 2}, values, array.DefaultAttributes)
+	opts := signal.DefaultSpectralOptions
+	opts.SegmentLength = 32
+	opts.Axis = 0
+	freqs, psd, err := signal.Welch(x, fs, &opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, array.Shape{17, 2}, psd.Shape)
+	f, p := freqs.Values(), psd.Values()
+	lane := func(c int) []float64 {
+		out := make([]float64, 17)
+		for i := range out {
+			out[i] = p[2*i+c]
+		}
+		return out
+	}
+	assert.InDelta(t, 12.5, f[argmax(lane(0))], 1e-12)
+	assert.InDelta(t, 25, f[argmax(lane(1))], 1e-12)
+}
+
+func TestSpectrogram(t *testing.T) {
+	fs := 64.0
+	// A sine whose frequency jumps halfway.
+	x := append(sine(128, 1, 8, fs), sine(128, 1, 16, fs)...)
+	opts := signal.DefaultSpectralOptions
+	opts.SegmentLength = 32
+	opts.Overlap = 0
+	freqs, times, sxx, err := signal.Spectrogram(arraytest.Vector(t, x...), fs, &opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, array.Shape{17, 8}, sxx.Shape)
+	assertValues(t, []float64{0.25, 0.75, 1.25, 1.75, 2.25, 2.75, 3.25, 3.75}, times, 1e-12)
+	f, s := freqs.Values(), sxx.Values()
+	for seg := 0; seg < 8; seg++ {
+		column := make([]float64, 17)
+		for k := range column {
+			column[k] = s[k*8+seg]
+		}
+		want := 8.0
+		if seg >= 4 {
+			want = 16
+		}
+		assert.InDelta(t, want, f[argmax(column)], 1e-12, "segment %d", seg)
+	}
+}
+
+func TestSTFT(t *testing.T) {
+	fs := 32.0
+	opts := signal.DefaultSpectralOptions
+	opts.SegmentLength = 16
+	opts.Detrend = signal.DetrendNone
+	s, err := signal.STFT(arraytest.Vector(t, sine(64, 1, 4, fs)...), fs, &opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, array.Shape{9, 7}, s.Magnitude.Shape)
+	assert.Equal(t, array.Shape{9, 7}, s.Phase.Shape)
+	assert.Equal(t, array.Shape{7}, s.Times.Shape)
+	// The periodic Hann window sums to half the segment, so a bin
+	// holds half the amplitude of a sine at its frequency.
+	m := s.Magnitude.Values()
+	for seg := 0; seg < 7; seg++ {
+		assert.InDelta(t, 0.5, m[2*7+seg], 1e-12)
+	}
+}
+
+func TestSpectralErrors(t *testing.T) {
+	x := arraytest.Vector(t, sine(64, 1, 4, 32)...)
+	opts := signal.DefaultSpectralOptions
+	opts.SegmentLength = 16
+	opts.Overlap = 16
+	_, _, err := signal.Welch(x, 32, &opts)
+	assert.Equal(t, signal.ErrInvalidSegments, err)
+	_, _, err = signal.Welch(x, 0, nil)
+	assert.Equal(t, signal.ErrInvalidFrequency, err)
+	opts = signal.DefaultSpectralOptions
+	opts.Window = arraytest.Vector(t, 1, 1, 1)
+	opts.SegmentLength = 4
+	_, _, err = signal.Welch(x, 32, &opts)
+	assert.Equal(t, signal.ErrInvalidSegments, err)
+}
@@ -0,0 +1,108 @@
+// Package signal processes sampled signals stored in arrays: window
+// functions, design of FIR and IIR filters, filtering, convolution,
+// spectral estimation and resampling.
+//
+// Functions taking multi-channel signals process every lane along a
+// given axis, counting negative axes from the last dimension, so that
+// recordings may be stored with channels along any axis.  Frequencies
+// are given in the units of the sampling frequency fs, whose half is
+// the Nyquist frequency.
+package signal
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// window returns a window of n items given by fn at the fractions
+// i / (m - 1) of its span, where m is n for symmetric windows, used in
+// filter design, and n + 1 for periodic ones, used in spectral analysis.
+func window(n int, sym bool, fn func(x float64) float64) (*array.Dense, error) {
+	if n <= 0 {
+		return nil, ErrInvalidLength
+	}
+	m := n
+	if !sym {
+		m++
+	}
+	values := make([]float64, n)
+	if n == 1 {
+		values[0] = 1
+	} else {
+		for i := range values {
+			values[i] = fn(float64(i) / float64(m-1))
+		}
+	}
+	return array.NewDenseWithValues(array.Shape{n}, values, array.DefaultAttributes)
+}
+
+// generalCosine returns a window that is a sum of cosines with the
+// given coefficients.
+func generalCosine(n int, sym bool, a ...float64) (*array.Dense, error) {
+	return window(n, sym, func(x float64) float64 {
+		sum := 0.0
+		for k, c := range a {
+			sign := 1.0
+			if k%2 == 1 {
+				sign = -1
+			}
+			sum += sign * c * math.Cos(2*math.Pi*float64(k)*x)
+		}
+		return sum
+	})
+}
+
+// Hann returns a Hann window of n items.  Symmetric windows, whose
+// first and last items are equal, suit filter design, and periodic
+// ones spectral analysis.
+func Hann(n int, sym bool) (*array.Dense, error) {
+	return generalCosine(n, sym, 0.5, 0.5)
+}
+
+// Hamming returns a Hamming window of n items, either symmetric or
+// periodic.
+func Hamming(n int, sym bool) (*array.Dense, error) {
+	return generalCosine(n, sym, 0.54, 0.46)
+}
+
+// Blackman returns a Blackman window of n items, either symmetric or
+// periodic.
+func Blackman(n int, sym bool) (*array.Dense, error) {
+	return generalCosine(n, sym, 0.42, 0.5, 0.08)
+}
+
+// Kaiser returns a Kaiser window of n items with shape parameter beta,
+// either symmetric or periodic.  Beta trades the width of the main lobe
+// for the height of the side lobes: zero gives a rectangular window,
+// and 8.6 is close to a Blackman window.
+func Kaiser(n int, beta float64, sym bool) (*array.Dense, error) {
+	if !(beta >= 0) || math.IsInf(beta, 0) {
+		return nil, ErrInvalidParameter
+	}
+	i0 := specfun.Iv(0, beta)
+	return window(n, sym, func(x float64) float64 {
+		r := 2*x - 1
+		return specfun.Iv(0, beta*math.Sqrt(1-r*r)) / i0
+	})
+}
+
+// Tukey returns a Tukey window of n items, either symmetric or
+// periodic: a rectangle whose edges taper as cosines over the fraction
+// alpha of the window.  Alpha zero gives a rectangular window, and one
+// a Hann window.
+func Tukey(n int, alpha float64, sym bool) (*array.Dense, error) {
+	if !(alpha >= 0 && alpha <= 1) {
+		return nil, ErrInvalidParameter
+	}
+	return window(n, sym, func(x float64) float64 {
+		if x > 0.5 {
+			x = 1 - x
+		}
+		if x < alpha/2 {
+			return 0.5 * (1 - math.Cos(2*math.Pi*x/alpha))
+		}
+		return 1
+	})
+}
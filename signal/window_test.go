@@ -0,0 +1,78 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/signal"
+)
+
+func assertValues(t *testing.T, want []float64, d *array.Dense, delta float64) {
+	t.Helper()
+	got := d.Values()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		assert.InDelta(t, want[i], got[i], delta, "item %d", i)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	cases := []struct {
+		name string
+		fn   func() (*array.Dense, error)
+		want []float64
+	}{
+		{"hann", func() (*array.Dense, error) { return signal.Hann(5, true) },
+			[]float64{0, 0.5, 1, 0.5, 0}},
+		{"periodic hann", func() (*array.Dense, error) { return signal.Hann(4, false) },
+			[]float64{0, 0.5, 1, 0.5}},
+		{"hamming", func() (*array.Dense, error) { return signal.Hamming(5, true) },
+			[]float64{0.08, 0.54, 1, 0.54, 0.08}},
+		{"blackman", func() (*array.Dense, error) { return signal.Blackman(5, true) },
+			[]float64{0, 0.34, 1, 0.34, 0}},
+		{"kaiser zero beta", func() (*array.Dense, error) { return signal.Kaiser(4, 0, true) },
+			[]float64{1, 1, 1, 1}},
+		{"tukey", func() (*array.Dense, error) { return signal.Tukey(5, 0.5, true) },
+			[]float64{0, 1, 1, 1, 0}},
+		{"tukey rectangular", func() (*array.Dense, error) { return signal.Tukey(3, 0, true) },
+			[]float64{1, 1, 1}},
+		{"single", func() (*array.Dense, error) { return signal.Hann(1, true) },
+			[]float64{1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w, err := c.fn()
+			if assert.NoError(t, err) {
+				assertValues(t, c.want, w, 1e-12)
+			}
+		})
+	}
+}
+
+func TestKaiser(t *testing.T) {
+	w, err := signal.Kaiser(7, 5, true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	v := w.Values()
+	assert.InDelta(t, 1, v[3], 1e-12)
+	// The edges are 1 / I0(beta).
+	assert.InDelta(t, 0.036710, v[0], 1e-5)
+	for i := range v {
+		assert.InDelta(t, v[i], v[len(v)-1-i], 1e-12)
+	}
+}
+
+func TestWindowErrors(t *testing.T) {
+	_, err := signal.Hann(0, true)
+	assert.Equal(t, signal.ErrInvalidLength, err)
+	_, err = signal.Tukey(5, 2, true)
+	assert.Equal(t, signal.ErrInvalidParameter, err)
+	_, err = signal.Kaiser(5, math.NaN(), true)
+	assert.Equal(t, signal.ErrInvalidParameter, err)
+}
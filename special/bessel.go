@@ -0,0 +1,61 @@
+package special
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Jv returns the Bessel function of the first kind of real order v.
+// Negative arguments are only defined for integer orders.
+func Jv(v, x *array.Dense) (*array.Dense, error) {
+	return array.Map2(v, x, specfun.Jv)
+}
+
+// Yv returns the Bessel function of the second kind of real order v.
+func Yv(v, x *array.Dense) (*array.Dense, error) {
+	return array.Map2(v, x, specfun.Yv)
+}
+
+// Iv returns the modified Bessel function of the first kind of real
+// order v.  Negative arguments are only defined for integer orders.
+func Iv(v, x *array.Dense) (*array.Dense, error) {
+	return array.Map2(v, x, specfun.Iv)
+}
+
+// Kv returns the modified Bessel function of the second kind of real
+// order v.
+func Kv(v, x *array.Dense) (*array.Dense, error) {
+	return array.Map2(v, x, specfun.Kv)
+}
+
+// Jn returns the Bessel function of the first kind of integer order n.
+func Jn(n int, x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, func(v float64) float64 {
+		return math.Jn(n, v)
+	})
+}
+
+// Yn returns the Bessel function of the second kind of integer order n.
+func Yn(n int, x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, func(v float64) float64 {
+		return math.Yn(n, v)
+	})
+}
+
+// In returns the modified Bessel function of the first kind of integer
+// order n.
+func In(n int, x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, func(v float64) float64 {
+		return specfun.Iv(float64(n), v)
+	})
+}
+
+// Kn returns the modified Bessel function of the second kind of integer
+// order n.
+func Kn(n int, x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, func(v float64) float64 {
+		return specfun.Kv(float64(n), v)
+	})
+}
@@ -0,0 +1,99 @@
+package special_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/special"
+)
+
+func relDelta(t *testing.T, expected, actual float64, msg ...interface{}) {
+	t.Helper()
+	assert.InDelta(t, 0, (actual-expected)/expected, 1e-12, msg...)
+}
+
+func TestBesselIntegerOrder(t *testing.T) {
+	x := arraytest.Vector(t, 0.5, 1, 2.5, 10, 40)
+	for _, n := range []int{0, 1, 2, 5} {
+		j, err := special.Jv(array.Scalar(float64(n)), x)
+		jn, errn := special.Jn(n, x)
+		y, erry := special.Yv(array.Scalar(float64(n)), x)
+		if assert.Nil(t, err) && assert.Nil(t, errn) && assert.Nil(t, erry) {
+			for i, v := range x.Data {
+				assert.Equal(t, math.Jn(n, v), j.Data[i])
+				assert.Equal(t, math.Jn(n, v), jn.Data[i])
+				assert.Equal(t, math.Yn(n, v), y.Data[i])
+			}
+		}
+	}
+	one := arraytest.Vector(t, 1)
+	i0, _ := special.In(0, one)
+	i1, _ := special.In(1, one)
+	k0, _ := special.Kn(0, one)
+	k1, _ := special.Kn(1, one)
+	relDelta(t, 1.2660658777520082, i0.Data[0])
+	relDelta(t, 0.5651591039924851, i1.Data[0])
+	relDelta(t, 0.42102443824070834, k0.Data[0])
+	relDelta(t, 0.6019072301972346, k1.Data[0])
+	// Modified Bessel functions of integer order against the real-order
+	// recurrence I_{n-1} - I_{n+1} = 2n/x I_n.
+	x = arraytest.Vector(t, 0.3, 3, 30)
+	i2, _ := special.In(2, x)
+	i3, _ := special.In(3, x)
+	i4, _ := special.In(4, x)
+	for k, v := range x.Data {
+		relDelta(t, i2.Data[k]-i4.Data[k], 6/v*i3.Data[k], v)
+	}
+	neg, _ := special.In(-3, arraytest.Vector(t, -2))
+	pos, _ := special.In(3, arraytest.Vector(t, 2))
+	assert.InDelta(t, -pos.Data[0], neg.Data[0], 1e-15)
+}
+
+func TestBesselRealOrder(t *testing.T) {
+	x := arraytest.Vector(t, 0.1, 0.7, 1.9, 2.1, 8, 35, 2e4)
+	order := func(v float64) *array.Dense { return array.Scalar(v) }
+	j, _ := special.Jv(order(0.5), x)
+	jm, _ := special.Jv(order(-0.5), x)
+	y, _ := special.Yv(order(0.5), x)
+	ym, _ := special.Yv(order(-0.5), x)
+	j25, _ := special.Jv(order(2.5), x)
+	for k, v := range x.Data {
+		scale := math.Sqrt(2 / (math.Pi * v))
+		s, c := math.Sincos(v)
+		// Large arguments lose accuracy in the phase.
+		tol := 1e-13 * scale * math.Max(1, v/100)
+		assert.InDelta(t, scale*s, j.Data[k], tol, v)
+		assert.InDelta(t, scale*c, jm.Data[k], tol, v)
+		assert.InDelta(t, -scale*c, y.Data[k], tol, v)
+		assert.InDelta(t, scale*s, ym.Data[k], tol, v)
+		expected := scale * ((3/(v*v)-1)*s - 3*c/v)
+		assert.InDelta(t, expected, j25.Data[k], 10*tol, v)
+	}
+	x = arraytest.Vector(t, 0.1, 0.7, 1.9, 2.1, 8, 35, 650, 705)
+	k25, _ := special.Kv(order(2.5), x)
+	km25, _ := special.Kv(order(-2.5), x)
+	i15, _ := special.Iv(order(1.5), x)
+	im05, _ := special.Iv(order(-0.5), x)
+	for k, v := range x.Data {
+		expected := math.Sqrt(math.Pi/(2*v)) * math.Exp(-v) * (1 + 3/v + 3/(v*v))
+		relDelta(t, expected, k25.Data[k], v)
+		relDelta(t, expected, km25.Data[k], v)
+		scale := math.Sqrt(2 / (math.Pi * v))
+		relDelta(t, scale*(math.Cosh(v)-math.Sinh(v)/v), i15.Data[k], v)
+		relDelta(t, scale*math.Cosh(v), im05.Data[k], v)
+	}
+	// Values at the origin and broadcasting of the order.
+	out, err := special.Jv(arraytest.Vector(t, 0, 0.5, 1.5), array.Scalar(0))
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{1, 0, 0}, out.Data)
+	}
+	out, _ = special.Kv(array.Scalar(0.3), arraytest.Vector(t, 0, -1))
+	assert.True(t, math.IsInf(out.Data[0], 1))
+	assert.True(t, math.IsNaN(out.Data[1]))
+	out, _ = special.Jv(array.Scalar(0.3), arraytest.Vector(t, -1))
+	assert.True(t, math.IsNaN(out.Data[0]))
+}
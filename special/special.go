@@ -0,0 +1,96 @@
+// Package special provides special mathematical functions applied
+// elementwise to Dense arrays.  Functions of several arguments
+// broadcast their operands together; use array.Scalar for constant
+// arguments.
+package special
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Gamma returns the gamma function of every item of x.
+func Gamma(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, math.Gamma)
+}
+
+// LGamma returns the natural logarithm of the absolute value of the
+// gamma function of every item of x.
+func LGamma(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, specfun.LGamma)
+}
+
+// Digamma returns the logarithmic derivative of the gamma function of
+// every item of x.
+func Digamma(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, specfun.Digamma)
+}
+
+// Beta returns the beta function B(a, b) = Γ(a)Γ(b)/Γ(a+b).
+func Beta(a, b *array.Dense) (*array.Dense, error) {
+	return array.Map2(a, b, specfun.Beta)
+}
+
+// LBeta returns the natural logarithm of the absolute value of the beta
+// function.
+func LBeta(a, b *array.Dense) (*array.Dense, error) {
+	return array.Map2(a, b, specfun.LBeta)
+}
+
+// GammaInc returns the regularized lower incomplete gamma function
+// P(a, x) = γ(a, x)/Γ(a).
+func GammaInc(a, x *array.Dense) (*array.Dense, error) {
+	return array.Map2(a, x, specfun.GammaInc)
+}
+
+// GammaIncC returns the regularized upper incomplete gamma function
+// Q(a, x) = 1 - P(a, x).
+func GammaIncC(a, x *array.Dense) (*array.Dense, error) {
+	return array.Map2(a, x, specfun.GammaIncC)
+}
+
+// BetaInc returns the regularized incomplete beta function I_x(a, b).
+func BetaInc(a, b, x *array.Dense) (*array.Dense, error) {
+	return array.MapN(func(args []float64) float64 {
+		return specfun.BetaInc(args[0], args[1], args[2])
+	}, a, b, x)
+}
+
+// Erf returns the error function of every item of x.
+func Erf(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, math.Erf)
+}
+
+// Erfc returns the complementary error function of every item of x.
+func Erfc(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, math.Erfc)
+}
+
+// Erfinv returns the inverse error function of every item of x.
+func Erfinv(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, math.Erfinv)
+}
+
+// Erfcinv returns the inverse complementary error function of every
+// item of x, accurate far into the tails, where x is near 0 or 2.
+func Erfcinv(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, specfun.Erfcinv)
+}
+
+// Expit returns the logistic sigmoid 1/(1+exp(-x)) of every item of x.
+func Expit(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, specfun.Expit)
+}
+
+// Logit returns log(p/(1-p)), the inverse of Expit, of every item of p.
+func Logit(p *array.Dense) (*array.Dense, error) {
+	return array.Map(p, specfun.Logit)
+}
+
+// Softplus returns log(1+exp(x)) of every item of x, computed without
+// overflow.
+func Softplus(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, specfun.Softplus)
+}
@@ -0,0 +1,123 @@
+package special_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/special"
+)
+
+func TestGammaFunctions(t *testing.T) {
+	x := arraytest.Vector(t, 0.5, 1, 5)
+	out, err := special.Gamma(x)
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{math.Sqrt(math.Pi), 1, 24}, out.Data, 1e-12)
+	}
+	out, err = special.LGamma(arraytest.Vector(t, -0.5, 100))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.Log(2*math.Sqrt(math.Pi)), out.Data[0], 1e-12)
+		assert.InDelta(t, 359.1342053695754, out.Data[1], 1e-9)
+	}
+	out, err = special.Digamma(arraytest.Vector(t, 1, 0.5, -0.5, 30, 0, -2))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, -0.5772156649015329, out.Data[0], 1e-14)
+		assert.InDelta(t, -1.9635100260214235, out.Data[1], 1e-14)
+		assert.InDelta(t, 0.03648997397857652, out.Data[2], 1e-14)
+		harmonic := 0.0
+		for k := 1; k < 30; k++ {
+			harmonic += 1 / float64(k)
+		}
+		assert.InDelta(t, harmonic-0.5772156649015329, out.Data[3], 1e-14)
+		assert.True(t, math.IsNaN(out.Data[4]))
+		assert.True(t, math.IsNaN(out.Data[5]))
+	}
+	out, err = special.Beta(arraytest.Vector(t, 2, 0.5, 200), arraytest.Vector(t, 3, 0.5, 200))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 1./12, out.Data[0], 1e-15)
+		assert.InDelta(t, math.Pi, out.Data[1], 1e-13)
+		expected := math.Exp(2*special200() - special400())
+		assert.InDelta(t, 1, out.Data[2]/expected, 1e-9)
+	}
+}
+
+func special200() float64 { v, _ := math.Lgamma(200); return v }
+func special400() float64 { v, _ := math.Lgamma(400); return v }
+
+func TestIncompleteFunctions(t *testing.T) {
+	x := arraytest.Vector(t, 0, 0.1, 1, 3, 20)
+	p, err := special.GammaInc(array.Scalar(1), x)
+	q, err2 := special.GammaIncC(array.Scalar(1), x)
+	if assert.Nil(t, err) && assert.Nil(t, err2) {
+		for i, v := range x.Data {
+			assert.InDelta(t, 1-math.Exp(-v), p.Data[i], 1e-14)
+			assert.InDelta(t, math.Exp(-v), q.Data[i], 1e-14)
+		}
+	}
+	p, err = special.GammaInc(array.Scalar(0.5), x)
+	if assert.Nil(t, err) {
+		for i, v := range x.Data {
+			assert.InDelta(t, math.Erf(math.Sqrt(v)), p.Data[i], 1e-14)
+		}
+	}
+	p, err = special.GammaInc(array.Scalar(-1), x)
+	if assert.Nil(t, err) {
+		assert.True(t, math.IsNaN(p.Data[0]))
+	}
+	out, err := special.BetaInc(array.Scalar(2), array.Scalar(3), arraytest.Vector(t, 0, 0.4, 1))
+	if assert.Nil(t, err) {
+		assert.InDeltaSlice(t, []float64{0, 0.5248, 1}, out.Data, 1e-14)
+	}
+	out, err = special.BetaInc(arraytest.Vector(t, 0.5, 3.5), array.Scalar(1), array.Scalar(0.8))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, math.Pow(0.8, 0.5), out.Data[0], 1e-14)
+		assert.InDelta(t, math.Pow(0.8, 3.5), out.Data[1], 1e-14)
+	}
+}
+
+func TestErrorAndLogisticFunctions(t *testing.T) {
+	x := arraytest.Vector(t, -1, 0, 0.5)
+	erf, err := special.Erf(x)
+	if assert.Nil(t, err) {
+		inv, err := special.Erfinv(erf)
+		if assert.Nil(t, err) {
+			assert.InDeltaSlice(t, x.Data, inv.Data, 1e-14)
+		}
+	}
+	erfc, err := special.Erfc(x)
+	if assert.Nil(t, err) {
+		inv, err := special.Erfcinv(erfc)
+		if assert.Nil(t, err) {
+			assert.InDeltaSlice(t, x.Data, inv.Data, 1e-14)
+		}
+	}
+	// Tails, where inverting erf at 1 - x loses all precision.
+	inv, err := special.Erfcinv(arraytest.Vector(t, 1e-10, 1e-100, 1e-300))
+	if assert.Nil(t, err) {
+		assert.InEpsilonSlice(t, []float64{
+			4.572824967389485, 15.065574702592646, 26.209469960516124,
+		}, inv.Data, 1e-13)
+	}
+	x = arraytest.Vector(t, -800, -1, 0, 2, 800)
+	e, err := special.Expit(x)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []float64{0, 1 / (1 + math.E), 0.5, 1 / (1 + math.Exp(-2)), 1}, e.Data)
+	}
+	l, err := special.Logit(arraytest.Vector(t, 0.5, 1/(1+math.E), 0, 1))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0.0, l.Data[0])
+		assert.InDelta(t, -1, l.Data[1], 1e-15)
+		assert.True(t, math.IsInf(l.Data[2], -1))
+		assert.True(t, math.IsInf(l.Data[3], 1))
+	}
+	s, err := special.Softplus(x)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0.0, s.Data[0])
+		assert.InDelta(t, math.Log1p(math.Exp(-1)), s.Data[1], 1e-15)
+		assert.InDelta(t, math.Ln2, s.Data[2], 1e-15)
+		assert.Equal(t, 800.0, s.Data[4])
+	}
+}
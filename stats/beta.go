@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Beta is the beta distribution on [0, 1] with shape parameters Alpha
+// and Beta, whose density is x^(α-1) (1-x)^(β-1) / B(α, β).
+type Beta struct {
+	Alpha, Beta float64
+}
+
+// NewBeta returns a beta distribution.  Both shape parameters must be
+// positive.
+func NewBeta(alpha, beta float64) (*Beta, error) {
+	if !positive(alpha, beta) {
+		return nil, ErrInvalidParameter
+	}
+	return &Beta{Alpha: alpha, Beta: beta}, nil
+}
+
+func (d Beta) logPDF(x float64) float64 {
+	if x < 0 || x > 1 {
+		return math.Inf(-1)
+	}
+	return xlogy(d.Alpha-1, x) + xlog1py(d.Beta-1, -x) -
+		specfun.LBeta(d.Alpha, d.Beta)
+}
+
+func (d Beta) pdf(x float64) float64 {
+	return math.Exp(d.logPDF(x))
+}
+
+func (d Beta) cdf(x float64) float64 {
+	return specfun.BetaInc(d.Alpha, d.Beta, math.Max(0, math.Min(1, x)))
+}
+
+func (d Beta) sf(x float64) float64 {
+	return specfun.BetaInc(d.Beta, d.Alpha, 1-math.Max(0, math.Min(1, x)))
+}
+
+func (d Beta) ppf(q float64) float64 {
+	return specfun.BetaIncInv(d.Alpha, d.Beta, q)
+}
+
+// PDF returns the probability density function.
+func (d Beta) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d Beta) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Beta) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Beta) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d Beta) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns α/(α+β).
+func (d Beta) Mean() float64 { return d.Alpha / (d.Alpha + d.Beta) }
+
+// Variance returns αβ/((α+β)²(α+β+1)).
+func (d Beta) Variance() float64 {
+	s := d.Alpha + d.Beta
+	return d.Alpha * d.Beta / (s * s * (s + 1))
+}
+
+// Skewness returns 2(β-α)√(α+β+1)/((α+β+2)√(αβ)).
+func (d Beta) Skewness() float64 {
+	a, b := d.Alpha, d.Beta
+	return 2 * (b - a) * math.Sqrt(a+b+1) / ((a + b + 2) * math.Sqrt(a*b))
+}
+
+// Kurtosis returns the excess kurtosis.
+func (d Beta) Kurtosis() float64 {
+	a, b := d.Alpha, d.Beta
+	num := (a-b)*(a-b)*(a+b+1) - a*b*(a+b+2)
+	return 6 * num / (a * b * (a + b + 2) * (a + b + 3))
+}
+
+// Rvs returns beta variates.
+func (d Beta) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		x := gammaVariate(src, d.Alpha)
+		y := gammaVariate(src, d.Beta)
+		return x / (x + y)
+	})
+}
+
+// FitBeta returns the maximum likelihood estimate of the beta
+// distribution of the items of x, which must lie in the open interval
+// (0, 1).  The search starts from the method of moments estimate.
+func FitBeta(x *array.Dense) (*Beta, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	meanLog, meanLog1m := 0.0, 0.0
+	for _, v := range values {
+		if !(v > 0 && v < 1) {
+			return nil, ErrInvalidSample
+		}
+		meanLog += math.Log(v)
+		meanLog1m += math.Log1p(-v)
+	}
+	n := float64(len(values))
+	meanLog /= n
+	meanLog1m /= n
+	m := meanOf(values)
+	v := 0.0
+	for _, x := range values {
+		v += (x - m) * (x - m)
+	}
+	v /= n
+	p0 := []float64{1, 1}
+	if common := m*(1-m)/v - 1; common > 0 {
+		p0 = []float64{m * common, (1 - m) * common}
+	}
+	params, err := maximizeLikelihood(p0, func(p []float64) float64 {
+		return (p[0]-1)*meanLog + (p[1]-1)*meanLog1m - specfun.LBeta(p[0], p[1])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewBeta(params[0], params[1])
+}
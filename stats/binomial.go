@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Binomial is the binomial distribution of the number of successes in
+// N independent trials with success probability P.
+type Binomial struct {
+	N int
+	P float64
+}
+
+// NewBinomial returns a binomial distribution.  N must be non-negative
+// and P must lie in [0, 1].
+func NewBinomial(n int, p float64) (*Binomial, error) {
+	if n < 0 || !(p >= 0 && p <= 1) {
+		return nil, ErrInvalidParameter
+	}
+	return &Binomial{N: n, P: p}, nil
+}
+
+func (d Binomial) logPMF(k float64) float64 {
+	n := float64(d.N)
+	switch {
+	case math.IsNaN(k):
+		return k
+	case k < 0 || k > n || !isInteger(k):
+		return math.Inf(-1)
+	}
+	choose := specfun.LGamma(n+1) - specfun.LGamma(k+1) - specfun.LGamma(n-k+1)
+	return choose + xlogy(k, d.P) + xlog1py(n-k, -d.P)
+}
+
+func (d Binomial) pmf(k float64) float64 {
+	return math.Exp(d.logPMF(k))
+}
+
+func (d Binomial) cdf(x float64) float64 {
+	n := float64(d.N)
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x < 0:
+		return 0
+	case x >= n:
+		return 1
+	}
+	k := math.Floor(x)
+	return specfun.BetaInc(n-k, k+1, 1-d.P)
+}
+
+func (d Binomial) sf(x float64) float64 {
+	n := float64(d.N)
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x < 0:
+		return 1
+	case x >= n:
+		return 0
+	}
+	k := math.Floor(x)
+	return specfun.BetaInc(k+1, n-k, d.P)
+}
+
+func (d Binomial) ppf(q float64) float64 {
+	return discreteQuantile(d, q, d.cdf, float64(d.N))
+}
+
+// PMF returns the probability mass function.
+func (d Binomial) PMF(k *array.Dense) (*array.Dense, error) {
+	return array.Map(k, d.pmf)
+}
+
+// LogPMF returns the natural logarithm of the probability mass
+// function.
+func (d Binomial) LogPMF(k *array.Dense) (*array.Dense, error) {
+	return array.Map(k, d.logPMF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Binomial) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Binomial) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function, the smallest k with
+// CDF(k) >= q.
+func (d Binomial) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns NP.
+func (d Binomial) Mean() float64 { return float64(d.N) * d.P }
+
+// Variance returns NP(1-P).
+func (d Binomial) Variance() float64 { return float64(d.N) * d.P * (1 - d.P) }
+
+// Skewness returns (1-2P)/√(NP(1-P)).
+func (d Binomial) Skewness() float64 {
+	return (1 - 2*d.P) / math.Sqrt(d.Variance())
+}
+
+// Kurtosis returns (1-6P(1-P))/(NP(1-P)).
+func (d Binomial) Kurtosis() float64 {
+	return (1 - 6*d.P*(1-d.P)) / d.Variance()
+}
+
+// Rvs returns binomial variates by inversion.
+func (d Binomial) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		return d.ppf(src.Float64())
+	})
+}
+
+// FitBinomial returns the maximum likelihood estimate of the binomial
+// distribution with n trials of the items of x, whose success
+// probability is their mean divided by n.  The items must be integers
+// in [0, n].
+func FitBinomial(n int, x *array.Dense) (*Binomial, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, ErrInvalidParameter
+	}
+	for _, v := range values {
+		if v < 0 || v > float64(n) || !isInteger(v) {
+			return nil, ErrInvalidSample
+		}
+	}
+	return NewBinomial(n, meanOf(values)/float64(n))
+}
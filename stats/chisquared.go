@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// ChiSquared is the chi-squared distribution with K degrees of freedom,
+// the gamma distribution with shape K/2 and scale 2.
+type ChiSquared struct {
+	K float64
+}
+
+// NewChiSquared returns a chi-squared distribution.  The degrees of
+// freedom must be positive.
+func NewChiSquared(k float64) (*ChiSquared, error) {
+	if !positive(k) {
+		return nil, ErrInvalidParameter
+	}
+	return &ChiSquared{K: k}, nil
+}
+
+func (d ChiSquared) gamma() Gamma {
+	return Gamma{Shape: d.K / 2, Scale: 2}
+}
+
+// PDF returns the probability density function.
+func (d ChiSquared) PDF(x *array.Dense) (*array.Dense, error) {
+	return d.gamma().PDF(x)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d ChiSquared) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return d.gamma().LogPDF(x)
+}
+
+// CDF returns the cumulative distribution function.
+func (d ChiSquared) CDF(x *array.Dense) (*array.Dense, error) {
+	return d.gamma().CDF(x)
+}
+
+// SF returns the survival function.
+func (d ChiSquared) SF(x *array.Dense) (*array.Dense, error) {
+	return d.gamma().SF(x)
+}
+
+// PPF returns the percent point function.
+func (d ChiSquared) PPF(q *array.Dense) (*array.Dense, error) {
+	return d.gamma().PPF(q)
+}
+
+// Mean returns K.
+func (d ChiSquared) Mean() float64 { return d.K }
+
+// Variance returns 2K.
+func (d ChiSquared) Variance() float64 { return 2 * d.K }
+
+// Skewness returns √(8/K).
+func (d ChiSquared) Skewness() float64 { return math.Sqrt(8 / d.K) }
+
+// Kurtosis returns 12/K.
+func (d ChiSquared) Kurtosis() float64 { return 12 / d.K }
+
+// Rvs returns chi-squared variates.
+func (d ChiSquared) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return d.gamma().Rvs(shape, src)
+}
+
+// FitChiSquared returns the maximum likelihood estimate of the
+// chi-squared distribution of the items of x, which must be positive.
+func FitChiSquared(x *array.Dense) (*ChiSquared, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	_, meanLog, err := logMoments(values)
+	if err != nil {
+		return nil, err
+	}
+	// The degrees of freedom solve ψ(K/2) = mean(log x) - log 2.
+	c := meanLog - math.Ln2
+	half, err := solveMonotonic(func(h float64) float64 {
+		return specfun.Digamma(h) - c
+	}, math.Exp(c)+0.5)
+	if err != nil {
+		return nil, err
+	}
+	return NewChiSquared(2 * half)
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// ContingencyResult is the outcome of a chi-square test of independence.
+type ContingencyResult struct {
+	TestResult
+
+	// Expected holds the frequencies expected under independence, with
+	// the shape of the observed table.
+	Expected *array.Dense
+}
+
+// ChiSquareContingency performs Pearson's chi-square test of the null
+// hypothesis that the rows and columns of a two-dimensional table of
+// observed frequencies are independent.  When correction is set and
+// the table has one degree of freedom, Yates' correction for continuity
+// moves every observed frequency by 0.5 towards its expected value.
+func ChiSquareContingency(observed *array.Dense, correction bool) (*ContingencyResult, error) {
+	if len(observed.Shape) != 2 {
+		return nil, ErrInvalidTable
+	}
+	rows, cols := observed.Shape[0], observed.Shape[1]
+	values := observed.Values()
+	rowSums := make([]float64, rows)
+	colSums := make([]float64, cols)
+	total := 0.0
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			v := values[i*cols+j]
+			if !(v >= 0) || math.IsInf(v, 1) {
+				return nil, ErrInvalidTable
+			}
+			rowSums[i] += v
+			colSums[j] += v
+			total += v
+		}
+	}
+	for _, s := range append(rowSums, colSums...) {
+		if s == 0 {
+			return nil, ErrInvalidTable
+		}
+	}
+	expected := make([]float64, len(values))
+	dof := float64((rows - 1) * (cols - 1))
+	stat := 0.0
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			k := i*cols + j
+			expected[k] = rowSums[i] * colSums[j] / total
+			diff := math.Abs(values[k] - expected[k])
+			if correction && dof == 1 {
+				diff -= math.Min(0.5, diff)
+			}
+			stat += diff * diff / expected[k]
+		}
+	}
+	p := 1.0
+	if dof > 0 {
+		p = ChiSquared{K: dof}.gamma().sf(stat)
+	}
+	exp, err := array.NewDenseWithValues(observed.Shape, expected, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return &ContingencyResult{
+		TestResult: TestResult{Statistic: stat, PValue: p, DoF: dof},
+		Expected:   exp,
+	}, nil
+}
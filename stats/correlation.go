@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Pearson returns Pearson's correlation coefficient of paired samples
+// x and y, and the p-value of the test of the null hypothesis that
+// they are uncorrelated, which assumes normally distributed samples.
+func Pearson(x, y *array.Dense, alt Alternative) (TestResult, error) {
+	xs, ys, err := pairs(x, y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	return pearson(xs, ys, alt)
+}
+
+// Spearman returns Spearman's rank correlation coefficient of paired
+// samples x and y, the Pearson correlation of their ranks, and the
+// p-value of the test of the null hypothesis that they are
+// uncorrelated, given by the t distribution with n-2 degrees of
+// freedom.
+func Spearman(x, y *array.Dense, alt Alternative) (TestResult, error) {
+	xs, ys, err := pairs(x, y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	rx, _ := rank(xs)
+	ry, _ := rank(ys)
+	return pearson(rx, ry, alt)
+}
+
+func pearson(xs, ys []float64, alt Alternative) (TestResult, error) {
+	n := len(xs)
+	if n < 3 {
+		return TestResult{}, ErrTooFewObservations
+	}
+	mx, my := meanOf(xs), meanOf(ys)
+	sxy, sxx, syy := 0.0, 0.0, 0.0
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	r := sxy / math.Sqrt(sxx*syy)
+	r = math.Max(-1, math.Min(1, r))
+	dof := float64(n - 2)
+	t := r * math.Sqrt(dof/((1-r)*(1+r)))
+	return TestResult{
+		Statistic: r,
+		PValue:    pValue(StudentT{Nu: dof}, t, alt),
+		DoF:       dof,
+	}, nil
+}
+
+// Kendall returns Kendall's tau-b rank correlation coefficient of
+// paired samples x and y, which accounts for ties, and the p-value of
+// the test of the null hypothesis that they are independent, given by
+// the normal approximation to the distribution of the number of
+// concordant minus discordant pairs.  It takes time proportional to
+// the square of the sample size.
+func Kendall(x, y *array.Dense, alt Alternative) (TestResult, error) {
+	xs, ys, err := pairs(x, y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	n := len(xs)
+	if n < 3 {
+		return TestResult{}, ErrTooFewObservations
+	}
+	s := 0.0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			s += sign(xs[i]-xs[j]) * sign(ys[i]-ys[j])
+		}
+	}
+	_, tx := rank(xs)
+	_, ty := rank(ys)
+	fn := float64(n)
+	n0 := fn * (fn - 1) / 2
+	n1, v1, w1 := tieSums(tx)
+	n2, v2, w2 := tieSums(ty)
+	tau := s / math.Sqrt((n0-n1)*(n0-n2))
+	varS := (fn*(fn-1)*(2*fn+5)-v1-v2)/18 +
+		w1*w2/(9*fn*(fn-1)*(fn-2)) + 2*n1*n2/(fn*(fn-1))
+	z := s / math.Sqrt(varS)
+	return TestResult{
+		Statistic: tau,
+		PValue:    pValue(Normal{Sigma: 1}, z, alt),
+	}, nil
+}
+
+// tieSums returns, over the groups of ties of sizes t, the sums of
+// t(t-1)/2, t(t-1)(2t+5) and t(t-1)(t-2).
+func tieSums(ties []int) (count, v, w float64) {
+	for _, t := range ties {
+		ft := float64(t)
+		count += ft * (ft - 1) / 2
+		v += ft * (ft - 1) * (2*ft + 5)
+		w += ft * (ft - 1) * (ft - 2)
+	}
+	return count, v, w
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
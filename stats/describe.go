@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Description summarizes the items of an array along an axis.  Every
+// array has the shape of the described array without that axis.
+type Description struct {
+	// Count is the number of items in every lane.
+	Count int
+
+	Min, Max *array.Dense
+	Mean     *array.Dense
+
+	// Variance is the unbiased sample variance, with divisor Count-1.
+	Variance *array.Dense
+
+	// Skewness and Kurtosis are the biased sample skewness and excess
+	// kurtosis, computed from the central moments with divisor Count.
+	Skewness *array.Dense
+	Kurtosis *array.Dense
+}
+
+// Describe returns summary statistics of the items of x along axis.
+// Negative axes count from the last dimension.
+func Describe(x *array.Dense, axis int) (*Description, error) {
+	desc := &Description{}
+	var err error
+	if desc.Min, err = array.ReduceAlongAxis(x, axis, minimum); err != nil {
+		return nil, err
+	}
+	if desc.Max, err = array.ReduceAlongAxis(x, axis, maximum); err != nil {
+		return nil, err
+	}
+	if desc.Mean, err = array.ReduceAlongAxis(x, axis, meanOf); err != nil {
+		return nil, err
+	}
+	desc.Variance, err = array.ReduceAlongAxis(x, axis, func(v []float64) float64 {
+		return variance(v, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if desc.Skewness, err = array.ReduceAlongAxis(x, axis, skewness); err != nil {
+		return nil, err
+	}
+	if desc.Kurtosis, err = array.ReduceAlongAxis(x, axis, kurtosis); err != nil {
+		return nil, err
+	}
+	if axis < 0 {
+		axis += len(x.Shape)
+	}
+	desc.Count = x.Shape[axis]
+	return desc, nil
+}
+
+func minimum(values []float64) float64 {
+	m := math.Inf(1)
+	for _, v := range values {
+		m = math.Min(m, v)
+	}
+	return m
+}
+
+func maximum(values []float64) float64 {
+	m := math.Inf(-1)
+	for _, v := range values {
+		m = math.Max(m, v)
+	}
+	return m
+}
+
+// centralMoment returns the k-th central moment of values with divisor
+// n.
+func centralMoment(values []float64, k int) float64 {
+	mean := meanOf(values)
+	sum := 0.0
+	for _, v := range values {
+		d := v - mean
+		p := 1.0
+		for i := 0; i < k; i++ {
+			p *= d
+		}
+		sum += p
+	}
+	return sum / float64(len(values))
+}
+
+// variance returns the sample variance of values with divisor n-ddof.
+func variance(values []float64, ddof int) float64 {
+	n := float64(len(values))
+	return centralMoment(values, 2) * n / (n - float64(ddof))
+}
+
+func skewness(values []float64) float64 {
+	m2 := centralMoment(values, 2)
+	return centralMoment(values, 3) / math.Pow(m2, 1.5)
+}
+
+func kurtosis(values []float64) float64 {
+	m2 := centralMoment(values, 2)
+	return centralMoment(values, 4)/(m2*m2) - 3
+}
@@ -0,0 +1,41 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/stats"
+)
+
+func TestDescribe(t *testing.T) {
+	x, err := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{1, 2, 3, 4, 6, 11}, array.DefaultAttributes)
+	if !assert.Nil(t, err) {
+		return
+	}
+	d, err := stats.Describe(x, -1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 3, d.Count)
+		assert.Equal(t, array.Shape{2}, d.Mean.Shape)
+		assert.Equal(t, []float64{1, 4}, d.Min.Data)
+		assert.Equal(t, []float64{3, 11}, d.Max.Data)
+		assert.InDeltaSlice(t, []float64{2, 7}, d.Mean.Data, 1e-15)
+		assert.InDeltaSlice(t, []float64{1, 13}, d.Variance.Data, 1e-14)
+		assert.InDeltaSlice(t, []float64{0, 12 / math.Pow(26./3, 1.5)}, d.Skewness.Data, 1e-14)
+		assert.InDeltaSlice(t, []float64{-1.5, -1.5}, d.Kurtosis.Data, 1e-14)
+	}
+	d, err = stats.Describe(x, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, d.Count)
+		assert.Equal(t, array.Shape{3}, d.Mean.Shape)
+		assert.InDeltaSlice(t, []float64{2.5, 4, 7}, d.Mean.Data, 1e-15)
+		assert.InDeltaSlice(t, []float64{4.5, 8, 32}, d.Variance.Data, 1e-14)
+		assert.InDeltaSlice(t, []float64{0, 0, 0}, d.Skewness.Data, 1e-15)
+		assert.InDeltaSlice(t, []float64{-2, -2, -2}, d.Kurtosis.Data, 1e-15)
+	}
+	_, err = stats.Describe(x, 2)
+	assert.IsType(t, &array.AxisError{}, err)
+}
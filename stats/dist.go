@@ -0,0 +1,133 @@
+// Package stats provides probability distributions evaluated
+// elementwise over Dense arrays, random sampling and maximum likelihood
+// estimation.
+package stats
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Distribution is a univariate probability distribution.  Functions of
+// arrays return new arrays with the shape of their argument.
+type Distribution interface {
+	// CDF returns the cumulative distribution function P(X <= x).
+	CDF(x *array.Dense) (*array.Dense, error)
+
+	// SF returns the survival function P(X > x), computed without the
+	// cancellation of 1 - CDF(x) in the upper tail.
+	SF(x *array.Dense) (*array.Dense, error)
+
+	// PPF returns the percent point function, the inverse of CDF.
+	// Probabilities outside [0, 1] give NaN.
+	PPF(q *array.Dense) (*array.Dense, error)
+
+	// Mean, Variance, Skewness and Kurtosis return the moments of the
+	// distribution.  Kurtosis is the excess kurtosis, zero for the
+	// normal distribution.  Undefined moments are NaN and divergent
+	// ones are +Inf.
+	Mean() float64
+	Variance() float64
+	Skewness() float64
+	Kurtosis() float64
+
+	// Rvs returns an array of the given shape holding random variates
+	// drawn from src.
+	Rvs(shape array.Shape, src Source) (*array.Dense, error)
+}
+
+// Continuous is a distribution with a probability density function.
+type Continuous interface {
+	Distribution
+
+	// PDF returns the probability density function.
+	PDF(x *array.Dense) (*array.Dense, error)
+
+	// LogPDF returns the natural logarithm of PDF.
+	LogPDF(x *array.Dense) (*array.Dense, error)
+}
+
+// Discrete is a distribution over the integers with a probability mass
+// function.
+type Discrete interface {
+	Distribution
+
+	// PMF returns the probability mass function.  Items that are not
+	// integers have zero mass.
+	PMF(k *array.Dense) (*array.Dense, error)
+
+	// LogPMF returns the natural logarithm of PMF.
+	LogPMF(k *array.Dense) (*array.Dense, error)
+}
+
+// Source generates the uniform, normal and exponential variates from
+// which all others are drawn.  *rand.Rand satisfies it, so a sequence
+// can be reproduced with rand.New(rand.NewSource(seed)).  A nil Source
+// uses the default source of math/rand.
+type Source interface {
+	Float64() float64
+	NormFloat64() float64
+	ExpFloat64() float64
+}
+
+type globalSource struct{}
+
+func (globalSource) Float64() float64     { return rand.Float64() }
+func (globalSource) NormFloat64() float64 { return rand.NormFloat64() }
+func (globalSource) ExpFloat64() float64  { return rand.ExpFloat64() }
+
+func source(src Source) Source {
+	if src == nil {
+		return globalSource{}
+	}
+	return src
+}
+
+// sample returns an array of the given shape filled by calls to fn.
+func sample(shape array.Shape, src Source, fn func(src Source) float64) (*array.Dense, error) {
+	out, err := array.NewDense(shape, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	src = source(src)
+	for i := range out.Data {
+		out.Data[i] = fn(src)
+	}
+	return out, nil
+}
+
+// discreteQuantile returns the smallest integer k in [0, upper] with
+// cdf(k) >= q.  The search starts from a Cornish-Fisher approximation
+// built from the moments of the distribution.
+func discreteQuantile(d Distribution, q float64, cdf func(float64) float64, upper float64) float64 {
+	switch {
+	case math.IsNaN(q) || q < 0 || q > 1:
+		return math.NaN()
+	case q == 0:
+		return 0
+	case q == 1:
+		return upper
+	}
+	z := specfun.NormalQuantile(q)
+	guess := d.Mean() + math.Sqrt(d.Variance())*(z+d.Skewness()*(z*z-1)/6)
+	k := math.Floor(guess)
+	if math.IsNaN(k) || k < 0 {
+		k = 0
+	}
+	k = math.Min(k, upper)
+	for k > 0 && cdf(k-1) >= q {
+		k--
+	}
+	for k < upper && cdf(k) < q {
+		k++
+	}
+	return k
+}
+
+// isInteger reports whether x is a finite integer.
+func isInteger(x float64) bool {
+	return x == math.Trunc(x) && !math.IsInf(x, 0)
+}
@@ -0,0 +1,244 @@
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/stats"
+)
+
+var (
+	_ stats.Continuous = stats.Normal{}
+	_ stats.Continuous = stats.StudentT{}
+	_ stats.Continuous = stats.ChiSquared{}
+	_ stats.Continuous = stats.F{}
+	_ stats.Continuous = stats.Gamma{}
+	_ stats.Continuous = stats.Beta{}
+	_ stats.Continuous = stats.Exponential{}
+	_ stats.Continuous = stats.Uniform{}
+	_ stats.Discrete   = stats.Poisson{}
+	_ stats.Discrete   = stats.Binomial{}
+)
+
+func apply(t *testing.T, fn func(*array.Dense) (*array.Dense, error), values ...float64) []float64 {
+	t.Helper()
+	out, err := fn(arraytest.Vector(t, values...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.Data
+}
+
+// checkQuantiles verifies that PPF inverts CDF at the given points.
+func checkQuantiles(t *testing.T, d stats.Continuous, x ...float64) {
+	t.Helper()
+	q := apply(t, d.CDF, x...)
+	back := apply(t, d.PPF, q...)
+	for i := range x {
+		assert.InDelta(t, x[i], back[i], 1e-9*math.Max(1, math.Abs(x[i])), "%T at %v", d, x[i])
+	}
+	sf := apply(t, d.SF, x...)
+	for i := range x {
+		assert.InDelta(t, 1, q[i]+sf[i], 1e-14)
+	}
+}
+
+func TestNormal(t *testing.T) {
+	d, err := stats.NewNormal(0, 1)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.InDeltaSlice(t, []float64{0.025, 0.5, 0.975},
+		apply(t, d.CDF, -1.959963984540054, 0, 1.959963984540054), 1e-15)
+	assert.InDelta(t, 1.959963984540054, apply(t, d.PPF, 0.975)[0], 1e-14)
+	assert.InDelta(t, 1/math.Sqrt(2*math.Pi), apply(t, d.PDF, 0)[0], 1e-15)
+	assert.InDelta(t, 7.61985302416047e-24, apply(t, d.SF, 10)[0], 1e-36)
+	assert.True(t, math.IsNaN(apply(t, d.PPF, 1.5)[0]))
+	// Far tails keep their relative accuracy, and map back through CDF.
+	tails := []float64{1e-10, 1e-20, 1e-300}
+	z := apply(t, d.PPF, tails...)
+	assert.InEpsilonSlice(t, []float64{
+		-6.361340902404056, -9.262340089798408, -37.047096299361199,
+	}, z, 1e-14)
+	assert.InEpsilonSlice(t, tails, apply(t, d.CDF, z...), 1e-12)
+	d2 := stats.Normal{Mu: 3, Sigma: 2}
+	checkQuantiles(t, d2, -4, 0, 2.5, 9)
+	assert.InDelta(t, -0.5*math.Log(2*math.Pi)-math.Log(2)-1, apply(t, d2.LogPDF, 3+2*math.Sqrt2)[0], 1e-14)
+	_, err = stats.NewNormal(0, -1)
+	assert.ErrorIs(t, err, stats.ErrInvalidParameter)
+}
+
+func TestStudentT(t *testing.T) {
+	x := []float64{-30, -2, -0.3, 0, 0.3, 2, 30}
+	cauchy := stats.StudentT{Nu: 1}
+	two := stats.StudentT{Nu: 2}
+	cdf1 := apply(t, cauchy.CDF, x...)
+	pdf1 := apply(t, cauchy.PDF, x...)
+	cdf2 := apply(t, two.CDF, x...)
+	for i, v := range x {
+		assert.InDelta(t, 0.5+math.Atan(v)/math.Pi, cdf1[i], 1e-14, v)
+		assert.InDelta(t, 1/(math.Pi*(1+v*v)), pdf1[i], 1e-14, v)
+		assert.InDelta(t, 0.5+v/(2*math.Sqrt(2+v*v)), cdf2[i], 1e-14, v)
+	}
+	ten := stats.StudentT{Nu: 10}
+	assert.InDeltaSlice(t, []float64{-2.2281388519862748, 0, 2.2281388519862748},
+		apply(t, ten.PPF, 0.025, 0.5, 0.975), 1e-12)
+	checkQuantiles(t, stats.StudentT{Nu: 3.5}, -8, -1, 0.2, 4)
+	assert.True(t, math.IsNaN(cauchy.Mean()))
+	assert.True(t, math.IsInf(two.Variance(), 1))
+	assert.Equal(t, 1.25, ten.Variance())
+	assert.Equal(t, 1.0, ten.Kurtosis())
+}
+
+func TestChiSquaredAndGamma(t *testing.T) {
+	x := []float64{0.1, 1, 3.5, 12}
+	two := stats.ChiSquared{K: 2}
+	cdf := apply(t, two.CDF, x...)
+	sf := apply(t, two.SF, x...)
+	for i, v := range x {
+		assert.InDelta(t, -math.Expm1(-v/2), cdf[i], 1e-14, v)
+		assert.InDelta(t, math.Exp(-v/2), sf[i], 1e-14, v)
+	}
+	one := stats.ChiSquared{K: 1}
+	assert.InDelta(t, 3.841458820694124, apply(t, one.PPF, 0.95)[0], 1e-11)
+	checkQuantiles(t, stats.ChiSquared{K: 7}, 0.5, 3, 7, 20)
+
+	erlang := stats.Gamma{Shape: 3, Scale: 2}
+	cdf = apply(t, erlang.CDF, x...)
+	for i, v := range x {
+		y := v / 2
+		assert.InDelta(t, 1-math.Exp(-y)*(1+y+y*y/2), cdf[i], 1e-14, v)
+	}
+	assert.InDeltaSlice(t, []float64{0, 0, math.Exp(-1) / 4},
+		apply(t, erlang.PDF, -1, 0, 2), 1e-15)
+	checkQuantiles(t, stats.Gamma{Shape: 0.3, Scale: 1.5}, 1e-3, 0.1, 1, 6)
+	checkQuantiles(t, stats.Gamma{Shape: 40, Scale: 0.5}, 12, 20, 31)
+	assert.True(t, math.IsInf(apply(t, stats.Gamma{Shape: 0.5, Scale: 1}.PDF, 0)[0], 1))
+	assert.InDelta(t, -math.Log(2), apply(t, stats.Gamma{Shape: 1, Scale: 2}.LogPDF, 0)[0], 1e-15)
+}
+
+func TestFAndBeta(t *testing.T) {
+	x := []float64{0, 0.2, 1, 4.5, math.Inf(1)}
+	f := stats.F{D1: 2, D2: 2}
+	cdf := apply(t, f.CDF, x...)
+	for i, v := range x[:4] {
+		assert.InDelta(t, v/(1+v), cdf[i], 1e-14, v)
+	}
+	assert.Equal(t, 1.0, cdf[4])
+	assert.InDelta(t, 1/(1.2*1.2), apply(t, f.PDF, 0.2)[0], 1e-14)
+	checkQuantiles(t, stats.F{D1: 5, D2: 7}, 0.05, 0.8, 3, 15)
+
+	b := stats.Beta{Alpha: 2, Beta: 3}
+	x = []float64{0, 0.1, 0.5, 0.9, 1}
+	cdf = apply(t, b.CDF, x...)
+	pdf := apply(t, b.PDF, x...)
+	for i, v := range x {
+		assert.InDelta(t, 6*v*v-8*v*v*v+3*v*v*v*v, cdf[i], 1e-14, v)
+		assert.InDelta(t, 12*v*(1-v)*(1-v), pdf[i], 1e-14, v)
+	}
+	checkQuantiles(t, stats.Beta{Alpha: 0.5, Beta: 0.7}, 1e-4, 0.3, 0.8, 0.999)
+	checkQuantiles(t, stats.Beta{Alpha: 30, Beta: 4}, 0.6, 0.85, 0.95)
+	assert.InDelta(t, 0.4, b.Mean(), 1e-15)
+	assert.InDelta(t, 0.04, b.Variance(), 1e-15)
+}
+
+func TestExponentialAndUniform(t *testing.T) {
+	e := stats.Exponential{Rate: 2}
+	assert.InDeltaSlice(t, []float64{0, 2, 2 * math.Exp(-2)},
+		apply(t, e.PDF, -1, 0, 1), 1e-15)
+	checkQuantiles(t, e, 0.01, 1, 8)
+	u := stats.Uniform{Min: -1, Max: 3}
+	assert.Equal(t, []float64{0, 0.25, 0.25, 0}, apply(t, u.PDF, -2, -1, 3, 3.5))
+	assert.Equal(t, []float64{0, 0.375, 1}, apply(t, u.CDF, -2, 0.5, 5))
+	assert.Equal(t, []float64{-1, 1, 3}, apply(t, u.PPF, 0, 0.5, 1))
+	_, err := stats.NewUniform(1, 1)
+	assert.ErrorIs(t, err, stats.ErrInvalidParameter)
+}
+
+// checkDiscrete verifies that CDF accumulates PMF and that PPF returns
+// the smallest k with CDF(k) >= q.
+func checkDiscrete(t *testing.T, d stats.Discrete, upper int) {
+	t.Helper()
+	ks := make([]float64, upper+1)
+	for i := range ks {
+		ks[i] = float64(i)
+	}
+	pmf := apply(t, d.PMF, ks...)
+	cdf := apply(t, d.CDF, ks...)
+	sf := apply(t, d.SF, ks...)
+	sum := 0.0
+	for i := range ks {
+		sum += pmf[i]
+		assert.InDelta(t, sum, cdf[i], 1e-12, "%T at %d", d, i)
+		assert.InDelta(t, 1, cdf[i]+sf[i], 1e-14)
+	}
+	qs := []float64{0.001, 0.1, 0.5, 0.77, 0.999}
+	for i, k := range apply(t, d.PPF, qs...) {
+		c := apply(t, d.CDF, k-1, k)
+		assert.True(t, c[0] < qs[i] && c[1] >= qs[i], "%T ppf(%v) = %v", d, qs[i], k)
+	}
+}
+
+func TestPoisson(t *testing.T) {
+	d := stats.Poisson{Lambda: 2.5}
+	assert.InDelta(t, math.Pow(2.5, 3)*math.Exp(-2.5)/6, apply(t, d.PMF, 3)[0], 1e-15)
+	assert.Equal(t, []float64{0, 0, 0}, apply(t, d.PMF, -1, 1.5, math.Inf(1)))
+	assert.Equal(t, apply(t, d.CDF, 2), apply(t, d.CDF, 2.7))
+	checkDiscrete(t, d, 20)
+	checkDiscrete(t, stats.Poisson{Lambda: 1000}, 1200)
+	assert.Equal(t, []float64{0, 1}, apply(t, stats.Poisson{}.CDF, -0.5, 0))
+}
+
+func TestBinomial(t *testing.T) {
+	d := stats.Binomial{N: 10, P: 0.3}
+	assert.InDelta(t, 120*math.Pow(0.3, 3)*math.Pow(0.7, 7), apply(t, d.PMF, 3)[0], 1e-14)
+	checkDiscrete(t, d, 10)
+	checkDiscrete(t, stats.Binomial{N: 500, P: 0.02}, 500)
+	assert.Equal(t, []float64{1, 0, 0}, apply(t, stats.Binomial{N: 4, P: 0}.PMF, 0, 1, 4))
+	assert.Equal(t, []float64{0, 1}, apply(t, stats.Binomial{N: 4, P: 1}.PMF, 3, 4))
+	assert.Equal(t, []float64{0, 10}, apply(t, d.PPF, 0, 1))
+}
+
+func TestRvsMoments(t *testing.T) {
+	const n = 40000
+	for _, d := range []stats.Distribution{
+		stats.Normal{Mu: -2, Sigma: 3},
+		stats.StudentT{Nu: 6},
+		stats.ChiSquared{K: 3},
+		stats.F{D1: 4, D2: 20},
+		stats.Gamma{Shape: 0.4, Scale: 2},
+		stats.Gamma{Shape: 7, Scale: 0.5},
+		stats.Beta{Alpha: 0.8, Beta: 2.5},
+		stats.Exponential{Rate: 4},
+		stats.Uniform{Min: 1, Max: 2},
+		stats.Poisson{Lambda: 3.2},
+		stats.Binomial{N: 20, P: 0.65},
+	} {
+		src := rand.New(rand.NewSource(7))
+		x, err := d.Rvs(array.Shape{n / 100, 100}, src)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Equal(t, array.Shape{n / 100, 100}, x.Shape)
+		mean, ss := 0.0, 0.0
+		for _, v := range x.Data {
+			mean += v
+		}
+		mean /= n
+		for _, v := range x.Data {
+			ss += (v - mean) * (v - mean)
+		}
+		variance := ss / n
+		assert.InDelta(t, d.Mean(), mean, 5*math.Sqrt(d.Variance()/n), "%T", d)
+		assert.InDelta(t, d.Variance(), variance, 0.06*d.Variance(), "%T", d)
+
+		// Seeded generators reproduce the same variates.
+		y, _ := d.Rvs(array.Shape{3}, rand.New(rand.NewSource(7)))
+		assert.Equal(t, x.Data[:3], y.Data, "%T", d)
+	}
+}
@@ -0,0 +1,29 @@
+package stats
+
+import "errors"
+
+var (
+	// ErrInvalidParameter is returned when a distribution parameter is
+	// outside its domain.
+	ErrInvalidParameter = errors.New("invalid distribution parameter")
+
+	// ErrEmptySample is returned when a sample has no items.
+	ErrEmptySample = errors.New("sample is empty")
+
+	// ErrInvalidSample is returned when a sample has items outside the
+	// support of the distribution being fitted.
+	ErrInvalidSample = errors.New("sample is outside the support")
+
+	// ErrTooFewObservations is returned when a sample is too small for
+	// a statistic to be defined.
+	ErrTooFewObservations = errors.New("too few observations")
+
+	// ErrUnmatchedSamples is returned when paired samples have different
+	// lengths.
+	ErrUnmatchedSamples = errors.New("samples have different lengths")
+
+	// ErrInvalidTable is returned when a contingency table is not a
+	// two-dimensional array of non-negative frequencies with non-zero
+	// marginal totals.
+	ErrInvalidTable = errors.New("invalid contingency table")
+)
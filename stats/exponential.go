@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Exponential is the exponential distribution with the given rate,
+// whose density is λ exp(-λx) for x >= 0.
+type Exponential struct {
+	Rate float64
+}
+
+// NewExponential returns an exponential distribution.  The rate must be
+// positive.
+func NewExponential(rate float64) (*Exponential, error) {
+	if !positive(rate) {
+		return nil, ErrInvalidParameter
+	}
+	return &Exponential{Rate: rate}, nil
+}
+
+func (d Exponential) logPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	return math.Log(d.Rate) - d.Rate*x
+}
+
+func (d Exponential) pdf(x float64) float64 {
+	return math.Exp(d.logPDF(x))
+}
+
+func (d Exponential) cdf(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return -math.Expm1(-d.Rate * x)
+}
+
+func (d Exponential) sf(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return math.Exp(-d.Rate * x)
+}
+
+func (d Exponential) ppf(q float64) float64 {
+	if q < 0 || q > 1 {
+		return math.NaN()
+	}
+	return -math.Log1p(-q) / d.Rate
+}
+
+// PDF returns the probability density function.
+func (d Exponential) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d Exponential) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Exponential) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Exponential) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d Exponential) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns 1/λ.
+func (d Exponential) Mean() float64 { return 1 / d.Rate }
+
+// Variance returns 1/λ².
+func (d Exponential) Variance() float64 { return 1 / (d.Rate * d.Rate) }
+
+// Skewness returns 2.
+func (d Exponential) Skewness() float64 { return 2 }
+
+// Kurtosis returns 6.
+func (d Exponential) Kurtosis() float64 { return 6 }
+
+// Rvs returns exponential variates.
+func (d Exponential) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		return src.ExpFloat64() / d.Rate
+	})
+}
+
+// FitExponential returns the maximum likelihood estimate of the
+// exponential distribution of the items of x, the reciprocal of their
+// mean.  The items must be non-negative and not all zero.
+func FitExponential(x *array.Dense) (*Exponential, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if v < 0 {
+			return nil, ErrInvalidSample
+		}
+	}
+	mean := meanOf(values)
+	if mean == 0 {
+		return nil, ErrInvalidSample
+	}
+	return NewExponential(1 / mean)
+}
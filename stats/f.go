@@ -0,0 +1,185 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// F is the F distribution with D1 and D2 degrees of freedom, the
+// distribution of the ratio of two chi-squared variates divided by
+// their degrees of freedom.
+type F struct {
+	D1, D2 float64
+}
+
+// NewF returns an F distribution.  The degrees of freedom must be
+// positive.
+func NewF(d1, d2 float64) (*F, error) {
+	if !positive(d1, d2) {
+		return nil, ErrInvalidParameter
+	}
+	return &F{D1: d1, D2: d2}, nil
+}
+
+func (d F) logPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	d1, d2 := d.D1, d.D2
+	return 0.5*(d1*math.Log(d1)+d2*math.Log(d2)) + xlogy(d1/2-1, x) -
+		(d1+d2)/2*math.Log(d2+d1*x) - specfun.LBeta(d1/2, d2/2)
+}
+
+func (d F) pdf(x float64) float64 {
+	return math.Exp(d.logPDF(x))
+}
+
+func (d F) cdf(x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case math.IsInf(x, 1):
+		return 1
+	}
+	return specfun.BetaInc(d.D1/2, d.D2/2, d.D1*x/(d.D1*x+d.D2))
+}
+
+func (d F) sf(x float64) float64 {
+	switch {
+	case x <= 0:
+		return 1
+	case math.IsInf(x, 1):
+		return 0
+	}
+	return specfun.BetaInc(d.D2/2, d.D1/2, d.D2/(d.D1*x+d.D2))
+}
+
+func (d F) ppf(q float64) float64 {
+	y := specfun.BetaIncInv(d.D1/2, d.D2/2, q)
+	return d.D2 * y / (d.D1 * (1 - y))
+}
+
+// PDF returns the probability density function.
+func (d F) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d F) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d F) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d F) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d F) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns D2/(D2-2) for D2 > 2 and +Inf otherwise.
+func (d F) Mean() float64 {
+	if d.D2 <= 2 {
+		return math.Inf(1)
+	}
+	return d.D2 / (d.D2 - 2)
+}
+
+// Variance returns the variance for D2 > 4, +Inf for 2 < D2 <= 4 and
+// NaN otherwise.
+func (d F) Variance() float64 {
+	d1, d2 := d.D1, d.D2
+	switch {
+	case d2 > 4:
+		return 2 * d2 * d2 * (d1 + d2 - 2) / (d1 * (d2 - 2) * (d2 - 2) * (d2 - 4))
+	case d2 > 2:
+		return math.Inf(1)
+	}
+	return math.NaN()
+}
+
+// Skewness returns the skewness for D2 > 6 and NaN otherwise.
+func (d F) Skewness() float64 {
+	d1, d2 := d.D1, d.D2
+	if d2 <= 6 {
+		return math.NaN()
+	}
+	return (2*d1 + d2 - 2) * math.Sqrt(8*(d2-4)) /
+		((d2 - 6) * math.Sqrt(d1*(d1+d2-2)))
+}
+
+// Kurtosis returns the excess kurtosis for D2 > 8 and NaN otherwise.
+func (d F) Kurtosis() float64 {
+	d1, d2 := d.D1, d.D2
+	if d2 <= 8 {
+		return math.NaN()
+	}
+	num := d1*(5*d2-22)*(d1+d2-2) + (d2-4)*(d2-2)*(d2-2)
+	return 12 * num / (d1 * (d2 - 6) * (d2 - 8) * (d1 + d2 - 2))
+}
+
+// Rvs returns F variates.
+func (d F) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		x := gammaVariate(src, d.D1/2) / d.D1
+		y := gammaVariate(src, d.D2/2) / d.D2
+		return x / y
+	})
+}
+
+// FitF returns the maximum likelihood estimate of the F distribution
+// of the items of x, which must be positive.
+func FitF(x *array.Dense) (*F, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if !(v > 0) {
+			return nil, ErrInvalidSample
+		}
+	}
+	params, err := maximizeLikelihood(fStart(values), func(p []float64) float64 {
+		d := F{D1: p[0], D2: p[1]}
+		sum := 0.0
+		for _, v := range values {
+			sum += d.logPDF(v)
+		}
+		return sum / float64(len(values))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewF(params[0], params[1])
+}
+
+// fStart returns method of moments estimates of the degrees of freedom
+// of an F sample, falling back to moderate values when the sample
+// moments do not determine them.
+func fStart(values []float64) []float64 {
+	d1, d2 := 5.0, 10.0
+	m := meanOf(values)
+	if m > 1 {
+		d2 = math.Max(2*m/(m-1), 4.5)
+	}
+	v := 0.0
+	for _, x := range values {
+		v += (x - m) * (x - m)
+	}
+	v /= float64(len(values))
+	den := v*(d2-2)*(d2-2)*(d2-4) - 2*d2*d2
+	if den > 0 {
+		d1 = 2 * d2 * d2 * (d2 - 2) / den
+	}
+	return []float64{d1, d2}
+}
@@ -0,0 +1,108 @@
+package stats
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/optimize"
+)
+
+// observations returns the items of a sample.
+func observations(x *array.Dense) ([]float64, error) {
+	values := x.Values()
+	if len(values) == 0 {
+		return nil, ErrEmptySample
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, ErrInvalidSample
+		}
+	}
+	return values, nil
+}
+
+// meanOf returns the arithmetic mean of values.
+func meanOf(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// positive reports whether all values are finite and positive.
+func positive(values ...float64) bool {
+	for _, v := range values {
+		if !(v > 0) || math.IsInf(v, 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// maximizeLikelihood returns the positive parameters maximizing the
+// mean log-likelihood logLik, starting from p0.  The search runs over
+// the logarithms of the parameters so that they stay positive.
+func maximizeLikelihood(p0 []float64, logLik func(params []float64) float64) ([]float64, error) {
+	logs := make([]float64, len(p0))
+	for i, p := range p0 {
+		logs[i] = math.Log(p)
+	}
+	x0, err := array.NewDenseWithValues(array.Shape{len(logs)}, logs, array.DefaultAttributes)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]float64, len(p0))
+	problem := optimize.Problem{
+		Func: func(x *array.Dense) float64 {
+			for i, v := range x.Data {
+				params[i] = math.Exp(v)
+			}
+			v := -logLik(params)
+			if math.IsNaN(v) {
+				return math.Inf(1)
+			}
+			return v
+		},
+	}
+	opts := optimize.DefaultOptions
+	opts.GradTol = 1e-9
+	r, err := optimize.BFGS(problem, x0, &opts)
+	// The line search fails when no further progress is possible in
+	// floating point, which happens at the optimum.
+	if err != nil && !errors.Is(err, optimize.ErrLineSearch) {
+		return nil, err
+	}
+	for i, v := range r.X.Data {
+		params[i] = math.Exp(v)
+	}
+	return params, nil
+}
+
+// solveMonotonic returns the root of the monotonic function f, expanding
+// a bracket around guess > 0 until it holds a sign change.
+func solveMonotonic(f func(float64) float64, guess float64) (float64, error) {
+	lo, hi := guess/2, guess*2
+	for i := 0; i < 100 && f(lo)*f(hi) > 0; i++ {
+		lo /= 2
+		hi *= 2
+	}
+	return optimize.Brent(f, lo, hi, nil)
+}
+
+// xlogy returns x*log(y), or zero when x is zero.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
+
+// xlog1py returns x*log(1+y), or zero when x is zero.
+func xlog1py(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log1p(y)
+}
@@ -0,0 +1,84 @@
+package stats_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/stats"
+)
+
+func draw(t *testing.T, d stats.Distribution, n int) *array.Dense {
+	t.Helper()
+	x, err := d.Rvs(array.Shape{n}, rand.New(rand.NewSource(11)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func TestFitClosedForm(t *testing.T) {
+	x := arraytest.Vector(t, 1, 2, 3, 4, 10)
+	n, err := stats.FitNormal(x)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 4, n.Mu, 1e-15)
+		assert.InDelta(t, 3.1622776601683795, n.Sigma, 1e-15)
+	}
+	e, err := stats.FitExponential(x)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.25, e.Rate, 1e-15)
+	}
+	u, err := stats.FitUniform(x)
+	if assert.Nil(t, err) {
+		assert.Equal(t, stats.Uniform{Min: 1, Max: 10}, *u)
+	}
+	p, err := stats.FitPoisson(x)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 4.0, p.Lambda)
+	}
+	b, err := stats.FitBinomial(20, x)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0.2, b.P)
+	}
+
+	_, err = stats.FitNormal(arraytest.Vector(t))
+	assert.ErrorIs(t, err, stats.ErrEmptySample)
+	_, err = stats.FitPoisson(arraytest.Vector(t, 1, 2.5))
+	assert.ErrorIs(t, err, stats.ErrInvalidSample)
+	_, err = stats.FitBinomial(3, x)
+	assert.ErrorIs(t, err, stats.ErrInvalidSample)
+	_, err = stats.FitGamma(arraytest.Vector(t, 1, 0, 2))
+	assert.ErrorIs(t, err, stats.ErrInvalidSample)
+	_, err = stats.FitBeta(arraytest.Vector(t, 0.5, 1))
+	assert.ErrorIs(t, err, stats.ErrInvalidSample)
+}
+
+func TestFitMaximumLikelihood(t *testing.T) {
+	const n = 20000
+	g, err := stats.FitGamma(draw(t, stats.Gamma{Shape: 2.5, Scale: 1.5}, n))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 2.5, g.Shape, 0.1)
+		assert.InDelta(t, 1.5, g.Scale, 0.06)
+	}
+	c, err := stats.FitChiSquared(draw(t, stats.ChiSquared{K: 3}, n))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 3, c.K, 0.06)
+	}
+	b, err := stats.FitBeta(draw(t, stats.Beta{Alpha: 2, Beta: 5}, n))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 2, b.Alpha, 0.08)
+		assert.InDelta(t, 5, b.Beta, 0.2)
+	}
+	st, err := stats.FitStudentT(draw(t, stats.StudentT{Nu: 4}, n))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 4, st.Nu, 0.3)
+	}
+	f, err := stats.FitF(draw(t, stats.F{D1: 6, D2: 12}, n))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 6, f.D1, 0.5)
+		assert.InDelta(t, 12, f.D2, 1.5)
+	}
+}
@@ -0,0 +1,163 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Gamma is the gamma distribution with shape k and scale θ, whose
+// density is x^(k-1) exp(-x/θ) / (Γ(k) θ^k) for x > 0.
+type Gamma struct {
+	Shape, Scale float64
+}
+
+// NewGamma returns a gamma distribution.  Shape and scale must be
+// positive.
+func NewGamma(shape, scale float64) (*Gamma, error) {
+	if !positive(shape, scale) {
+		return nil, ErrInvalidParameter
+	}
+	return &Gamma{Shape: shape, Scale: scale}, nil
+}
+
+func (d Gamma) logPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	k := d.Shape
+	return xlogy(k-1, x) - x/d.Scale - specfun.LGamma(k) - k*math.Log(d.Scale)
+}
+
+func (d Gamma) pdf(x float64) float64 {
+	return math.Exp(d.logPDF(x))
+}
+
+func (d Gamma) cdf(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return specfun.GammaInc(d.Shape, x/d.Scale)
+}
+
+func (d Gamma) sf(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return specfun.GammaIncC(d.Shape, x/d.Scale)
+}
+
+func (d Gamma) ppf(q float64) float64 {
+	return d.Scale * specfun.GammaIncInv(d.Shape, q)
+}
+
+// PDF returns the probability density function.
+func (d Gamma) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d Gamma) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Gamma) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Gamma) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d Gamma) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns kθ.
+func (d Gamma) Mean() float64 { return d.Shape * d.Scale }
+
+// Variance returns kθ².
+func (d Gamma) Variance() float64 { return d.Shape * d.Scale * d.Scale }
+
+// Skewness returns 2/√k.
+func (d Gamma) Skewness() float64 { return 2 / math.Sqrt(d.Shape) }
+
+// Kurtosis returns 6/k.
+func (d Gamma) Kurtosis() float64 { return 6 / d.Shape }
+
+// Rvs returns gamma variates.
+func (d Gamma) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		return d.Scale * gammaVariate(src, d.Shape)
+	})
+}
+
+// FitGamma returns the maximum likelihood estimate of the gamma
+// distribution of the items of x, which must be positive and not all
+// equal.
+func FitGamma(x *array.Dense) (*Gamma, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	mean, meanLog, err := logMoments(values)
+	if err != nil {
+		return nil, err
+	}
+	// The shape solves log(k) - ψ(k) = log(mean) - mean(log x).
+	s := math.Log(mean) - meanLog
+	if !(s > 0) {
+		return nil, ErrInvalidSample
+	}
+	guess := (3 - s + math.Sqrt((s-3)*(s-3)+24*s)) / (12 * s)
+	k, err := solveMonotonic(func(k float64) float64 {
+		return math.Log(k) - specfun.Digamma(k) - s
+	}, guess)
+	if err != nil {
+		return nil, err
+	}
+	return NewGamma(k, mean/k)
+}
+
+// logMoments returns the mean and the mean logarithm of positive
+// values.
+func logMoments(values []float64) (mean, meanLog float64, err error) {
+	for _, v := range values {
+		if !(v > 0) {
+			return 0, 0, ErrInvalidSample
+		}
+		mean += v
+		meanLog += math.Log(v)
+	}
+	n := float64(len(values))
+	return mean / n, meanLog / n, nil
+}
+
+// gammaVariate returns a variate of the gamma distribution with unit
+// scale by the method of Marsaglia and Tsang (2000).
+func gammaVariate(src Source, k float64) float64 {
+	if k < 1 {
+		// Boost the shape and rescale by U^(1/k).
+		return gammaVariate(src, k+1) * math.Pow(src.Float64(), 1/k)
+	}
+	d := k - 1./3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		var x, v float64
+		for v <= 0 {
+			x = src.NormFloat64()
+			v = 1 + c*x
+		}
+		v = v * v * v
+		u := src.Float64()
+		x2 := x * x
+		if u < 1-0.0331*x2*x2 || math.Log(u) < 0.5*x2+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/arraytest"
+	"github.com/jimmyskull/math/stats"
+)
+
+// twoSidedT2 returns the two-sided p-value of t under the t
+// distribution with two degrees of freedom.
+func twoSidedT2(t float64) float64 {
+	return 1 - math.Abs(t)/math.Sqrt(2+t*t)
+}
+
+func TestTTest(t *testing.T) {
+	x := arraytest.Vector(t, 5.1, 4.9, 5.6)
+	r, err := stats.TTest(x, 5, stats.TwoSided)
+	if assert.Nil(t, err) {
+		sd := math.Sqrt(((5.2-5.1)*(5.2-5.1) + 0.09 + 0.16) / 2)
+		stat := 0.2 / (sd / math.Sqrt(3))
+		assert.InDelta(t, stat, r.Statistic, 1e-12)
+		assert.InDelta(t, twoSidedT2(stat), r.PValue, 1e-12)
+		assert.Equal(t, 2.0, r.DoF)
+	}
+	less, _ := stats.TTest(x, 5, stats.Less)
+	greater, _ := stats.TTest(x, 5, stats.Greater)
+	assert.InDelta(t, 1, less.PValue+greater.PValue, 1e-14)
+	assert.InDelta(t, r.PValue/2, greater.PValue, 1e-14)
+
+	paired, err := stats.TTestPaired(
+		arraytest.Vector(t, 6.1, 5.9, 7.6), arraytest.Vector(t, 6, 6, 7), stats.TwoSided)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, r.Statistic, paired.Statistic, 1e-12)
+		assert.InDelta(t, r.PValue, paired.PValue, 1e-12)
+	}
+	_, err = stats.TTestPaired(x, arraytest.Vector(t, 1, 2), stats.TwoSided)
+	assert.ErrorIs(t, err, stats.ErrUnmatchedSamples)
+	_, err = stats.TTest(arraytest.Vector(t, 1), 0, stats.TwoSided)
+	assert.ErrorIs(t, err, stats.ErrTooFewObservations)
+}
+
+func TestTTestWelch(t *testing.T) {
+	x := arraytest.Vector(t, 1, 2, 3, 4)
+	y := arraytest.Vector(t, 2, 3, 4, 5)
+	r, err := stats.TTestWelch(x, y, stats.TwoSided)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, -1/math.Sqrt(5./6), r.Statistic, 1e-14)
+		assert.InDelta(t, 6, r.DoF, 1e-12)
+		cdf, _ := stats.StudentT{Nu: 6}.CDF(arraytest.Vector(t, r.Statistic))
+		assert.InDelta(t, 2*cdf.Data[0], r.PValue, 1e-14)
+	}
+	swapped, _ := stats.TTestWelch(y, x, stats.TwoSided)
+	assert.InDelta(t, -r.Statistic, swapped.Statistic, 1e-14)
+	assert.InDelta(t, r.PValue, swapped.PValue, 1e-14)
+
+	// Unequal variances reduce the degrees of freedom.
+	r, err = stats.TTestWelch(
+		arraytest.Vector(t, 1, 2, 3), arraytest.Vector(t, 0, 10, 20, 30, 40), stats.Less)
+	if assert.Nil(t, err) {
+		vx, vy := 1./3, 250./5
+		dof := (vx + vy) * (vx + vy) / (vx*vx/2 + vy*vy/4)
+		assert.InDelta(t, dof, r.DoF, 1e-12)
+		assert.InDelta(t, (2-20)/math.Sqrt(vx+vy), r.Statistic, 1e-12)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	normalSF := func(z float64) float64 { return 0.5 * math.Erfc(z/math.Sqrt2) }
+	x := arraytest.Vector(t, 1, 2, 3)
+	y := arraytest.Vector(t, 4, 5, 6, 7)
+	r, err := stats.MannWhitneyU(x, y, stats.TwoSided)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0.0, r.Statistic)
+		assert.InDelta(t, 2*normalSF((12-6-0.5)/math.Sqrt(8)), r.PValue, 1e-14)
+	}
+	r, _ = stats.MannWhitneyU(x, y, stats.Greater)
+	assert.InDelta(t, normalSF((0-6-0.5)/math.Sqrt(8)), r.PValue, 1e-14)
+
+	// Ties share their average rank and reduce the variance.
+	r, err = stats.MannWhitneyU(arraytest.Vector(t, 1, 2, 2), arraytest.Vector(t, 2, 3), stats.Less)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1.0, r.Statistic)
+		assert.InDelta(t, normalSF((5-3-0.5)/math.Sqrt(2.4)), r.PValue, 1e-14)
+	}
+}
+
+// kolmogorovSF sums the series of the Kolmogorov distribution.
+func kolmogorovSF(d, n float64) float64 {
+	z := (math.Sqrt(n) + 0.12 + 0.11/math.Sqrt(n)) * d
+	sum := 0.0
+	for k := 1.0; k <= 100; k++ {
+		sum += math.Pow(-1, k-1) * math.Exp(-2*k*k*z*z)
+	}
+	return 2 * sum
+}
+
+func TestKSTest(t *testing.T) {
+	r, err := stats.KSTest(arraytest.Vector(t, 0.7, 0.1, 0.4), stats.Uniform{Min: 0, Max: 1})
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.3, r.Statistic, 1e-15)
+		assert.InDelta(t, kolmogorovSF(0.3, 3), r.PValue, 1e-6)
+	}
+	values := make([]float64, 200)
+	stat := 0.0
+	for i := range values {
+		values[i] = math.Pow(float64(i)/200, 2)
+		stat = math.Max(stat, float64(i+1)/200-values[i])
+	}
+	r, err = stats.KSTest(arraytest.Vector(t, values...), stats.Uniform{Min: 0, Max: 1})
+	if assert.Nil(t, err) {
+		assert.InDelta(t, stat, r.Statistic, 1e-15)
+		assert.InDelta(t, kolmogorovSF(stat, 200), r.PValue, 1e-12)
+		assert.Less(t, r.PValue, 1e-9)
+	}
+
+	r, err = stats.KSTest2(arraytest.Vector(t, 3, 1, 2), arraytest.Vector(t, 2.5, 4, 5, 6))
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.75, r.Statistic, 1e-15)
+		assert.InDelta(t, kolmogorovSF(0.75, 12./7), r.PValue, 1e-6)
+	}
+	r, _ = stats.KSTest2(arraytest.Vector(t, 1, 2, 2, 3), arraytest.Vector(t, 1, 2, 2, 3))
+	assert.Equal(t, stats.TestResult{Statistic: 0, PValue: 1}, r)
+}
+
+func TestChiSquareContingency(t *testing.T) {
+	observed, _ := array.NewDenseWithValues(array.Shape{2, 2},
+		[]float64{10, 20, 30, 40}, array.DefaultAttributes)
+	inv := 1./12 + 1./18 + 1./28 + 1./42
+	r, err := stats.ChiSquareContingency(observed, true)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 2.25*inv, r.Statistic, 1e-14)
+		assert.InDelta(t, math.Erfc(math.Sqrt(r.Statistic/2)), r.PValue, 1e-14)
+		assert.Equal(t, 1.0, r.DoF)
+		assert.Equal(t, []float64{12, 18, 28, 42}, r.Expected.Values())
+	}
+	r, err = stats.ChiSquareContingency(observed, false)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 4*inv, r.Statistic, 1e-14)
+	}
+	table, _ := array.NewDenseWithValues(array.Shape{2, 3},
+		[]float64{10, 10, 20, 20, 20, 20}, array.DefaultAttributes)
+	r, err = stats.ChiSquareContingency(table, true)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2.0, r.DoF)
+		// The chi-squared survival function with two degrees of freedom
+		// is exp(-x/2).
+		assert.InDelta(t, math.Exp(-r.Statistic/2), r.PValue, 1e-14)
+	}
+	table.Data[0] = -1
+	_, err = stats.ChiSquareContingency(table, true)
+	assert.ErrorIs(t, err, stats.ErrInvalidTable)
+	_, err = stats.ChiSquareContingency(arraytest.Vector(t, 1, 2), true)
+	assert.ErrorIs(t, err, stats.ErrInvalidTable)
+}
+
+func TestCorrelation(t *testing.T) {
+	x := arraytest.Vector(t, 1, 2, 3, 4)
+	y := arraytest.Vector(t, 2, 1, 4, 3)
+	tstat := 0.6 * math.Sqrt(2/0.64)
+	for _, fn := range []func(x, y *array.Dense, alt stats.Alternative) (stats.TestResult, error){
+		stats.Pearson, stats.Spearman,
+	} {
+		r, err := fn(x, y, stats.TwoSided)
+		if assert.Nil(t, err) {
+			assert.InDelta(t, 0.6, r.Statistic, 1e-14)
+			assert.InDelta(t, twoSidedT2(tstat), r.PValue, 1e-12)
+		}
+	}
+	// Spearman depends only on the ranks.
+	r, _ := stats.Spearman(
+		arraytest.Vector(t, 1, 20, 300, 4000), arraytest.Vector(t, -5, -9, 0, -1), stats.TwoSided)
+	assert.InDelta(t, 0.6, r.Statistic, 1e-14)
+	r, _ = stats.Pearson(x, arraytest.Vector(t, 8, 6, 4, 2), stats.TwoSided)
+	assert.Equal(t, -1.0, r.Statistic)
+	assert.Equal(t, 0.0, r.PValue)
+
+	r, err := stats.Kendall(
+		arraytest.Vector(t, 1, 2, 3, 4, 5), arraytest.Vector(t, 3, 1, 2, 5, 4), stats.TwoSided)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 0.4, r.Statistic, 1e-15)
+		assert.InDelta(t, math.Erfc(4/math.Sqrt(50./3)/math.Sqrt2), r.PValue, 1e-14)
+	}
+	// With ties, tau-b is normalized by the number of untied pairs.
+	r, err = stats.Kendall(
+		arraytest.Vector(t, 1, 1, 2, 3), arraytest.Vector(t, 1, 2, 2, 3), stats.TwoSided)
+	if assert.Nil(t, err) {
+		assert.InDelta(t, 4/math.Sqrt(5*5), r.Statistic, 1e-15)
+	}
+	_, err = stats.Kendall(x, arraytest.Vector(t, 1), stats.TwoSided)
+	assert.ErrorIs(t, err, stats.ErrUnmatchedSamples)
+}
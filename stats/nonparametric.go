@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// rank returns the ranks of values starting at one, tied values sharing
+// the average of their ranks, and the sizes of the groups of ties.
+func rank(values []float64) (ranks []float64, ties []int) {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+	ranks = make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i + 1
+		for j < len(order) && values[order[j]] == values[order[i]] {
+			j++
+		}
+		avg := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			ranks[order[k]] = avg
+		}
+		if j-i > 1 {
+			ties = append(ties, j-i)
+		}
+		i = j
+	}
+	return ranks, ties
+}
+
+// MannWhitneyU performs the Mann-Whitney U test of the null hypothesis
+// that two independent samples come from the same distribution.  The
+// statistic is the U statistic of x, and the p-value is given by the
+// normal approximation with tie and continuity corrections.
+func MannWhitneyU(x, y *array.Dense, alt Alternative) (TestResult, error) {
+	xs, err := observations(x)
+	if err != nil {
+		return TestResult{}, err
+	}
+	ys, err := observations(y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	n1, n2 := float64(len(xs)), float64(len(ys))
+	ranks, ties := rank(append(append([]float64(nil), xs...), ys...))
+	r1 := 0.0
+	for _, r := range ranks[:len(xs)] {
+		r1 += r
+	}
+	u1 := r1 - n1*(n1+1)/2
+	u2 := n1*n2 - u1
+	n := n1 + n2
+	tieTerm := 0.0
+	for _, t := range ties {
+		ft := float64(t)
+		tieTerm += ft*ft*ft - ft
+	}
+	mean := n1 * n2 / 2
+	sd := math.Sqrt(n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1))))
+	u := u1
+	switch alt {
+	case TwoSided:
+		u = math.Max(u1, u2)
+	case Less:
+		u = u2
+	}
+	z := (u - mean - 0.5) / sd
+	p := Normal{Sigma: 1}.sf(z)
+	if alt == TwoSided {
+		p = math.Min(1, 2*p)
+	}
+	return TestResult{Statistic: u1, PValue: p}, nil
+}
+
+// KSTest performs the two-sided one-sample Kolmogorov-Smirnov test of
+// the null hypothesis that the items of x are drawn from d.  The
+// statistic is the largest distance between the empirical and the
+// hypothesized distribution functions, and the p-value is given by the
+// asymptotic Kolmogorov distribution with the correction of Stephens
+// (1970).
+func KSTest(x *array.Dense, d Distribution) (TestResult, error) {
+	values, err := observations(x)
+	if err != nil {
+		return TestResult{}, err
+	}
+	sort.Float64s(values)
+	sorted, err := array.NewDenseWithValues(
+		array.Shape{len(values)}, values, array.DefaultAttributes)
+	if err != nil {
+		return TestResult{}, err
+	}
+	cdf, err := d.CDF(sorted)
+	if err != nil {
+		return TestResult{}, err
+	}
+	n := float64(len(values))
+	stat := 0.0
+	for i, f := range cdf.Data {
+		stat = math.Max(stat, math.Max(float64(i+1)/n-f, f-float64(i)/n))
+	}
+	return TestResult{Statistic: stat, PValue: kolmogorov(stat, n)}, nil
+}
+
+// KSTest2 performs the two-sided two-sample Kolmogorov-Smirnov test of
+// the null hypothesis that two independent samples come from the same
+// distribution.  The p-value is asymptotic, as in KSTest.
+func KSTest2(x, y *array.Dense) (TestResult, error) {
+	xs, err := observations(x)
+	if err != nil {
+		return TestResult{}, err
+	}
+	ys, err := observations(y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	sort.Float64s(xs)
+	sort.Float64s(ys)
+	n1, n2 := float64(len(xs)), float64(len(ys))
+	stat := 0.0
+	i, j := 0, 0
+	for i < len(xs) && j < len(ys) {
+		v := math.Min(xs[i], ys[j])
+		for i < len(xs) && xs[i] == v {
+			i++
+		}
+		for j < len(ys) && ys[j] == v {
+			j++
+		}
+		stat = math.Max(stat, math.Abs(float64(i)/n1-float64(j)/n2))
+	}
+	return TestResult{Statistic: stat, PValue: kolmogorov(stat, n1*n2/(n1+n2))}, nil
+}
+
+// kolmogorov returns the asymptotic probability that the
+// Kolmogorov-Smirnov statistic of a sample of effective size n exceeds
+// d.
+func kolmogorov(d, n float64) float64 {
+	sn := math.Sqrt(n)
+	z := (sn + 0.12 + 0.11/sn) * d
+	switch {
+	case z == 0:
+		return 1
+	case z < 1.18:
+		y := math.Exp(-math.Pi * math.Pi / (8 * z * z))
+		p := math.Sqrt(2*math.Pi) / z *
+			(y + math.Pow(y, 9) + math.Pow(y, 25) + math.Pow(y, 49))
+		return 1 - p
+	}
+	x := math.Exp(-2 * z * z)
+	return math.Max(0, 2*(x-math.Pow(x, 4)+math.Pow(x, 9)))
+}
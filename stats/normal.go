@@ -0,0 +1,107 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Normal is the normal distribution with mean Mu and standard deviation
+// Sigma.
+type Normal struct {
+	Mu, Sigma float64
+}
+
+// NewNormal returns a normal distribution.  Sigma must be positive.
+func NewNormal(mu, sigma float64) (*Normal, error) {
+	if math.IsNaN(mu) || math.IsInf(mu, 0) || !positive(sigma) {
+		return nil, ErrInvalidParameter
+	}
+	return &Normal{Mu: mu, Sigma: sigma}, nil
+}
+
+func (d Normal) logPDF(x float64) float64 {
+	z := (x - d.Mu) / d.Sigma
+	return -0.5*z*z - math.Log(d.Sigma) - 0.5*math.Log(2*math.Pi)
+}
+
+func (d Normal) pdf(x float64) float64 {
+	return math.Exp(d.logPDF(x))
+}
+
+func (d Normal) cdf(x float64) float64 {
+	return 0.5 * math.Erfc(-(x-d.Mu)/(d.Sigma*math.Sqrt2))
+}
+
+func (d Normal) sf(x float64) float64 {
+	return 0.5 * math.Erfc((x-d.Mu)/(d.Sigma*math.Sqrt2))
+}
+
+func (d Normal) ppf(q float64) float64 {
+	if q < 0 || q > 1 {
+		return math.NaN()
+	}
+	return d.Mu + d.Sigma*specfun.NormalQuantile(q)
+}
+
+// PDF returns the probability density function.
+func (d Normal) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d Normal) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Normal) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Normal) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d Normal) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns Mu.
+func (d Normal) Mean() float64 { return d.Mu }
+
+// Variance returns Sigma².
+func (d Normal) Variance() float64 { return d.Sigma * d.Sigma }
+
+// Skewness returns zero.
+func (d Normal) Skewness() float64 { return 0 }
+
+// Kurtosis returns zero.
+func (d Normal) Kurtosis() float64 { return 0 }
+
+// Rvs returns normal variates.
+func (d Normal) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		return d.Mu + d.Sigma*src.NormFloat64()
+	})
+}
+
+// FitNormal returns the maximum likelihood estimate of the normal
+// distribution of the items of x: their mean and their standard
+// deviation with divisor n.
+func FitNormal(x *array.Dense) (*Normal, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	mean := meanOf(values)
+	ss := 0.0
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return NewNormal(mean, math.Sqrt(ss/float64(len(values))))
+}
@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// Poisson is the Poisson distribution with mean Lambda.
+type Poisson struct {
+	Lambda float64
+}
+
+// NewPoisson returns a Poisson distribution.  The mean must be finite
+// and non-negative.
+func NewPoisson(lambda float64) (*Poisson, error) {
+	if !(lambda >= 0) || math.IsInf(lambda, 1) {
+		return nil, ErrInvalidParameter
+	}
+	return &Poisson{Lambda: lambda}, nil
+}
+
+func (d Poisson) logPMF(k float64) float64 {
+	switch {
+	case math.IsNaN(k):
+		return k
+	case k < 0 || !isInteger(k):
+		return math.Inf(-1)
+	}
+	return xlogy(k, d.Lambda) - d.Lambda - specfun.LGamma(k+1)
+}
+
+func (d Poisson) pmf(k float64) float64 {
+	return math.Exp(d.logPMF(k))
+}
+
+func (d Poisson) cdf(x float64) float64 {
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x < 0:
+		return 0
+	case math.IsInf(x, 1) || d.Lambda == 0:
+		return 1
+	}
+	return specfun.GammaIncC(math.Floor(x)+1, d.Lambda)
+}
+
+func (d Poisson) sf(x float64) float64 {
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x < 0:
+		return 1
+	case math.IsInf(x, 1) || d.Lambda == 0:
+		return 0
+	}
+	return specfun.GammaInc(math.Floor(x)+1, d.Lambda)
+}
+
+func (d Poisson) ppf(q float64) float64 {
+	return discreteQuantile(d, q, d.cdf, math.Inf(1))
+}
+
+// PMF returns the probability mass function.
+func (d Poisson) PMF(k *array.Dense) (*array.Dense, error) {
+	return array.Map(k, d.pmf)
+}
+
+// LogPMF returns the natural logarithm of the probability mass
+// function.
+func (d Poisson) LogPMF(k *array.Dense) (*array.Dense, error) {
+	return array.Map(k, d.logPMF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Poisson) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Poisson) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function, the smallest k with
+// CDF(k) >= q.
+func (d Poisson) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns λ.
+func (d Poisson) Mean() float64 { return d.Lambda }
+
+// Variance returns λ.
+func (d Poisson) Variance() float64 { return d.Lambda }
+
+// Skewness returns 1/√λ.
+func (d Poisson) Skewness() float64 { return 1 / math.Sqrt(d.Lambda) }
+
+// Kurtosis returns 1/λ.
+func (d Poisson) Kurtosis() float64 { return 1 / d.Lambda }
+
+// Rvs returns Poisson variates by inversion.
+func (d Poisson) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		return d.ppf(src.Float64())
+	})
+}
+
+// FitPoisson returns the maximum likelihood estimate of the Poisson
+// distribution of the items of x, their mean.  The items must be
+// non-negative integers.
+func FitPoisson(x *array.Dense) (*Poisson, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if v < 0 || !isInteger(v) {
+			return nil, ErrInvalidSample
+		}
+	}
+	return NewPoisson(meanOf(values))
+}
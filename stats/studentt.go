@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+	"github.com/jimmyskull/math/internal/specfun"
+)
+
+// StudentT is Student's t distribution with Nu degrees of freedom.
+type StudentT struct {
+	Nu float64
+}
+
+// NewStudentT returns a t distribution.  The degrees of freedom must be
+// positive.
+func NewStudentT(nu float64) (*StudentT, error) {
+	if !positive(nu) {
+		return nil, ErrInvalidParameter
+	}
+	return &StudentT{Nu: nu}, nil
+}
+
+func (d StudentT) logPDF(x float64) float64 {
+	nu := d.Nu
+	return specfun.LGamma((nu+1)/2) - specfun.LGamma(nu/2) -
+		0.5*math.Log(nu*math.Pi) - (nu+1)/2*math.Log1p(x*x/nu)
+}
+
+func (d StudentT) pdf(x float64) float64 {
+	return math.Exp(d.logPDF(x))
+}
+
+func (d StudentT) cdf(x float64) float64 {
+	if math.IsNaN(x) {
+		return x
+	}
+	// P(|T| > |x|) = I_{ν/(ν+x²)}(ν/2, 1/2).
+	tail := 0.5 * specfun.BetaInc(d.Nu/2, 0.5, d.Nu/(d.Nu+x*x))
+	if x > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+func (d StudentT) sf(x float64) float64 {
+	return d.cdf(-x)
+}
+
+func (d StudentT) ppf(q float64) float64 {
+	switch {
+	case math.IsNaN(q) || q < 0 || q > 1:
+		return math.NaN()
+	case q == 0.5:
+		return 0
+	case q > 0.5:
+		return -d.ppf(1 - q)
+	}
+	y := specfun.BetaIncInv(d.Nu/2, 0.5, 2*q)
+	return -math.Sqrt(d.Nu * (1 - y) / y)
+}
+
+// PDF returns the probability density function.
+func (d StudentT) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d StudentT) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d StudentT) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d StudentT) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d StudentT) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns zero for Nu > 1.
+func (d StudentT) Mean() float64 {
+	if d.Nu <= 1 {
+		return math.NaN()
+	}
+	return 0
+}
+
+// Variance returns Nu/(Nu-2) for Nu > 2 and +Inf for 1 < Nu <= 2.
+func (d StudentT) Variance() float64 {
+	switch {
+	case d.Nu > 2:
+		return d.Nu / (d.Nu - 2)
+	case d.Nu > 1:
+		return math.Inf(1)
+	}
+	return math.NaN()
+}
+
+// Skewness returns zero for Nu > 3.
+func (d StudentT) Skewness() float64 {
+	if d.Nu <= 3 {
+		return math.NaN()
+	}
+	return 0
+}
+
+// Kurtosis returns 6/(Nu-4) for Nu > 4 and +Inf for 2 < Nu <= 4.
+func (d StudentT) Kurtosis() float64 {
+	switch {
+	case d.Nu > 4:
+		return 6 / (d.Nu - 4)
+	case d.Nu > 2:
+		return math.Inf(1)
+	}
+	return math.NaN()
+}
+
+// Rvs returns t variates.
+func (d StudentT) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		chi2 := 2 * gammaVariate(src, d.Nu/2)
+		return src.NormFloat64() / math.Sqrt(chi2/d.Nu)
+	})
+}
+
+// FitStudentT returns the maximum likelihood estimate of the t
+// distribution of the items of x.
+func FitStudentT(x *array.Dense) (*StudentT, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	params, err := maximizeLikelihood([]float64{5}, func(p []float64) float64 {
+		d := StudentT{Nu: p[0]}
+		sum := 0.0
+		for _, v := range values {
+			sum += d.logPDF(v)
+		}
+		return sum / float64(len(values))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewStudentT(params[0])
+}
@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Alternative is the alternative hypothesis of a test.
+type Alternative int
+
+const (
+	// TwoSided tests whether the statistic differs from its value under
+	// the null hypothesis in either direction.
+	TwoSided Alternative = iota
+
+	// Less tests whether the statistic is smaller than under the null
+	// hypothesis.
+	Less
+
+	// Greater tests whether the statistic is larger than under the null
+	// hypothesis.
+	Greater
+)
+
+// TestResult is the outcome of a hypothesis test.
+type TestResult struct {
+	Statistic float64
+	PValue    float64
+
+	// DoF is the number of degrees of freedom of the reference
+	// distribution, or zero when it has none.
+	DoF float64
+}
+
+// tails is a distribution with scalar tail probabilities.
+type tails interface {
+	cdf(x float64) float64
+	sf(x float64) float64
+}
+
+// pValue returns the probability under the null distribution d of a
+// statistic at least as extreme as stat.
+func pValue(d tails, stat float64, alt Alternative) float64 {
+	switch alt {
+	case Less:
+		return d.cdf(stat)
+	case Greater:
+		return d.sf(stat)
+	}
+	return math.Min(1, 2*math.Min(d.cdf(stat), d.sf(stat)))
+}
+
+// TTest performs the one-sample t-test of the null hypothesis that the
+// items of x have mean mu.
+func TTest(x *array.Dense, mu float64, alt Alternative) (TestResult, error) {
+	values, err := observations(x)
+	if err != nil {
+		return TestResult{}, err
+	}
+	return oneSampleT(values, mu, alt)
+}
+
+// TTestPaired performs the t-test of the null hypothesis that paired
+// observations x and y have equal means, the one-sample t-test of their
+// differences x - y.
+func TTestPaired(x, y *array.Dense, alt Alternative) (TestResult, error) {
+	xs, ys, err := pairs(x, y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	diff := make([]float64, len(xs))
+	for i := range diff {
+		diff[i] = xs[i] - ys[i]
+	}
+	return oneSampleT(diff, 0, alt)
+}
+
+// TTestWelch performs Welch's t-test of the null hypothesis that two
+// independent samples have equal means, without assuming equal
+// variances.  The degrees of freedom are given by the Welch-Satterthwaite
+// equation.
+func TTestWelch(x, y *array.Dense, alt Alternative) (TestResult, error) {
+	xs, err := observations(x)
+	if err != nil {
+		return TestResult{}, err
+	}
+	ys, err := observations(y)
+	if err != nil {
+		return TestResult{}, err
+	}
+	if len(xs) < 2 || len(ys) < 2 {
+		return TestResult{}, ErrTooFewObservations
+	}
+	vx := variance(xs, 1) / float64(len(xs))
+	vy := variance(ys, 1) / float64(len(ys))
+	se := vx + vy
+	dof := se * se / (vx*vx/float64(len(xs)-1) + vy*vy/float64(len(ys)-1))
+	t := (meanOf(xs) - meanOf(ys)) / math.Sqrt(se)
+	return TestResult{
+		Statistic: t,
+		PValue:    pValue(StudentT{Nu: dof}, t, alt),
+		DoF:       dof,
+	}, nil
+}
+
+func oneSampleT(values []float64, mu float64, alt Alternative) (TestResult, error) {
+	n := len(values)
+	if n < 2 {
+		return TestResult{}, ErrTooFewObservations
+	}
+	t := (meanOf(values) - mu) / math.Sqrt(variance(values, 1)/float64(n))
+	dof := float64(n - 1)
+	return TestResult{
+		Statistic: t,
+		PValue:    pValue(StudentT{Nu: dof}, t, alt),
+		DoF:       dof,
+	}, nil
+}
+
+// pairs returns the items of two samples of the same size.
+func pairs(x, y *array.Dense) ([]float64, []float64, error) {
+	xs, err := observations(x)
+	if err != nil {
+		return nil, nil, err
+	}
+	ys, err := observations(y)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(xs) != len(ys) {
+		return nil, nil, ErrUnmatchedSamples
+	}
+	return xs, ys, nil
+}
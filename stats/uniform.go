@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jimmyskull/math/array"
+)
+
+// Uniform is the continuous uniform distribution on [Min, Max].
+type Uniform struct {
+	Min, Max float64
+}
+
+// NewUniform returns a uniform distribution.  The bounds must be finite
+// and Min must be less than Max.
+func NewUniform(min, max float64) (*Uniform, error) {
+	if math.IsInf(min, 0) || math.IsInf(max, 0) || !(min < max) {
+		return nil, ErrInvalidParameter
+	}
+	return &Uniform{Min: min, Max: max}, nil
+}
+
+func (d Uniform) pdf(x float64) float64 {
+	if math.IsNaN(x) {
+		return x
+	}
+	if x < d.Min || x > d.Max {
+		return 0
+	}
+	return 1 / (d.Max - d.Min)
+}
+
+func (d Uniform) logPDF(x float64) float64 {
+	return math.Log(d.pdf(x))
+}
+
+func (d Uniform) cdf(x float64) float64 {
+	return math.Max(0, math.Min(1, (x-d.Min)/(d.Max-d.Min)))
+}
+
+func (d Uniform) sf(x float64) float64 {
+	return math.Max(0, math.Min(1, (d.Max-x)/(d.Max-d.Min)))
+}
+
+func (d Uniform) ppf(q float64) float64 {
+	if q < 0 || q > 1 {
+		return math.NaN()
+	}
+	return d.Min + q*(d.Max-d.Min)
+}
+
+// PDF returns the probability density function.
+func (d Uniform) PDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.pdf)
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function.
+func (d Uniform) LogPDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.logPDF)
+}
+
+// CDF returns the cumulative distribution function.
+func (d Uniform) CDF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.cdf)
+}
+
+// SF returns the survival function.
+func (d Uniform) SF(x *array.Dense) (*array.Dense, error) {
+	return array.Map(x, d.sf)
+}
+
+// PPF returns the percent point function.
+func (d Uniform) PPF(q *array.Dense) (*array.Dense, error) {
+	return array.Map(q, d.ppf)
+}
+
+// Mean returns the midpoint of the interval.
+func (d Uniform) Mean() float64 { return (d.Min + d.Max) / 2 }
+
+// Variance returns (Max-Min)²/12.
+func (d Uniform) Variance() float64 {
+	w := d.Max - d.Min
+	return w * w / 12
+}
+
+// Skewness returns zero.
+func (d Uniform) Skewness() float64 { return 0 }
+
+// Kurtosis returns -6/5.
+func (d Uniform) Kurtosis() float64 { return -1.2 }
+
+// Rvs returns uniform variates.
+func (d Uniform) Rvs(shape array.Shape, src Source) (*array.Dense, error) {
+	return sample(shape, src, func(src Source) float64 {
+		return d.Min + src.Float64()*(d.Max-d.Min)
+	})
+}
+
+// FitUniform returns the maximum likelihood estimate of the uniform
+// distribution of the items of x, the interval between the smallest and
+// the largest item.  The items must not all be equal.
+func FitUniform(x *array.Dense) (*Uniform, error) {
+	values, err := observations(x)
+	if err != nil {
+		return nil, err
+	}
+	min, max := values[0], values[0]
+	for _, v := range values[1:] {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	if min == max {
+		return nil, ErrInvalidSample
+	}
+	return NewUniform(min, max)
+}